@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+
+	"github.com/vgrigalashvili/veemon/internal/config"
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/service"
+	"github.com/vgrigalashvili/veemon/pkg/mqtt"
+	"golang.org/x/sync/errgroup"
+)
+
+// routeMQTT routes the events lifts publish on Lift/<device ID>/events/<kind>,
+// and their replies to commands, to the services handling them. Each topic
+// has its own payload schema.
+func routeMQTT(router *mqtt.Router, deviceService *service.DeviceService, eventService *service.DeviceEventService, commandService *service.DeviceCommandService) {
+	router.Handle("Lift/+/events/heartbeat", mqtt.JSON(func(ctx *mqtt.Context, heartbeat domain.Heartbeat) error {
+		return deviceService.AcceptHeartbeat(ctx.DeviceID, heartbeat)
+	}))
+	router.Handle("Lift/+/events/metrics", mqtt.JSON(func(ctx *mqtt.Context, report domain.MetricsReport) error {
+		return deviceService.AcceptMetrics(ctx.DeviceID, report)
+	}))
+	router.Handle("Lift/+/events/door", mqtt.JSON(func(ctx *mqtt.Context, door domain.DoorEvent) error {
+		return eventService.AcceptDoor(ctx.DeviceID, door)
+	}))
+	router.Handle("Lift/+/events/fault", mqtt.JSON(func(ctx *mqtt.Context, fault domain.FaultEvent) error {
+		return eventService.AcceptFault(ctx.DeviceID, fault)
+	}))
+	router.Handle("Lift/+/events/trip", mqtt.JSON(func(ctx *mqtt.Context, trip domain.TripEvent) error {
+		return eventService.AcceptTrip(ctx.DeviceID, trip)
+	}))
+	router.Handle("Lift/+/events/alarm", mqtt.JSON(func(ctx *mqtt.Context, alarm domain.AlarmEvent) error {
+		return eventService.AcceptAlarm(ctx.DeviceID, alarm)
+	}))
+	router.Handle("Lift/+/commands/+/reply", mqtt.JSON(func(ctx *mqtt.Context, reply domain.CommandReply) error {
+		return commandService.AcceptReply(ctx.DeviceID, ctx.Wildcards[1], reply)
+	}))
+}
+
+// newMQTTClient creates the client of the MQTT broker configured in ac.
+func newMQTTClient(ac config.AppConfig, router *mqtt.Router) (mqtt.Client, error) {
+	return mqtt.NewClient(mqtt.Config{
+		BrokerURL:            ac.MQTTBrokerURL,
+		ClientID:             ac.MQTTClientID,
+		Username:             ac.MQTTUsername,
+		Password:             ac.MQTTPassword,
+		CAFile:               ac.MQTTCAFile,
+		KeepAlive:            ac.MQTTKeepAlive,
+		QoS:                  ac.MQTTQoS,
+		MaxReconnectInterval: ac.MQTTMaxReconnectInterval,
+	}, router)
+}
+
+// runMQTTClient keeps the connection to the MQTT broker up until the server
+// shuts down, then disconnects.
+func runMQTTClient(ctx context.Context, waitGroup *errgroup.Group, client mqtt.Client) {
+	waitGroup.Go(func() error {
+		return client.Run(ctx)
+	})
+}
@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"errors"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/vgrigalashvili/veemon/api/rest"
+	"github.com/vgrigalashvili/veemon/api/rest/middleware"
 	"github.com/vgrigalashvili/veemon/internal/dto"
 	"github.com/vgrigalashvili/veemon/internal/repository"
 	"github.com/vgrigalashvili/veemon/internal/service"
@@ -24,9 +27,12 @@ func InitializeAuthHandler(rh *rest.RestHandler) {
 	authRequests := rh.API.Group("api/auth")
 
 	validator := validator.NewValidator()
-	userRepository := repository.NewUserRepository(rh.Querier)
-	userService := service.NewUserService(userRepository)
-	authService := service.NewAuthService(rh.Token, userService)
+	userRepository := repository.NewUserRepository(rh.Store)
+	sessionRepository := repository.NewSessionRepository(rh.Store)
+	verifyEmailRepository := repository.NewVerifyEmailRepository(rh.Store)
+	passwordResetRepository := repository.NewPasswordResetRepository(rh.Store)
+	userService := service.NewUserService(userRepository, rh.PasswordPolicy, rh.TaskDistributor, rh.Denylist, rh.Config.DefaultCallingCode)
+	authService := service.NewAuthService(rh.Token, rh.Denylist, userService, sessionRepository, verifyEmailRepository, passwordResetRepository, rh.TaskDistributor, rh.Config.AccessTokenDuration, rh.Config.RefreshTokenDuration)
 	authHandler := &AuthHandler{
 		authService: authService,
 		validator:   validator,
@@ -34,7 +40,16 @@ func InitializeAuthHandler(rh *rest.RestHandler) {
 
 	// public
 	authRequests.Post("/sign-up", authHandler.signUp)
-	// api.Post("/sign-in", authHandler.signIn)
+	authRequests.Post("/sign-in", authHandler.signIn)
+	authRequests.Post("/refresh", authHandler.refresh)
+	authRequests.Get("/verify-email", authHandler.verifyEmail)
+	authRequests.Post("/forgot-password", authHandler.forgotPassword)
+	authRequests.Post("/reset-password", authHandler.resetPassword)
+
+	// protected
+	authMiddleware := middleware.AuthMiddleware(rh.Token, rh.Denylist)
+	authRequests.Post("/logout", authMiddleware, authHandler.logout)
+	authRequests.Post("/logout-all", authMiddleware, authHandler.logoutAll)
 
 }
 
@@ -72,15 +87,27 @@ func (ah *AuthHandler) signUp(ctx *fiber.Ctx) error {
 	result, err := ah.authService.HandleSignUpProcesses(ctx, request)
 	if err != nil {
 		log.Printf("[ERROR] failed to sign up: %v", err)
-		if err.Error() == "user with this mobile already exists" {
+		if errors.Is(err, helper.ErrWeakPassword) {
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    err.Error(),
+			})
+		}
+		if errors.Is(err, repository.ErrUserAlreadyExists) {
 			return ctx.Status(http.StatusConflict).JSON(&fiber.Map{
 				"success": false,
 				"data":    "you already have registered with this mobile",
 			})
 		}
+		if errors.Is(err, repository.ErrEmailAlreadyExists) {
+			return ctx.Status(http.StatusConflict).JSON(&fiber.Map{
+				"success": false,
+				"data":    "you already have registered with this email",
+			})
+		}
 		return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
 			"success": false,
-			"data":    "something went wrong:" + err.Error(),
+			"data":    "something went wrong.",
 		})
 	}
 
@@ -90,26 +117,308 @@ func (ah *AuthHandler) signUp(ctx *fiber.Ctx) error {
 	})
 }
 
-// func (uh *AuthHandler) signIn(ctx *fiber.Ctx) error {
-// 	var credentials dto.AuthSignIn
-// 	if err := ctx.BodyParser(&credentials); err != nil {
-// 		log.Printf("[ERROR] invalid request body: %v", err)
-// 		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
-// 			"success": false,
-// 			"data":    ErrInvalidRequestJSON.Error(),
-// 		})
-// 	}
-// 	token, err := uh.authService.SignIn(credentials)
-// 	if err != nil {
-
-// 		return ctx.Status(http.StatusUnauthorized).JSON(&fiber.Map{
-// 			"success": false,
-// 			"data":    "invalid credentials.",
-// 		})
-// 	}
-
-// 	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
-// 		"success": true,
-// 		"data":    token,
-// 	})
-// }
+// @Summary Sign In
+// @Description Authenticates a user by email and password and returns an access and refresh token.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param credentials body dto.AuthSignIn true "Sign In Credentials"
+// @Success 200 {object} dto.StandardResponse
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /api/auth/sign-in [post]
+func (ah *AuthHandler) signIn(ctx *fiber.Ctx) error {
+	var credentials dto.AuthSignIn
+	if err := ctx.BodyParser(&credentials); err != nil {
+		log.Printf("[ERROR] invalid request body: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidRequestJSON.Error(),
+		})
+	}
+
+	credentials.Email = helper.NormalizeEmail(credentials.Email)
+
+	if err := ah.validator.ValidateStruct(&credentials); err != nil {
+		log.Printf("[ERROR] validation failed: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    "Validation error: " + err.Error(),
+		})
+	}
+
+	tokens, err := ah.authService.SignIn(credentials, ctx.Get("User-Agent"), ctx.IP())
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			return ctx.Status(http.StatusUnauthorized).JSON(&fiber.Map{
+				"success": false,
+				"data":    "invalid credentials.",
+			})
+		}
+		log.Printf("[ERROR] failed to sign in: %v", err)
+		return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
+			"success": false,
+			"data":    "something went wrong.",
+		})
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    tokens,
+	})
+}
+
+// @Summary Refresh Tokens
+// @Description Rotates a refresh token and returns a new access and refresh token. Reusing a rotated refresh token revokes the whole session.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param refresh body dto.AuthRefresh true "Refresh Token"
+// @Success 200 {object} dto.StandardResponse
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /api/auth/refresh [post]
+func (ah *AuthHandler) refresh(ctx *fiber.Ctx) error {
+	var request dto.AuthRefresh
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Printf("[ERROR] invalid request body: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidRequestJSON.Error(),
+		})
+	}
+
+	if err := ah.validator.ValidateStruct(&request); err != nil {
+		log.Printf("[ERROR] validation failed: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    "Validation error: " + err.Error(),
+		})
+	}
+
+	tokens, err := ah.authService.Refresh(request, ctx.Get("User-Agent"), ctx.IP())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidToken), errors.Is(err, service.ErrExpiredToken):
+			return ctx.Status(http.StatusUnauthorized).JSON(&fiber.Map{
+				"success": false,
+				"data":    ErrInvalidOrExpiredToken.Error(),
+			})
+		case errors.Is(err, service.ErrRefreshTokenReused):
+			return ctx.Status(http.StatusUnauthorized).JSON(&fiber.Map{
+				"success": false,
+				"data":    "refresh token was already used, please sign in again.",
+			})
+		}
+		log.Printf("[ERROR] failed to refresh tokens: %v", err)
+		return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
+			"success": false,
+			"data":    "something went wrong.",
+		})
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    tokens,
+	})
+}
+
+// @Summary Verify Email
+// @Description Confirms the email address of a user with the link sent after sign-up.
+// @Tags Auth
+// @Produce json
+// @Param email_id query int true "Verification ID"
+// @Param secret_code query string true "Secret Code"
+// @Success 200 {object} dto.StandardResponse
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /api/auth/verify-email [get]
+func (ah *AuthHandler) verifyEmail(ctx *fiber.Ctx) error {
+	emailID, err := strconv.ParseInt(ctx.Query("email_id"), 10, 64)
+	secretCode := ctx.Query("secret_code")
+	if err != nil || secretCode == "" {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidQueryParam.Error(),
+		})
+	}
+
+	if _, err := ah.authService.VerifyEmail(emailID, secretCode); err != nil {
+		if errors.Is(err, repository.ErrInvalidVerifyEmail) {
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    err.Error(),
+			})
+		}
+		return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
+			"success": false,
+			"data":    "something went wrong.",
+		})
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    "email verified.",
+	})
+}
+
+// @Summary Forgot Password
+// @Description Sends a single-use password reset code to the email if it belongs to a user.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body dto.AuthForgotPassword true "Email"
+// @Success 202 {object} dto.StandardResponse
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /api/auth/forgot-password [post]
+func (ah *AuthHandler) forgotPassword(ctx *fiber.Ctx) error {
+	var request dto.AuthForgotPassword
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Printf("[ERROR] invalid request body: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidRequestJSON.Error(),
+		})
+	}
+
+	request.Email = helper.NormalizeEmail(request.Email)
+
+	if err := ah.validator.ValidateStruct(&request); err != nil {
+		log.Printf("[ERROR] validation failed: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    "Validation error: " + err.Error(),
+		})
+	}
+
+	if err := ah.authService.ForgotPassword(request); err != nil {
+		return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
+			"success": false,
+			"data":    "something went wrong.",
+		})
+	}
+
+	return ctx.Status(http.StatusAccepted).JSON(&fiber.Map{
+		"success": true,
+		"data":    "if the email is registered, a reset code is on its way.",
+	})
+}
+
+// @Summary Reset Password
+// @Description Sets a new password using a code sent by forgot-password. Signs the user out of every session.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body dto.AuthResetPassword true "Reset Code and New Password"
+// @Success 200 {object} dto.StandardResponse
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /api/auth/reset-password [post]
+func (ah *AuthHandler) resetPassword(ctx *fiber.Ctx) error {
+	var request dto.AuthResetPassword
+	if err := ctx.BodyParser(&request); err != nil {
+		log.Printf("[ERROR] invalid request body: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidRequestJSON.Error(),
+		})
+	}
+
+	if err := ah.validator.ValidateStruct(&request); err != nil {
+		log.Printf("[ERROR] validation failed: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    "Validation error: " + err.Error(),
+		})
+	}
+
+	if err := ah.authService.ResetPassword(request); err != nil {
+		if errors.Is(err, repository.ErrInvalidPasswordReset) || errors.Is(err, helper.ErrWeakPassword) {
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    err.Error(),
+			})
+		}
+		return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
+			"success": false,
+			"data":    "something went wrong.",
+		})
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    "password has been reset.",
+	})
+}
+
+// @Summary Log Out
+// @Description Revokes the access token of the request. If a refresh token is given, its session is revoked too.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param logout body dto.AuthLogout false "Refresh Token"
+// @Success 200 {object} dto.StandardResponse
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /api/auth/logout [post]
+func (ah *AuthHandler) logout(ctx *fiber.Ctx) error {
+	var request dto.AuthLogout
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&request); err != nil {
+			log.Printf("[ERROR] invalid request body: %v", err)
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    ErrInvalidRequestJSON.Error(),
+			})
+		}
+	}
+
+	if err := ah.authService.Logout(middleware.AuthPayload(ctx), request); err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			return ctx.Status(http.StatusUnauthorized).JSON(&fiber.Map{
+				"success": false,
+				"data":    ErrInvalidOrExpiredToken.Error(),
+			})
+		}
+		log.Printf("[ERROR] failed to log out: %v", err)
+		return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
+			"success": false,
+			"data":    "something went wrong.",
+		})
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    "logged out.",
+	})
+}
+
+// @Summary Log Out Everywhere
+// @Description Revokes every access token and session of the signed-in user.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /api/auth/logout-all [post]
+func (ah *AuthHandler) logoutAll(ctx *fiber.Ctx) error {
+	payload := middleware.AuthPayload(ctx)
+	if err := ah.authService.LogoutEverywhere(payload.UserID); err != nil {
+		log.Printf("[ERROR] failed to log out everywhere: %v", err)
+		return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
+			"success": false,
+			"data":    "something went wrong.",
+		})
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    "logged out from all sessions.",
+	})
+}
@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/vgrigalashvili/veemon/api/rest"
+	"github.com/vgrigalashvili/veemon/api/rest/middleware"
+	"github.com/vgrigalashvili/veemon/internal/dto"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/internal/service"
+	"github.com/vgrigalashvili/veemon/pkg/money"
+)
+
+type BidHandler struct {
+	bidService  *service.BidService
+	handleError func(ctx *fiber.Ctx, err error) error // error handler function for handling API errors.
+}
+
+func InitializeBidHandler(rh *rest.RestHandler) {
+
+	api := rh.API
+	errorHandler := &rest.DefaultAPIErrorHandler{}
+	taskService := service.NewTaskService(repository.NewTaskRepository(rh.Store))
+	bidService := service.NewBidService(taskService, repository.NewBidRepository(rh.Store))
+
+	bidHandler := &BidHandler{
+		bidService:  bidService,
+		handleError: errorHandler.HandleError,
+	}
+
+	authMiddleware := middleware.AuthMiddleware(rh.Token, rh.Denylist)
+
+	// protected
+	api.Post("/tasks/:id/bids", authMiddleware, bidHandler.create)
+	api.Get("/tasks/:id/bids", authMiddleware, bidHandler.list)
+	api.Post("/tasks/:id/bids/:bid/withdraw", authMiddleware, bidHandler.withdraw)
+	api.Post("/tasks/:id/bids/:bid/accept", authMiddleware, bidHandler.accept)
+}
+
+// @Summary Bid on a Task
+// @Description Places a bid of the signed-in user on an open task. The amount must be in the currency of the task budget, and a user can have only one pending bid per task.
+// @Tags Bids
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param bid body dto.CreateBid true "Bid Data"
+// @Success 201 {object} dto.StandardResponse{data=dto.BidResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 409 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /tasks/{id}/bids [post]
+func (bh *BidHandler) create(ctx *fiber.Ctx) error {
+	taskID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	var bidData dto.CreateBid
+	if err := ctx.BodyParser(&bidData); err != nil {
+		log.Printf("[ERROR] invalid request body: %v", err)
+		return bh.handleError(ctx, rest.ErrInvalidRequestJSON)
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(bidData); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	bid, err := bh.bidService.Create(middleware.AuthActor(ctx), taskID, bidData.Amount, bidData.Message)
+	if err != nil {
+		return bidErrorResponse(ctx, err, "failed to create bid.")
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewBidResponse(bid),
+	})
+}
+
+// @Summary List Task Bids
+// @Description Returns the bids on a task, oldest first. The task owner and staff see every bid, other users only their own.
+// @Tags Bids
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Success 200 {object} dto.StandardResponse{data=[]dto.BidResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /tasks/{id}/bids [get]
+func (bh *BidHandler) list(ctx *fiber.Ctx) error {
+	taskID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	bids, err := bh.bidService.List(middleware.AuthActor(ctx), taskID)
+	if err != nil {
+		return bidErrorResponse(ctx, err, "failed to list bids.")
+	}
+
+	response := make([]dto.BidResponse, 0, len(bids))
+	for _, bid := range bids {
+		response = append(response, dto.NewBidResponse(bid))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// @Summary Withdraw a Bid
+// @Description Withdraws a pending bid. Only the bidder may withdraw it.
+// @Tags Bids
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param bid path string true "Bid ID"
+// @Success 200 {object} dto.StandardResponse{data=dto.BidResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 409 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /tasks/{id}/bids/{bid}/withdraw [post]
+func (bh *BidHandler) withdraw(ctx *fiber.Ctx) error {
+	taskID, bidID, err := parseBidParams(ctx)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	bid, err := bh.bidService.Withdraw(middleware.AuthActor(ctx), taskID, bidID)
+	if err != nil {
+		return bidErrorResponse(ctx, err, "failed to withdraw bid.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewBidResponse(bid),
+	})
+}
+
+// @Summary Accept a Bid
+// @Description Accepts a pending bid on an open task. In one transaction the bidder is assigned to the task, the task moves to assigned and all other pending bids are rejected. Only the task owner may accept a bid.
+// @Tags Bids
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param bid path string true "Bid ID"
+// @Success 200 {object} dto.StandardResponse{data=dto.AcceptBidResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 409 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /tasks/{id}/bids/{bid}/accept [post]
+func (bh *BidHandler) accept(ctx *fiber.Ctx) error {
+	taskID, bidID, err := parseBidParams(ctx)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	bid, task, err := bh.bidService.Accept(middleware.AuthActor(ctx), taskID, bidID)
+	if err != nil {
+		return bidErrorResponse(ctx, err, "failed to accept bid.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data": dto.AcceptBidResponse{
+			Bid:  dto.NewBidResponse(bid),
+			Task: dto.NewTaskResponse(task),
+		},
+	})
+}
+
+// parseBidParams parses the task and bid IDs of a bid route.
+func parseBidParams(ctx *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	taskID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	bidID, err := uuid.Parse(ctx.Params("bid"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return taskID, bidID, nil
+}
+
+// bidErrorResponse maps bid service errors to API responses, falling back to
+// taskErrorResponse for errors of the task itself.
+func bidErrorResponse(ctx *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, repository.ErrBidNotFound):
+		return ctx.Status(http.StatusNotFound).JSON(&fiber.Map{
+			"success": false,
+			"data":    repository.ErrBidNotFound.Error(),
+		})
+	case errors.Is(err, service.ErrBidForbidden):
+		return ctx.Status(http.StatusForbidden).JSON(&fiber.Map{
+			"success": false,
+			"data":    service.ErrBidForbidden.Error(),
+		})
+	case errors.Is(err, repository.ErrBidAlreadyExists), errors.Is(err, repository.ErrBidNotPending),
+		errors.Is(err, service.ErrTaskNotOpen):
+		return ctx.Status(http.StatusConflict).JSON(&fiber.Map{
+			"success": false,
+			"data":    err.Error(),
+		})
+	case errors.Is(err, service.ErrOwnTaskBid), errors.Is(err, money.ErrCurrencyMismatch):
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    err.Error(),
+		})
+	}
+	return taskErrorResponse(ctx, err, fallback)
+}
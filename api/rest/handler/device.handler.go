@@ -0,0 +1,650 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/vgrigalashvili/veemon/api/rest"
+	"github.com/vgrigalashvili/veemon/api/rest/middleware"
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/dto"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/internal/service"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
+)
+
+type DeviceHandler struct {
+	deviceService  *service.DeviceService
+	eventService   *service.DeviceEventService
+	commandService *service.DeviceCommandService
+	handleError    func(ctx *fiber.Ctx, err error) error // error handler function for handling API errors.
+}
+
+func InitializeDeviceHandler(rh *rest.RestHandler) {
+
+	api := rh.API
+	errorHandler := &rest.DefaultAPIErrorHandler{}
+	deviceRepo := repository.NewDeviceRepository(rh.Store)
+	incidentService := service.NewIncidentService(
+		repository.NewIncidentRepository(rh.Store),
+		repository.NewDeviceContactRepository(rh.Store),
+		deviceRepo,
+		rh.TaskDistributor,
+		rh.IncidentPolicy,
+	)
+	deviceService := service.NewDeviceService(deviceRepo, repository.NewMetricRepository(rh.Store), incidentService, rh.Config.HeartbeatInterval, rh.Config.HeartbeatMissedLimit)
+	eventService := service.NewDeviceEventService(deviceRepo, repository.NewDeviceEventRepository(rh.Store), incidentService)
+	commandService := service.NewDeviceCommandService(deviceRepo, repository.NewDeviceCommandRepository(rh.Store), rh.MQTT, rh.Config.CommandTimeout)
+
+	deviceHandler := &DeviceHandler{
+		deviceService:  deviceService,
+		eventService:   eventService,
+		commandService: commandService,
+		handleError:    errorHandler.HandleError,
+	}
+
+	authMiddleware := middleware.AuthMiddleware(rh.Token, rh.Denylist)
+
+	// protected
+	api.Post("/devices", authMiddleware, middleware.RequirePermission("devices:manage"), deviceHandler.create)
+	api.Get("/devices", authMiddleware, middleware.RequirePermission("devices:read"), deviceHandler.list)
+	api.Get("/devices/:id", authMiddleware, middleware.RequirePermission("devices:read"), deviceHandler.get)
+	api.Get("/devices/:id/status", authMiddleware, middleware.RequirePermission("devices:read"), deviceHandler.status)
+	api.Get("/devices/:id/metrics", authMiddleware, middleware.RequirePermission("devices:read"), deviceHandler.metrics)
+	api.Get("/devices/:id/events", authMiddleware, middleware.RequirePermission("devices:read"), deviceHandler.events)
+	api.Post("/devices/:id/commands", authMiddleware, middleware.RequirePermission("devices:command"), deviceHandler.sendCommand)
+	api.Get("/devices/:id/commands", authMiddleware, middleware.RequirePermission("devices:read"), deviceHandler.commands)
+	api.Get("/devices/:id/commands/:command", authMiddleware, middleware.RequirePermission("devices:read"), deviceHandler.command)
+	api.Patch("/devices/:id", authMiddleware, middleware.RequirePermission("devices:manage"), deviceHandler.update)
+	api.Delete("/devices/:id", authMiddleware, middleware.RequirePermission("devices:manage"), deviceHandler.delete)
+}
+
+// @Summary Register a Device
+// @Description Adds a lift to the device registry. The ID must be the one the lift publishes its MQTT messages under; heartbeats of unregistered lifts are rejected.
+// @Tags Devices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param device body dto.CreateDevice true "Device Data"
+// @Success 201 {object} dto.StandardResponse{data=dto.DeviceResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 409 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /devices [post]
+func (dh *DeviceHandler) create(ctx *fiber.Ctx) error {
+	var deviceData dto.CreateDevice
+	if err := ctx.BodyParser(&deviceData); err != nil {
+		log.Printf("[ERROR] invalid request body: %v", err)
+		return dh.handleError(ctx, rest.ErrInvalidRequestJSON)
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(deviceData); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	device := domain.Device{
+		ID:       deviceData.ID,
+		Building: deviceData.Building,
+		Address:  deviceData.Address,
+		Model:    deviceData.Model,
+		OwnerOrg: deviceData.OwnerOrg,
+	}
+	if deviceData.InstallDate != "" {
+		// Already checked by the datetime validation.
+		device.InstallDate, _ = time.Parse(time.DateOnly, deviceData.InstallDate)
+	}
+
+	createdDevice, err := dh.deviceService.Create(device)
+	if err != nil {
+		return deviceErrorResponse(ctx, err, "failed to register device.")
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewDeviceResponse(createdDevice),
+	})
+}
+
+// @Summary List Devices
+// @Description Returns a page of the registered devices ordered by ID. Pass the returned next_cursor to get the following page.
+// @Tags Devices
+// @Produce json
+// @Security BearerAuth
+// @Param building query string false "Only devices in this building"
+// @Param owner_org query string false "Only devices of this owner organization"
+// @Param cursor query string false "Cursor of the page to return"
+// @Param limit query int false "Page size, 50 by default and at most 500"
+// @Success 200 {object} dto.StandardResponse{data=dto.DeviceListResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /devices [get]
+func (dh *DeviceHandler) list(ctx *fiber.Ctx) error {
+	var query dto.ListDevices
+	if err := ctx.QueryParser(&query); err != nil {
+		log.Printf("[ERROR] invalid query parameters: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    rest.ErrValidationField,
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(query); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	filter := domain.DeviceFilter{
+		CursorID: query.Cursor,
+		Limit:    query.Limit,
+	}
+	if query.Building != "" {
+		filter.Building = &query.Building
+	}
+	if query.OwnerOrg != "" {
+		filter.OwnerOrg = &query.OwnerOrg
+	}
+
+	devices, nextCursor, err := dh.deviceService.List(filter)
+	if err != nil {
+		return deviceErrorResponse(ctx, err, "failed to list devices.")
+	}
+
+	response := dto.DeviceListResponse{
+		Devices:    make([]dto.DeviceResponse, 0, len(devices)),
+		NextCursor: nextCursor,
+	}
+	for _, device := range devices {
+		response.Devices = append(response.Devices, dto.NewDeviceResponse(device))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// @Summary Get a Device
+// @Description Returns a registered device by its ID.
+// @Tags Devices
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device ID"
+// @Success 200 {object} dto.StandardResponse{data=dto.DeviceResponse}
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /devices/{id} [get]
+func (dh *DeviceHandler) get(ctx *fiber.Ctx) error {
+	device, err := dh.deviceService.Get(ctx.Params("id"))
+	if err != nil {
+		return deviceErrorResponse(ctx, err, "failed to get device.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewDeviceResponse(device),
+	})
+}
+
+// @Summary Get the Status of a Device
+// @Description Returns whether a lift is online, based on its heartbeats. A lift is offline once it missed HEARTBEAT_MISSED_LIMIT heartbeats in a row, and unknown until its first heartbeat. Also returns the firmware and metrics of the last heartbeat.
+// @Tags Devices
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device ID"
+// @Success 200 {object} dto.StandardResponse{data=dto.DeviceStatusResponse}
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /devices/{id}/status [get]
+func (dh *DeviceHandler) status(ctx *fiber.Ctx) error {
+	status, err := dh.deviceService.Status(ctx.Params("id"))
+	if err != nil {
+		return deviceErrorResponse(ctx, err, "failed to get device status.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewDeviceStatusResponse(status),
+	})
+}
+
+// @Summary Get the Metrics of a Device
+// @Description Returns the metrics a lift reported, downsampled into buckets of one step each with the average, minimum and maximum of the samples in the bucket. Buckets start at from; steps without samples are left out. The range defaults to the 24 hours before to, and to to now. Without a step the range is split into about 200 buckets; a range of more than 1000 steps is rejected.
+// @Tags Devices
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device ID"
+// @Param name query string false "Only this metric"
+// @Param from query string false "Start of the range (RFC 3339)"
+// @Param to query string false "End of the range, exclusive (RFC 3339)"
+// @Param step query string false "Bucket size as a duration, such as 5m or 1h"
+// @Success 200 {object} dto.StandardResponse{data=dto.DeviceMetricsResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /devices/{id}/metrics [get]
+func (dh *DeviceHandler) metrics(ctx *fiber.Ctx) error {
+	var query dto.ListDeviceMetrics
+	if err := ctx.QueryParser(&query); err != nil {
+		log.Printf("[ERROR] invalid query parameters: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    rest.ErrValidationField,
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(query); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	var metricQuery domain.MetricQuery
+	if query.Name != "" {
+		metricQuery.Name = &query.Name
+	}
+	from, err := parseOptionalTimestamp(query.From)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidTimestamp.Error(),
+		})
+	}
+	if from != nil {
+		metricQuery.From = *from
+	}
+	to, err := parseOptionalTimestamp(query.To)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidTimestamp.Error(),
+		})
+	}
+	if to != nil {
+		metricQuery.To = *to
+	}
+	if query.Step != "" {
+		if metricQuery.Step, err = time.ParseDuration(query.Step); err != nil {
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    service.ErrInvalidStep.Error(),
+			})
+		}
+	}
+
+	deviceID := ctx.Params("id")
+	series, metricQuery, err := dh.deviceService.Metrics(deviceID, metricQuery)
+	if err != nil {
+		return deviceErrorResponse(ctx, err, "failed to get device metrics.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewDeviceMetricsResponse(deviceID, metricQuery, series),
+	})
+}
+
+// @Summary List the Events of a Device
+// @Description Returns a page of the door, fault, trip and alarm events a lift reported, newest first. Pass the returned next_cursor to get the following page.
+// @Tags Devices
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device ID"
+// @Param kind query string false "Only events of this kind" Enums(door, fault, trip, alarm)
+// @Param cursor query string false "Cursor of the page to return"
+// @Param limit query int false "Page size, 50 by default and at most 500"
+// @Success 200 {object} dto.StandardResponse{data=dto.DeviceEventListResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /devices/{id}/events [get]
+func (dh *DeviceHandler) events(ctx *fiber.Ctx) error {
+	var query dto.ListDeviceEvents
+	if err := ctx.QueryParser(&query); err != nil {
+		log.Printf("[ERROR] invalid query parameters: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    rest.ErrValidationField,
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(query); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	filter := domain.DeviceEventFilter{Limit: query.Limit}
+	if query.Kind != "" {
+		filter.Kind = &query.Kind
+	}
+	if query.Cursor != "" {
+		createdAt, id, err := helper.DecodeCursor(query.Cursor)
+		if err != nil {
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    helper.ErrInvalidCursor.Error(),
+			})
+		}
+		filter.CursorCreatedAt = &createdAt
+		filter.CursorID = id
+	}
+
+	events, nextCursor, err := dh.eventService.List(ctx.Params("id"), filter)
+	if err != nil {
+		return deviceErrorResponse(ctx, err, "failed to list device events.")
+	}
+
+	response := dto.DeviceEventListResponse{
+		Events:     make([]dto.DeviceEventResponse, 0, len(events)),
+		NextCursor: nextCursor,
+	}
+	for _, event := range events {
+		response.Events = append(response.Events, dto.NewDeviceEventResponse(event))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// @Summary Send a Command to a Device
+// @Description Publishes a command to a lift on Lift/{id}/commands/{name}, with the ID of the stored command as its correlation ID. The command stays pending until the lift replies acked on Lift/{id}/commands/{name}/reply, then completed or failed; without a final reply before it expires it times out. Maintenance mode takes a boolean enabled argument, reboot and diagnostics none.
+// @Tags Devices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device ID"
+// @Param command body dto.SendDeviceCommand true "Command"
+// @Success 202 {object} dto.StandardResponse{data=dto.DeviceCommandResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Failure 503 {object} dto.StandardResponse
+// @Router /devices/{id}/commands [post]
+func (dh *DeviceHandler) sendCommand(ctx *fiber.Ctx) error {
+	var commandData dto.SendDeviceCommand
+	if err := ctx.BodyParser(&commandData); err != nil {
+		log.Printf("[ERROR] invalid request body: %v", err)
+		return dh.handleError(ctx, rest.ErrInvalidRequestJSON)
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(commandData); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	command, err := dh.commandService.Send(
+		middleware.AuthActor(ctx).ID,
+		ctx.Params("id"),
+		commandData.Name,
+		commandData.Args,
+		time.Duration(commandData.TimeoutSeconds)*time.Second,
+	)
+	if err != nil {
+		return deviceErrorResponse(ctx, err, "failed to send device command.")
+	}
+
+	return ctx.Status(http.StatusAccepted).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewDeviceCommandResponse(command),
+	})
+}
+
+// @Summary List the Commands of a Device
+// @Description Returns a page of the commands sent to a lift, newest first, with their progress.
+// @Tags Devices
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device ID"
+// @Param status query string false "Only commands in this status" Enums(pending, acked, completed, failed, timed_out)
+// @Param cursor query string false "Cursor of the page, from next_cursor of the previous page"
+// @Param limit query int false "Page size, 50 by default and at most 500"
+// @Success 200 {object} dto.StandardResponse{data=dto.DeviceCommandListResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /devices/{id}/commands [get]
+func (dh *DeviceHandler) commands(ctx *fiber.Ctx) error {
+	var query dto.ListDeviceCommands
+	if err := ctx.QueryParser(&query); err != nil {
+		log.Printf("[ERROR] invalid query parameters: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    rest.ErrValidationField,
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(query); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	filter := domain.DeviceCommandFilter{Limit: query.Limit}
+	if query.Status != "" {
+		filter.Status = &query.Status
+	}
+	if query.Cursor != "" {
+		createdAt, id, err := helper.DecodeCursor(query.Cursor)
+		if err != nil {
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    helper.ErrInvalidCursor.Error(),
+			})
+		}
+		filter.CursorCreatedAt = &createdAt
+		filter.CursorID = id
+	}
+
+	commands, nextCursor, err := dh.commandService.List(ctx.Params("id"), filter)
+	if err != nil {
+		return deviceErrorResponse(ctx, err, "failed to list device commands.")
+	}
+
+	response := dto.DeviceCommandListResponse{
+		Commands:   make([]dto.DeviceCommandResponse, 0, len(commands)),
+		NextCursor: nextCursor,
+	}
+	for _, command := range commands {
+		response.Commands = append(response.Commands, dto.NewDeviceCommandResponse(command))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// @Summary Get a Command of a Device
+// @Description Returns a command sent to a lift and its progress.
+// @Tags Devices
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device ID"
+// @Param command path string true "Command ID"
+// @Success 200 {object} dto.StandardResponse{data=dto.DeviceCommandResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /devices/{id}/commands/{command} [get]
+func (dh *DeviceHandler) command(ctx *fiber.Ctx) error {
+	commandID, err := uuid.Parse(ctx.Params("command"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	command, err := dh.commandService.Get(ctx.Params("id"), commandID)
+	if err != nil {
+		return deviceErrorResponse(ctx, err, "failed to get device command.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewDeviceCommandResponse(command),
+	})
+}
+
+// @Summary Update a Device
+// @Description Applies a partial update to the metadata of a registered device. The ID cannot be changed.
+// @Tags Devices
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device ID"
+// @Param device body dto.UpdateDevice true "Fields to Update"
+// @Success 200 {object} dto.StandardResponse{data=dto.DeviceResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /devices/{id} [patch]
+func (dh *DeviceHandler) update(ctx *fiber.Ctx) error {
+	var deviceData dto.UpdateDevice
+	if err := ctx.BodyParser(&deviceData); err != nil {
+		log.Printf("[ERROR] invalid request body: %v", err)
+		return dh.handleError(ctx, rest.ErrInvalidRequestJSON)
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(deviceData); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	update := domain.DeviceUpdate{
+		Building: deviceData.Building,
+		Address:  deviceData.Address,
+		Model:    deviceData.Model,
+		OwnerOrg: deviceData.OwnerOrg,
+	}
+	if deviceData.InstallDate != nil {
+		// Already checked by the datetime validation.
+		installDate, _ := time.Parse(time.DateOnly, *deviceData.InstallDate)
+		update.InstallDate = &installDate
+	}
+
+	device, err := dh.deviceService.Update(ctx.Params("id"), update)
+	if err != nil {
+		return deviceErrorResponse(ctx, err, "failed to update device.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewDeviceResponse(device),
+	})
+}
+
+// @Summary Delete a Device
+// @Description Removes a device from the registry. Its heartbeats are rejected from then on; registering the ID again restores it.
+// @Tags Devices
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device ID"
+// @Success 200 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /devices/{id} [delete]
+func (dh *DeviceHandler) delete(ctx *fiber.Ctx) error {
+	deviceID := ctx.Params("id")
+	if err := dh.deviceService.Delete(deviceID); err != nil {
+		return deviceErrorResponse(ctx, err, "failed to delete device.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    deviceID,
+	})
+}
+
+// deviceErrorResponse maps device service errors to API responses.
+func deviceErrorResponse(ctx *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, repository.ErrDeviceNotFound):
+		return ctx.Status(http.StatusNotFound).JSON(&fiber.Map{
+			"success": false,
+			"data":    repository.ErrDeviceNotFound.Error(),
+		})
+	case errors.Is(err, repository.ErrDeviceAlreadyExists):
+		return ctx.Status(http.StatusConflict).JSON(&fiber.Map{
+			"success": false,
+			"data":    repository.ErrDeviceAlreadyExists.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidDeviceID):
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    service.ErrInvalidDeviceID.Error(),
+		})
+	case errors.Is(err, repository.ErrCommandNotFound):
+		return ctx.Status(http.StatusNotFound).JSON(&fiber.Map{
+			"success": false,
+			"data":    repository.ErrCommandNotFound.Error(),
+		})
+	case errors.Is(err, service.ErrCommandNotDelivered):
+		return ctx.Status(http.StatusServiceUnavailable).JSON(&fiber.Map{
+			"success": false,
+			"data":    service.ErrCommandNotDelivered.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidTimeRange),
+		errors.Is(err, service.ErrInvalidStep),
+		errors.Is(err, service.ErrTooManyBuckets),
+		errors.Is(err, service.ErrUnknownCommand),
+		errors.Is(err, service.ErrInvalidCommandArgs),
+		errors.Is(err, service.ErrInvalidCommandTimeout):
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    err.Error(),
+		})
+	}
+	return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
+		"success": false,
+		"data":    fallback,
+	})
+}
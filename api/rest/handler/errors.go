@@ -17,4 +17,7 @@ var (
 	ErrInvalidRequestJSON      = errors.New("invalid JSON body in request")
 	ErrValidationField         = errors.New("validation field")
 	ErrUniqueMobileComplaint   = errors.New("mobile already taken")
+	ErrInvalidTimestamp        = errors.New("invalid timestamp, expected RFC 3339 with a time zone")
+	ErrMultipartFileRequired   = errors.New("multipart/form-data body with a `file` field required")
+	ErrInvalidDownloadLink     = errors.New("download link is invalid or has expired")
 )
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vgrigalashvili/veemon/api/rest"
+	"github.com/vgrigalashvili/veemon/internal/dto"
+	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
+	"github.com/vgrigalashvili/veemon/pkg/mqtt"
+)
+
+// readinessTimeout bounds how long a readiness probe waits for the database.
+const readinessTimeout = 2 * time.Second
+
+type HealthHandler struct {
+	store *db.Store
+	mqtt  mqtt.Client
+}
+
+func InitializeHealthHandler(rh *rest.RestHandler) {
+
+	api := rh.API
+
+	healthHandler := &HealthHandler{
+		store: rh.Store,
+		mqtt:  rh.MQTT,
+	}
+
+	// public
+	api.Get("/health/live", healthHandler.live)
+	api.Get("/health/ready", healthHandler.ready)
+}
+
+// @Summary Liveness Probe
+// @Description Succeeds while the server is running.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} dto.StandardResponse
+// @Router /health/live [get]
+func (hh *HealthHandler) live(ctx *fiber.Ctx) error {
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    "ok",
+	})
+}
+
+// @Summary Readiness Probe
+// @Description Succeeds when the database is reachable and the MQTT client is connected and subscribed to the lift topics, and fails with 503 otherwise.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} dto.StandardResponse{data=dto.ReadinessResponse}
+// @Failure 503 {object} dto.StandardResponse{data=dto.ReadinessResponse}
+// @Router /health/ready [get]
+func (hh *HealthHandler) ready(ctx *fiber.Ctx) error {
+	response := dto.ReadinessResponse{
+		Database: "up",
+		MQTT:     hh.mqtt.State(),
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx.UserContext(), readinessTimeout)
+	defer cancel()
+	if err := hh.store.Ping(pingCtx); err != nil {
+		log.Printf("[WARN] readiness probe failed to reach the database: %v", err)
+		response.Database = "down"
+	}
+
+	ready := response.Database == "up" && response.MQTT == mqtt.StateConnected
+	status := http.StatusOK
+	if !ready {
+		status = http.StatusServiceUnavailable
+	}
+	return ctx.Status(status).JSON(&fiber.Map{
+		"success": ready,
+		"data":    response,
+	})
+}
@@ -0,0 +1,372 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/vgrigalashvili/veemon/api/rest"
+	"github.com/vgrigalashvili/veemon/api/rest/middleware"
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/dto"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/internal/service"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
+)
+
+type IncidentHandler struct {
+	incidentService *service.IncidentService
+	handleError     func(ctx *fiber.Ctx, err error) error // error handler function for handling API errors.
+}
+
+func InitializeIncidentHandler(rh *rest.RestHandler) {
+
+	api := rh.API
+	errorHandler := &rest.DefaultAPIErrorHandler{}
+	incidentService := service.NewIncidentService(
+		repository.NewIncidentRepository(rh.Store),
+		repository.NewDeviceContactRepository(rh.Store),
+		repository.NewDeviceRepository(rh.Store),
+		rh.TaskDistributor,
+		rh.IncidentPolicy,
+	)
+
+	incidentHandler := &IncidentHandler{
+		incidentService: incidentService,
+		handleError:     errorHandler.HandleError,
+	}
+
+	authMiddleware := middleware.AuthMiddleware(rh.Token, rh.Denylist)
+
+	// protected
+	api.Get("/incidents", authMiddleware, middleware.RequirePermission("incidents:read"), incidentHandler.list)
+	api.Get("/incidents/:id", authMiddleware, middleware.RequirePermission("incidents:read"), incidentHandler.get)
+	api.Post("/incidents/:id/acknowledge", authMiddleware, middleware.RequirePermission("incidents:manage"), incidentHandler.acknowledge)
+	api.Post("/incidents/:id/resolve", authMiddleware, middleware.RequirePermission("incidents:manage"), incidentHandler.resolve)
+	api.Get("/devices/:id/contacts", authMiddleware, middleware.RequirePermission("devices:read"), incidentHandler.contacts)
+	api.Put("/devices/:id/contacts/:user", authMiddleware, middleware.RequirePermission("devices:manage"), incidentHandler.setContact)
+	api.Delete("/devices/:id/contacts/:user", authMiddleware, middleware.RequirePermission("devices:manage"), incidentHandler.removeContact)
+}
+
+// @Summary List Incidents
+// @Description Returns a page of incidents, newest first. Lifts open incidents for warning and critical faults and when they go offline; a problem recurring while its incident is unresolved adds an occurrence instead of a new incident.
+// @Tags Incidents
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Only incidents in this status" Enums(open, acknowledged, resolved)
+// @Param device_id query string false "Only incidents of this device"
+// @Param severity query string false "Only incidents of this severity" Enums(warning, critical)
+// @Param cursor query string false "Cursor of the page, from next_cursor of the previous page"
+// @Param limit query int false "Page size, 50 by default and at most 500"
+// @Success 200 {object} dto.StandardResponse{data=dto.IncidentListResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /incidents [get]
+func (ih *IncidentHandler) list(ctx *fiber.Ctx) error {
+	var query dto.ListIncidents
+	if err := ctx.QueryParser(&query); err != nil {
+		log.Printf("[ERROR] invalid query parameters: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    rest.ErrValidationField,
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(query); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	filter := domain.IncidentFilter{Limit: query.Limit}
+	if query.Status != "" {
+		filter.Status = &query.Status
+	}
+	if query.DeviceID != "" {
+		filter.DeviceID = &query.DeviceID
+	}
+	if query.Severity != "" {
+		filter.Severity = &query.Severity
+	}
+	if query.Cursor != "" {
+		createdAt, id, err := helper.DecodeCursor(query.Cursor)
+		if err != nil {
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    helper.ErrInvalidCursor.Error(),
+			})
+		}
+		filter.CursorCreatedAt = &createdAt
+		filter.CursorID = id
+	}
+
+	incidents, nextCursor, err := ih.incidentService.List(filter)
+	if err != nil {
+		return incidentErrorResponse(ctx, err, "failed to list incidents.")
+	}
+
+	response := dto.IncidentListResponse{
+		Incidents:  make([]dto.IncidentResponse, 0, len(incidents)),
+		NextCursor: nextCursor,
+	}
+	for _, incident := range incidents {
+		response.Incidents = append(response.Incidents, dto.NewIncidentResponse(incident))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// @Summary Get an Incident
+// @Description Returns an incident by its ID.
+// @Tags Incidents
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Incident ID"
+// @Success 200 {object} dto.StandardResponse{data=dto.IncidentResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /incidents/{id} [get]
+func (ih *IncidentHandler) get(ctx *fiber.Ctx) error {
+	incidentID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	incident, err := ih.incidentService.Get(incidentID)
+	if err != nil {
+		return incidentErrorResponse(ctx, err, "failed to get incident.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewIncidentResponse(incident),
+	})
+}
+
+// @Summary Acknowledge an Incident
+// @Description Records that the requester is taking care of an open incident, which stops its escalation.
+// @Tags Incidents
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Incident ID"
+// @Success 200 {object} dto.StandardResponse{data=dto.IncidentResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 409 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /incidents/{id}/acknowledge [post]
+func (ih *IncidentHandler) acknowledge(ctx *fiber.Ctx) error {
+	incidentID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	incident, err := ih.incidentService.Acknowledge(incidentID, middleware.AuthActor(ctx).ID)
+	if err != nil {
+		return incidentErrorResponse(ctx, err, "failed to acknowledge incident.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewIncidentResponse(incident),
+	})
+}
+
+// @Summary Resolve an Incident
+// @Description Closes an open or acknowledged incident. The problem recurring shortly after reopens it instead of opening a new incident.
+// @Tags Incidents
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Incident ID"
+// @Success 200 {object} dto.StandardResponse{data=dto.IncidentResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 409 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /incidents/{id}/resolve [post]
+func (ih *IncidentHandler) resolve(ctx *fiber.Ctx) error {
+	incidentID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	incident, err := ih.incidentService.Resolve(incidentID, middleware.AuthActor(ctx).ID)
+	if err != nil {
+		return incidentErrorResponse(ctx, err, "failed to resolve incident.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewIncidentResponse(incident),
+	})
+}
+
+// @Summary List the Contacts of a Device
+// @Description Returns the users notified of the incidents of a lift, by escalation level.
+// @Tags Incidents
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device ID"
+// @Success 200 {object} dto.StandardResponse{data=[]dto.DeviceContactResponse}
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /devices/{id}/contacts [get]
+func (ih *IncidentHandler) contacts(ctx *fiber.Ctx) error {
+	contacts, err := ih.incidentService.Contacts(ctx.Params("id"))
+	if err != nil {
+		return incidentErrorResponse(ctx, err, "failed to list device contacts.")
+	}
+
+	response := make([]dto.DeviceContactResponse, 0, len(contacts))
+	for _, contact := range contacts {
+		response = append(response, dto.NewDeviceContactResponse(contact))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// @Summary Set a Contact of a Device
+// @Description Adds a user to the contacts of a lift, or changes the escalation level they are notified from. Level 0 contacts are notified when an incident opens, higher levels once it escalated that far.
+// @Tags Incidents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device ID"
+// @Param user path string true "User ID"
+// @Param contact body dto.SetDeviceContact true "Contact Data"
+// @Success 200 {object} dto.StandardResponse{data=dto.DeviceContactResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /devices/{id}/contacts/{user} [put]
+func (ih *IncidentHandler) setContact(ctx *fiber.Ctx) error {
+	userID, err := uuid.Parse(ctx.Params("user"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	var contactData dto.SetDeviceContact
+	if err := ctx.BodyParser(&contactData); err != nil {
+		log.Printf("[ERROR] invalid request body: %v", err)
+		return ih.handleError(ctx, rest.ErrInvalidRequestJSON)
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(contactData); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	contact, err := ih.incidentService.SetContact(domain.DeviceContact{
+		DeviceID:        ctx.Params("id"),
+		UserID:          userID,
+		EscalationLevel: contactData.EscalationLevel,
+	})
+	if err != nil {
+		return incidentErrorResponse(ctx, err, "failed to set device contact.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewDeviceContactResponse(contact),
+	})
+}
+
+// @Summary Remove a Contact of a Device
+// @Description Stops notifying a user of the incidents of a lift.
+// @Tags Incidents
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Device ID"
+// @Param user path string true "User ID"
+// @Success 200 {object} dto.StandardResponse
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /devices/{id}/contacts/{user} [delete]
+func (ih *IncidentHandler) removeContact(ctx *fiber.Ctx) error {
+	userID, err := uuid.Parse(ctx.Params("user"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	if err := ih.incidentService.RemoveContact(ctx.Params("id"), userID); err != nil {
+		return incidentErrorResponse(ctx, err, "failed to remove device contact.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    userID,
+	})
+}
+
+// incidentErrorResponse maps incident service errors to API responses.
+func incidentErrorResponse(ctx *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, repository.ErrIncidentNotFound),
+		errors.Is(err, repository.ErrDeviceNotFound),
+		errors.Is(err, repository.ErrUserNotFound),
+		errors.Is(err, repository.ErrContactNotFound):
+		return ctx.Status(http.StatusNotFound).JSON(&fiber.Map{
+			"success": false,
+			"data":    err.Error(),
+		})
+	case errors.Is(err, repository.ErrIncidentStatusConflict):
+		return ctx.Status(http.StatusConflict).JSON(&fiber.Map{
+			"success": false,
+			"data":    repository.ErrIncidentStatusConflict.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidEscalationLevel):
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    service.ErrInvalidEscalationLevel.Error(),
+		})
+	}
+	return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
+		"success": false,
+		"data":    fallback,
+	})
+}
@@ -0,0 +1,522 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/vgrigalashvili/veemon/api/rest"
+	"github.com/vgrigalashvili/veemon/api/rest/middleware"
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/dto"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/internal/service"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
+	"github.com/vgrigalashvili/veemon/pkg/money"
+)
+
+type TaskHandler struct {
+	taskService *service.TaskService
+	handleError func(ctx *fiber.Ctx, err error) error // error handler function for handling API errors.
+}
+
+func InitializeTaskHandler(rh *rest.RestHandler) {
+
+	api := rh.API
+	errorHandler := &rest.DefaultAPIErrorHandler{}
+	taskRepository := repository.NewTaskRepository(rh.Store)
+	taskService := service.NewTaskService(taskRepository)
+
+	taskHandler := &TaskHandler{
+		taskService: taskService,
+		handleError: errorHandler.HandleError,
+	}
+
+	authMiddleware := middleware.AuthMiddleware(rh.Token, rh.Denylist)
+
+	// protected
+	api.Post("/tasks", authMiddleware, middleware.RequirePermission("tasks:create"), taskHandler.create)
+	api.Get("/tasks", authMiddleware, taskHandler.list)
+	api.Get("/tasks/search", authMiddleware, taskHandler.search)
+	api.Get("/tasks/:id", authMiddleware, taskHandler.get)
+	api.Patch("/tasks/:id", authMiddleware, taskHandler.update)
+	api.Delete("/tasks/:id", authMiddleware, taskHandler.delete)
+	api.Post("/tasks/:id/transitions", authMiddleware, taskHandler.transition)
+}
+
+// @Summary Create a Task
+// @Description Creates a task owned by the signed-in user. Private tasks are only visible to their owner and staff.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task body dto.CreateTask true "Task Data"
+// @Success 201 {object} dto.StandardResponse{data=dto.TaskResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /tasks [post]
+func (th *TaskHandler) create(ctx *fiber.Ctx) error {
+	var taskData dto.CreateTask
+	if err := ctx.BodyParser(&taskData); err != nil {
+		log.Printf("[ERROR] invalid request body: %v", err)
+		return th.handleError(ctx, rest.ErrInvalidRequestJSON)
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(taskData); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	task := domain.Task{
+		Public:      !taskData.Private,
+		Title:       taskData.Title,
+		Description: taskData.Description,
+		Category:    taskData.Category,
+		Location:    taskData.Location,
+		Address:     taskData.Address,
+		Budget:      taskData.Budget,
+		Latitude:    taskData.Latitude,
+		Longitude:   taskData.Longitude,
+	}
+	if taskData.Deadline != nil {
+		task.Deadline = *taskData.Deadline
+	}
+
+	createdTask, err := th.taskService.Create(middleware.AuthActor(ctx), task)
+	if err != nil {
+		return taskErrorResponse(ctx, err, "failed to create task.")
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewTaskResponse(createdTask),
+	})
+}
+
+// @Summary List Tasks
+// @Description Returns a page of the tasks visible to the signed-in user, newest first. Pass the returned next_cursor to get the following page.
+// @Tags Tasks
+// @Produce json
+// @Security BearerAuth
+// @Param owner query string false "Only tasks of this owner ID, or `me` for the signed-in user"
+// @Param cursor query string false "Cursor of the page to return"
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Success 200 {object} dto.StandardResponse{data=dto.TaskListResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /tasks [get]
+func (th *TaskHandler) list(ctx *fiber.Ctx) error {
+	var query dto.ListTasks
+	if err := ctx.QueryParser(&query); err != nil {
+		log.Printf("[ERROR] invalid query parameters: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    rest.ErrValidationField,
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(query); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	actor := middleware.AuthActor(ctx)
+	filter := domain.TaskFilter{Limit: query.Limit}
+	switch query.Owner {
+	case "":
+	case "me":
+		filter.OwnerID = &actor.ID
+	default:
+		ownerID, err := uuid.Parse(query.Owner)
+		if err != nil {
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    ErrInvalidUUIDFormat.Error(),
+			})
+		}
+		filter.OwnerID = &ownerID
+	}
+	if query.Cursor != "" {
+		createdAt, id, err := helper.DecodeCursor(query.Cursor)
+		if err != nil {
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    helper.ErrInvalidCursor.Error(),
+			})
+		}
+		filter.CursorCreatedAt = &createdAt
+		filter.CursorID = id
+	}
+
+	tasks, nextCursor, err := th.taskService.List(actor, filter)
+	if err != nil {
+		return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
+			"success": false,
+			"data":    "failed to list tasks.",
+		})
+	}
+
+	response := dto.TaskListResponse{
+		Tasks:      make([]dto.TaskResponse, 0, len(tasks)),
+		NextCursor: nextCursor,
+	}
+	for _, task := range tasks {
+		response.Tasks = append(response.Tasks, dto.NewTaskResponse(task))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// @Summary Search Tasks
+// @Description Returns a page of the tasks visible to the signed-in user that match every given filter, newest first. Pass the returned next_cursor to get the following page.
+// @Tags Tasks
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Full-text search over title and description"
+// @Param category query string false "Category"
+// @Param currency query string false "Budget currency, required with budget_min or budget_max" Enums(GEL, USD, EUR)
+// @Param budget_min query int false "Minimum budget in minor units of the currency"
+// @Param budget_max query int false "Maximum budget in minor units of the currency"
+// @Param deadline_from query string false "Earliest deadline, RFC 3339 with a time zone"
+// @Param deadline_to query string false "Latest deadline, RFC 3339 with a time zone"
+// @Param lat query number false "Latitude of the search center"
+// @Param lng query number false "Longitude of the search center"
+// @Param radius_km query number false "Search radius around lat/lng in kilometers, at most 500"
+// @Param cursor query string false "Cursor of the page to return"
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Success 200 {object} dto.StandardResponse{data=dto.TaskListResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /tasks/search [get]
+func (th *TaskHandler) search(ctx *fiber.Ctx) error {
+	var query dto.SearchTasks
+	if err := ctx.QueryParser(&query); err != nil {
+		log.Printf("[ERROR] invalid query parameters: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    rest.ErrValidationField,
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(query); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	search := domain.TaskSearch{
+		Query:     strings.TrimSpace(query.Query),
+		Category:  query.Category,
+		BudgetMin: query.BudgetMin,
+		BudgetMax: query.BudgetMax,
+		Limit:     query.Limit,
+	}
+	if query.Currency != "" {
+		currency, err := money.ParseCurrency(query.Currency)
+		if err != nil {
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    money.ErrUnsupportedCurrency.Error(),
+			})
+		}
+		search.BudgetCurrency = currency
+	}
+	var err error
+	if search.DeadlineFrom, err = parseOptionalTimestamp(query.DeadlineFrom); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidTimestamp.Error(),
+		})
+	}
+	if search.DeadlineTo, err = parseOptionalTimestamp(query.DeadlineTo); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidTimestamp.Error(),
+		})
+	}
+	if query.Latitude != nil {
+		search.Near = &domain.GeoRadius{
+			Latitude:  *query.Latitude,
+			Longitude: *query.Longitude,
+			RadiusKm:  *query.RadiusKm,
+		}
+	}
+	if query.Cursor != "" {
+		createdAt, id, err := helper.DecodeCursor(query.Cursor)
+		if err != nil {
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    helper.ErrInvalidCursor.Error(),
+			})
+		}
+		search.CursorCreatedAt = &createdAt
+		search.CursorID = id
+	}
+
+	tasks, nextCursor, err := th.taskService.Search(middleware.AuthActor(ctx), search)
+	if err != nil {
+		return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
+			"success": false,
+			"data":    "failed to search tasks.",
+		})
+	}
+
+	response := dto.TaskListResponse{
+		Tasks:      make([]dto.TaskResponse, 0, len(tasks)),
+		NextCursor: nextCursor,
+	}
+	for _, task := range tasks {
+		response.Tasks = append(response.Tasks, dto.NewTaskResponse(task))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// @Summary Get a Task
+// @Description Returns a task by ID. Private tasks of other users are reported as not found.
+// @Tags Tasks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Success 200 {object} dto.StandardResponse{data=dto.TaskResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /tasks/{id} [get]
+func (th *TaskHandler) get(ctx *fiber.Ctx) error {
+	taskID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	task, err := th.taskService.Get(middleware.AuthActor(ctx), taskID)
+	if err != nil {
+		return taskErrorResponse(ctx, err, "failed to get task.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewTaskResponse(task),
+	})
+}
+
+// @Summary Update a Task
+// @Description Partially updates a task; omitted fields are left unchanged. Only the owner or staff with the tasks:update permission may update it. The budget currency cannot change while bids in another currency are pending.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param task body dto.UpdateTask true "Fields to Update"
+// @Success 200 {object} dto.StandardResponse{data=dto.TaskResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 409 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /tasks/{id} [patch]
+func (th *TaskHandler) update(ctx *fiber.Ctx) error {
+	taskID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	var taskData dto.UpdateTask
+	if err := ctx.BodyParser(&taskData); err != nil {
+		log.Printf("[ERROR] invalid request body: %v", err)
+		return th.handleError(ctx, rest.ErrInvalidRequestJSON)
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(taskData); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	update := domain.TaskUpdate{
+		Title:       taskData.Title,
+		Description: taskData.Description,
+		Category:    taskData.Category,
+		Location:    taskData.Location,
+		Address:     taskData.Address,
+		Deadline:    taskData.Deadline,
+		Budget:      taskData.Budget,
+		Latitude:    taskData.Latitude,
+		Longitude:   taskData.Longitude,
+	}
+	if taskData.Private != nil {
+		public := !*taskData.Private
+		update.Public = &public
+	}
+
+	task, err := th.taskService.Update(middleware.AuthActor(ctx), taskID, update)
+	if err != nil {
+		return taskErrorResponse(ctx, err, "failed to update task.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewTaskResponse(task),
+	})
+}
+
+// @Summary Delete a Task
+// @Description Soft deletes a task. Only the owner or staff with the tasks:delete permission may delete it.
+// @Tags Tasks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Success 200 {object} dto.StandardResponse
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /tasks/{id} [delete]
+func (th *TaskHandler) delete(ctx *fiber.Ctx) error {
+	taskID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	if err := th.taskService.Delete(middleware.AuthActor(ctx), taskID); err != nil {
+		return taskErrorResponse(ctx, err, "failed to delete task.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    taskID,
+	})
+}
+
+// @Summary Change Task Status
+// @Description Moves a task to another status of its lifecycle: draft → open → assigned → in_progress → completed, with cancelled and disputed as side exits. Each move is allowed only for some of owner, assignee and staff, and is recorded in the task status history. When a task leaves open, a pending bid of the assignee is accepted and all other pending bids are rejected.
+// @Tags Tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param transition body dto.TransitionTask true "New Status"
+// @Success 200 {object} dto.StandardResponse{data=dto.TaskResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 409 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /tasks/{id}/transitions [post]
+func (th *TaskHandler) transition(ctx *fiber.Ctx) error {
+	taskID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	var transitionData dto.TransitionTask
+	if err := ctx.BodyParser(&transitionData); err != nil {
+		log.Printf("[ERROR] invalid request body: %v", err)
+		return th.handleError(ctx, rest.ErrInvalidRequestJSON)
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(transitionData); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	task, err := th.taskService.Transition(middleware.AuthActor(ctx), taskID, transitionData.Status, transitionData.AssigneeID, transitionData.Reason)
+	if err != nil {
+		return taskErrorResponse(ctx, err, "failed to change task status.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewTaskResponse(task),
+	})
+}
+
+// parseOptionalTimestamp parses an RFC 3339 query parameter, returning nil when it is empty.
+func parseOptionalTimestamp(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// taskErrorResponse maps task service errors to API responses.
+func taskErrorResponse(ctx *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, repository.ErrTaskNotFound):
+		return ctx.Status(http.StatusNotFound).JSON(&fiber.Map{
+			"success": false,
+			"data":    repository.ErrTaskNotFound.Error(),
+		})
+	case errors.Is(err, service.ErrTaskForbidden):
+		return ctx.Status(http.StatusForbidden).JSON(&fiber.Map{
+			"success": false,
+			"data":    service.ErrTaskForbidden.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidTaskTransition), errors.Is(err, repository.ErrTaskStatusConflict),
+		errors.Is(err, repository.ErrPendingBidCurrency):
+		return ctx.Status(http.StatusConflict).JSON(&fiber.Map{
+			"success": false,
+			"data":    err.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidTaskStatus), errors.Is(err, service.ErrAssigneeRequired),
+		errors.Is(err, service.ErrInvalidAssignee), errors.Is(err, repository.ErrAssigneeNotFound),
+		errors.Is(err, service.ErrDeadlineInPast), errors.Is(err, money.ErrUnsupportedCurrency),
+		errors.Is(err, money.ErrNegativeAmount):
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    err.Error(),
+		})
+	}
+	return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
+		"success": false,
+		"data":    fallback,
+	})
+}
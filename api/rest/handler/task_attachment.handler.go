@@ -0,0 +1,316 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/vgrigalashvili/veemon/api/rest"
+	"github.com/vgrigalashvili/veemon/api/rest/middleware"
+	"github.com/vgrigalashvili/veemon/internal/dto"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/internal/service"
+	"github.com/vgrigalashvili/veemon/pkg/storage"
+	"github.com/vgrigalashvili/veemon/pkg/token"
+)
+
+type AttachmentHandler struct {
+	attachmentService *service.AttachmentService
+}
+
+func InitializeAttachmentHandler(rh *rest.RestHandler) {
+
+	api := rh.API
+	taskService := service.NewTaskService(repository.NewTaskRepository(rh.Store))
+	attachmentRepository := repository.NewAttachmentRepository(rh.Store)
+	urlSigner, err := token.NewURLSigner(rh.Config.TokenSymmetricKey)
+	if err != nil {
+		log.Fatalf("[FATAL] error while creating URL signer: %v", err)
+	}
+	attachmentService := service.NewAttachmentService(taskService, attachmentRepository, rh.Blob, urlSigner, rh.Config.PublicURL, rh.Config.AttachmentLinkTTL)
+
+	attachmentHandler := &AttachmentHandler{
+		attachmentService: attachmentService,
+	}
+
+	authMiddleware := middleware.AuthMiddleware(rh.Token, rh.Denylist)
+
+	// protected; uploads are streamed, their body limit is set up with the server
+	api.Post("/tasks/:id/attachments", authMiddleware, attachmentHandler.uploadMultipart)
+	api.Put("/tasks/:id/attachments/:filename", authMiddleware, attachmentHandler.uploadRaw)
+	api.Get("/tasks/:id/attachments", authMiddleware, attachmentHandler.list)
+	api.Get("/attachments/:id", authMiddleware, attachmentHandler.link)
+
+	// public; the signature of the link stands in for the access token
+	api.Get("/attachments/:id/download", attachmentHandler.download)
+}
+
+// @Summary Upload a Task Attachment
+// @Description Uploads a file from the `file` field of a multipart form as an attachment of a task. Only the owner, the assignee or staff may attach files. The content type is detected from the content.
+// @Tags Tasks
+// @Accept mpfd
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param file formData file true "File to attach"
+// @Success 201 {object} dto.StandardResponse{data=dto.AttachmentResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 413 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /tasks/{id}/attachments [post]
+func (ah *AttachmentHandler) uploadMultipart(ctx *fiber.Ctx) error {
+	taskID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	mediaType, params, err := mime.ParseMediaType(ctx.Get(fiber.HeaderContentType))
+	if err != nil || mediaType != fiber.MIMEMultipartForm || params["boundary"] == "" {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrMultipartFileRequired.Error(),
+		})
+	}
+
+	// The parts are read straight from the request stream, skipping any
+	// fields before the file, so the file is never held in memory.
+	reader := multipart.NewReader(middleware.BodyStream(ctx), params["boundary"])
+	var file *multipart.Part
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			if errors.Is(err, middleware.ErrBodyTooLarge) {
+				return attachmentErrorResponse(ctx, err, "")
+			}
+			log.Printf("[ERROR] invalid multipart body: %v", err)
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    ErrMultipartFileRequired.Error(),
+			})
+		}
+		if part.FormName() == "file" && part.FileName() != "" {
+			file = part
+			break
+		}
+	}
+
+	attachment, err := ah.attachmentService.Upload(middleware.AuthActor(ctx), taskID, file.FileName(), file)
+	if err != nil {
+		return attachmentErrorResponse(ctx, err, "failed to upload attachment.")
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewAttachmentResponse(attachment),
+	})
+}
+
+// @Summary Upload a Task Attachment as Raw Body
+// @Description Uploads the raw request body as an attachment of a task under the given file name. Only the owner, the assignee or staff may attach files. The content type is detected from the content.
+// @Tags Tasks
+// @Accept octet-stream
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param filename path string true "File name of the attachment"
+// @Success 201 {object} dto.StandardResponse{data=dto.AttachmentResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 413 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /tasks/{id}/attachments/{filename} [put]
+func (ah *AttachmentHandler) uploadRaw(ctx *fiber.Ctx) error {
+	taskID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	filename, err := url.PathUnescape(ctx.Params("filename"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    service.ErrInvalidAttachmentName.Error(),
+		})
+	}
+
+	attachment, err := ah.attachmentService.Upload(middleware.AuthActor(ctx), taskID, filename, middleware.BodyStream(ctx))
+	if err != nil {
+		return attachmentErrorResponse(ctx, err, "failed to upload attachment.")
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewAttachmentResponse(attachment),
+	})
+}
+
+// @Summary List Task Attachments
+// @Description Returns the attachments of a task visible to the signed-in user, oldest first.
+// @Tags Tasks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Success 200 {object} dto.StandardResponse{data=[]dto.AttachmentResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /tasks/{id}/attachments [get]
+func (ah *AttachmentHandler) list(ctx *fiber.Ctx) error {
+	taskID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	attachments, err := ah.attachmentService.List(middleware.AuthActor(ctx), taskID)
+	if err != nil {
+		return attachmentErrorResponse(ctx, err, "failed to list attachments.")
+	}
+
+	response := make([]dto.AttachmentResponse, 0, len(attachments))
+	for _, attachment := range attachments {
+		response = append(response, dto.NewAttachmentResponse(attachment))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// @Summary Get an Attachment Download Link
+// @Description Returns a short-lived signed link to download an attachment without an access token. Links are only issued to users who may see the attachment's task.
+// @Tags Tasks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Attachment ID"
+// @Success 200 {object} dto.StandardResponse{data=dto.AttachmentLinkResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /attachments/{id} [get]
+func (ah *AttachmentHandler) link(ctx *fiber.Ctx) error {
+	attachmentID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	link, expiresAt, err := ah.attachmentService.DownloadLink(middleware.AuthActor(ctx), attachmentID)
+	if err != nil {
+		return attachmentErrorResponse(ctx, err, "failed to create download link.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data": dto.AttachmentLinkResponse{
+			URL:       link,
+			ExpiresAt: expiresAt,
+		},
+	})
+}
+
+// @Summary Download an Attachment
+// @Description Streams the content of an attachment. Needs no access token, but only accepts the signed links returned by GET /attachments/{id} until they expire.
+// @Tags Tasks
+// @Produce octet-stream
+// @Param id path string true "Attachment ID"
+// @Param expires query int true "Expiry of the link as a Unix timestamp"
+// @Param signature query string true "Signature of the link"
+// @Success 200 {file} file
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /attachments/{id}/download [get]
+func (ah *AttachmentHandler) download(ctx *fiber.Ctx) error {
+	attachmentID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	var query dto.DownloadAttachment
+	if err := ctx.QueryParser(&query); err != nil {
+		log.Printf("[ERROR] invalid query parameters: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    rest.ErrValidationField,
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(query); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	attachment, content, err := ah.attachmentService.Open(attachmentID, query.Expires, query.Signature)
+	if err != nil {
+		return attachmentErrorResponse(ctx, err, "failed to download attachment.")
+	}
+
+	// Uploaded files are always offered as downloads and never rendered, so
+	// a file cannot run scripts in the API's origin.
+	ctx.Set(fiber.HeaderContentType, attachment.ContentType)
+	ctx.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}))
+	ctx.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	ctx.Set(fiber.HeaderCacheControl, "private, no-store")
+
+	return ctx.Status(http.StatusOK).SendStream(content, int(attachment.Size))
+}
+
+// attachmentErrorResponse maps attachment service errors to API responses,
+// falling back to taskErrorResponse for errors of the task itself.
+func attachmentErrorResponse(ctx *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, middleware.ErrBodyTooLarge):
+		return ctx.Status(http.StatusRequestEntityTooLarge).JSON(&fiber.Map{
+			"success": false,
+			"data":    middleware.ErrBodyTooLarge.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidAttachmentName):
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    service.ErrInvalidAttachmentName.Error(),
+		})
+	case errors.Is(err, repository.ErrAttachmentNotFound), errors.Is(err, storage.ErrNotFound):
+		return ctx.Status(http.StatusNotFound).JSON(&fiber.Map{
+			"success": false,
+			"data":    repository.ErrAttachmentNotFound.Error(),
+		})
+	case errors.Is(err, token.ErrInvalidToken), errors.Is(err, token.ErrExpiredToken):
+		return ctx.Status(http.StatusForbidden).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidDownloadLink.Error(),
+		})
+	}
+	return taskErrorResponse(ctx, err, fallback)
+}
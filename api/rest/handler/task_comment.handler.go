@@ -0,0 +1,302 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/vgrigalashvili/veemon/api/rest"
+	"github.com/vgrigalashvili/veemon/api/rest/middleware"
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/dto"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/internal/service"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
+)
+
+type CommentHandler struct {
+	commentService  *service.CommentService
+	activityService *service.ActivityService
+	handleError     func(ctx *fiber.Ctx, err error) error // error handler function for handling API errors.
+}
+
+func InitializeCommentHandler(rh *rest.RestHandler) {
+
+	api := rh.API
+	errorHandler := &rest.DefaultAPIErrorHandler{}
+	taskService := service.NewTaskService(repository.NewTaskRepository(rh.Store))
+	commentService := service.NewCommentService(taskService, repository.NewCommentRepository(rh.Store))
+	activityService := service.NewActivityService(taskService, repository.NewActivityRepository(rh.Store))
+
+	commentHandler := &CommentHandler{
+		commentService:  commentService,
+		activityService: activityService,
+		handleError:     errorHandler.HandleError,
+	}
+
+	authMiddleware := middleware.AuthMiddleware(rh.Token, rh.Denylist)
+
+	// protected
+	api.Post("/tasks/:id/comments", authMiddleware, commentHandler.create)
+	api.Get("/tasks/:id/comments", authMiddleware, commentHandler.list)
+	api.Delete("/tasks/:id/comments/:comment", authMiddleware, commentHandler.delete)
+	api.Get("/tasks/:id/activity", authMiddleware, commentHandler.activity)
+}
+
+// @Summary Comment on a Task
+// @Description Adds a comment of the signed-in user to a task they can see. Set parent_id to reply to another comment on the same task.
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param comment body dto.CreateComment true "Comment Data"
+// @Success 201 {object} dto.StandardResponse{data=dto.CommentResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /tasks/{id}/comments [post]
+func (ch *CommentHandler) create(ctx *fiber.Ctx) error {
+	taskID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	var commentData dto.CreateComment
+	if err := ctx.BodyParser(&commentData); err != nil {
+		log.Printf("[ERROR] invalid request body: %v", err)
+		return ch.handleError(ctx, rest.ErrInvalidRequestJSON)
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(commentData); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	comment, err := ch.commentService.Create(middleware.AuthActor(ctx), taskID, commentData.ParentID, commentData.Body)
+	if err != nil {
+		return commentErrorResponse(ctx, err, "failed to create comment.")
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewCommentResponse(comment),
+	})
+}
+
+// @Summary List Task Comments
+// @Description Returns a page of the comments on a task, oldest first. Replies carry the parent_id of the comment they answer. Deleted comments are kept without their body so their replies stay in the thread.
+// @Tags Comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param cursor query string false "Cursor of the page to return"
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Success 200 {object} dto.StandardResponse{data=dto.CommentListResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /tasks/{id}/comments [get]
+func (ch *CommentHandler) list(ctx *fiber.Ctx) error {
+	taskID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	var query dto.ListComments
+	if err := ctx.QueryParser(&query); err != nil {
+		log.Printf("[ERROR] invalid query parameters: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    rest.ErrValidationField,
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(query); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	filter := domain.CommentFilter{Limit: query.Limit}
+	if query.Cursor != "" {
+		createdAt, id, err := helper.DecodeCursor(query.Cursor)
+		if err != nil {
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    helper.ErrInvalidCursor.Error(),
+			})
+		}
+		filter.CursorCreatedAt = &createdAt
+		filter.CursorID = id
+	}
+
+	comments, nextCursor, err := ch.commentService.List(middleware.AuthActor(ctx), taskID, filter)
+	if err != nil {
+		return commentErrorResponse(ctx, err, "failed to list comments.")
+	}
+
+	response := dto.CommentListResponse{
+		Comments:   make([]dto.CommentResponse, 0, len(comments)),
+		NextCursor: nextCursor,
+	}
+	for _, comment := range comments {
+		response.Comments = append(response.Comments, dto.NewCommentResponse(comment))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// @Summary Delete a Comment
+// @Description Soft deletes a comment and removes it from the task activity feed. Only its author or staff may delete it.
+// @Tags Comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param comment path string true "Comment ID"
+// @Success 200 {object} dto.StandardResponse
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /tasks/{id}/comments/{comment} [delete]
+func (ch *CommentHandler) delete(ctx *fiber.Ctx) error {
+	taskID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+	commentID, err := uuid.Parse(ctx.Params("comment"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	if err := ch.commentService.Delete(middleware.AuthActor(ctx), taskID, commentID); err != nil {
+		return commentErrorResponse(ctx, err, "failed to delete comment.")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    commentID,
+	})
+}
+
+// @Summary Get the Task Activity Feed
+// @Description Returns a page of everything that happened on a task, newest first: status changes, bids, attachments and comments. Bids placed or withdrawn by other users are only listed for the task owner and staff.
+// @Tags Comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param cursor query string false "Cursor of the page to return"
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Success 200 {object} dto.StandardResponse{data=dto.ActivityListResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /tasks/{id}/activity [get]
+func (ch *CommentHandler) activity(ctx *fiber.Ctx) error {
+	taskID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	var query dto.ListActivity
+	if err := ctx.QueryParser(&query); err != nil {
+		log.Printf("[ERROR] invalid query parameters: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    rest.ErrValidationField,
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(query); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    validationErrorData(err),
+		})
+	}
+
+	filter := domain.ActivityFilter{Limit: query.Limit}
+	if query.Cursor != "" {
+		createdAt, id, err := helper.DecodeCursor(query.Cursor)
+		if err != nil {
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    helper.ErrInvalidCursor.Error(),
+			})
+		}
+		filter.CursorCreatedAt = &createdAt
+		filter.CursorID = id
+	}
+
+	activity, nextCursor, err := ch.activityService.List(middleware.AuthActor(ctx), taskID, filter)
+	if err != nil {
+		return commentErrorResponse(ctx, err, "failed to list task activity.")
+	}
+
+	response := dto.ActivityListResponse{
+		Activity:   make([]dto.ActivityResponse, 0, len(activity)),
+		NextCursor: nextCursor,
+	}
+	for _, entry := range activity {
+		response.Activity = append(response.Activity, dto.NewActivityResponse(entry))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// commentErrorResponse maps comment service errors to API responses, falling
+// back to taskErrorResponse for errors of the task itself.
+func commentErrorResponse(ctx *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, repository.ErrCommentNotFound):
+		return ctx.Status(http.StatusNotFound).JSON(&fiber.Map{
+			"success": false,
+			"data":    repository.ErrCommentNotFound.Error(),
+		})
+	case errors.Is(err, service.ErrCommentForbidden):
+		return ctx.Status(http.StatusForbidden).JSON(&fiber.Map{
+			"success": false,
+			"data":    service.ErrCommentForbidden.Error(),
+		})
+	case errors.Is(err, service.ErrEmptyComment), errors.Is(err, service.ErrInvalidParentComment):
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    err.Error(),
+		})
+	}
+	return taskErrorResponse(ctx, err, fallback)
+}
@@ -5,15 +5,18 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/vgrigalashvili/veemon/api/rest"
 	"github.com/vgrigalashvili/veemon/api/rest/middleware"
 	"github.com/vgrigalashvili/veemon/internal/domain"
 	"github.com/vgrigalashvili/veemon/internal/dto"
 	"github.com/vgrigalashvili/veemon/internal/repository"
 	"github.com/vgrigalashvili/veemon/internal/service"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
 )
 
 type UserHandler struct {
@@ -25,20 +28,23 @@ func InitializeUserHandler(rh *rest.RestHandler) {
 
 	api := rh.API
 	errorHandler := &rest.DefaultAPIErrorHandler{}
-	userRepository := repository.NewUserRepository(rh.Querier)
-	userService := service.NewUserService(userRepository)
+	userRepository := repository.NewUserRepository(rh.Store)
+	userService := service.NewUserService(userRepository, rh.PasswordPolicy, rh.TaskDistributor, rh.Denylist, rh.Config.DefaultCallingCode)
 
 	userHandler := &UserHandler{
 		userService: userService,
 		handleError: errorHandler.HandleError,
 	}
 
-	authMiddleware := middleware.AuthMiddleware(rh.Token)
+	authMiddleware := middleware.AuthMiddleware(rh.Token, rh.Denylist)
 
 	// protected
-	api.Post("/user/add", authMiddleware, userHandler.add)
-	// api.Get("/user/get", authMiddleware, userHandler.get)
-	// api.Patch("/user/update", authMiddleware, userHandler.update)
+	api.Get("/users", authMiddleware, middleware.RequirePermission("users:list"), userHandler.list)
+	api.Post("/user/add", authMiddleware, middleware.RequirePermission("users:create"), userHandler.add)
+	api.Get("/user/me", authMiddleware, userHandler.me)
+	api.Get("/user/:id", authMiddleware, middleware.RequirePermissionOrSelf("users:read", "id"), userHandler.get)
+	api.Patch("/user/:id", authMiddleware, middleware.RequirePermissionOrSelf("users:update", "id"), userHandler.update)
+	api.Delete("/user/:id", authMiddleware, middleware.RequirePermissionOrSelf("users:delete", "id"), userHandler.delete)
 }
 
 // @Summary Add a User
@@ -46,9 +52,12 @@ func InitializeUserHandler(rh *rest.RestHandler) {
 // @Tags Users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param user body dto.CreateUser true "User Data"
 // @Success 201 {object} dto.StandardResponse
 // @Failure 400 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 409 {object} dto.StandardResponse
 // @Failure 500 {object} dto.StandardResponse
 // @Router /user/add [post]
 func (uh *UserHandler) add(ctx *fiber.Ctx) error {
@@ -80,17 +89,31 @@ func (uh *UserHandler) add(ctx *fiber.Ctx) error {
 		FirstName: userData.FirstName,
 		LastName:  userData.LastName,
 
-		Email: userData.Email,
-		Role:  userData.Role,
+		Email:    userData.Email,
+		Mobile:   userData.Mobile,
+		Password: userData.Password,
+		Role:     userData.Role,
 	}
 
 	userID, err := uh.userService.Create(user)
 	if err != nil {
 		log.Printf("[ERROR] failed to create user: %v", err)
-		if errors.Is(err, ErrUniqueMobileComplaint) {
+		if errors.Is(err, helper.ErrWeakPassword) || errors.Is(err, helper.ErrInvalidMobile) || errors.Is(err, service.ErrInvalidRole) {
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    err.Error(),
+			})
+		}
+		if errors.Is(err, repository.ErrUserAlreadyExists) {
 			return ctx.Status(http.StatusConflict).JSON(&fiber.Map{
 				"success": false,
-				"data":    ErrUniqueMobileComplaint,
+				"data":    ErrUniqueMobileComplaint.Error(),
+			})
+		}
+		if errors.Is(err, repository.ErrEmailAlreadyExists) {
+			return ctx.Status(http.StatusConflict).JSON(&fiber.Map{
+				"success": false,
+				"data":    repository.ErrEmailAlreadyExists.Error(),
 			})
 		}
 		return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
@@ -105,6 +128,304 @@ func (uh *UserHandler) add(ctx *fiber.Ctx) error {
 	})
 }
 
+// @Summary List Users
+// @Description Returns a page of users, newest first. Pass the returned next_cursor to get the following page.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param role query string false "Filter by role" Enums(admin, operator, user)
+// @Param email_verified query bool false "Filter by email verification"
+// @Param deleted query string false "Filter by deleted state, defaults to false" Enums(true, false, any)
+// @Param search query string false "Case-insensitive search on email and name"
+// @Param cursor query string false "Cursor of the page to return"
+// @Param limit query int false "Page size, 20 by default and at most 100"
+// @Success 200 {object} dto.StandardResponse{data=dto.UserListResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /users [get]
+func (uh *UserHandler) list(ctx *fiber.Ctx) error {
+	var query dto.ListUsers
+	if err := ctx.QueryParser(&query); err != nil {
+		log.Printf("[ERROR] invalid query parameters: %v", err)
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    rest.ErrValidationField,
+		})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(query); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    buildValidationErrorMessages(validationErrors),
+			})
+		}
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    rest.ErrValidationField,
+		})
+	}
+
+	filter := domain.UserFilter{
+		EmailVerified: query.EmailVerified,
+		Search:        strings.TrimSpace(query.Search),
+		Limit:         query.Limit,
+	}
+	if query.Role != "" {
+		filter.Role = &query.Role
+	}
+	switch query.Deleted {
+	case "", "false":
+		deleted := false
+		filter.Deleted = &deleted
+	case "true":
+		deleted := true
+		filter.Deleted = &deleted
+	}
+	if query.Cursor != "" {
+		createdAt, id, err := helper.DecodeCursor(query.Cursor)
+		if err != nil {
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    helper.ErrInvalidCursor.Error(),
+			})
+		}
+		filter.CursorCreatedAt = &createdAt
+		filter.CursorID = id
+	}
+
+	users, nextCursor, err := uh.userService.List(filter)
+	if err != nil {
+		return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
+			"success": false,
+			"data":    "failed to list users.",
+		})
+	}
+
+	response := dto.UserListResponse{
+		Users:      make([]dto.UserResponse, 0, len(users)),
+		NextCursor: nextCursor,
+	}
+	for _, user := range users {
+		response.Users = append(response.Users, dto.NewUserResponse(user))
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// @Summary Get Current User
+// @Description Returns the signed-in user.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.StandardResponse{data=dto.UserResponse}
+// @Failure 401 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /user/me [get]
+func (uh *UserHandler) me(ctx *fiber.Ctx) error {
+	return uh.respondWithUser(ctx, middleware.AuthPayload(ctx).UserID)
+}
+
+// @Summary Get a User
+// @Description Returns a user by ID. Users can read themselves, others need the users:read permission.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.StandardResponse{data=dto.UserResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /user/{id} [get]
+func (uh *UserHandler) get(ctx *fiber.Ctx) error {
+	userID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+	return uh.respondWithUser(ctx, userID)
+}
+
+func (uh *UserHandler) respondWithUser(ctx *fiber.Ctx, userID uuid.UUID) error {
+	user, err := uh.userService.GetBID(userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return ctx.Status(http.StatusNotFound).JSON(&fiber.Map{
+				"success": false,
+				"data":    repository.ErrUserNotFound.Error(),
+			})
+		}
+		return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
+			"success": false,
+			"data":    "failed to get user.",
+		})
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewUserResponse(user),
+	})
+}
+
+// @Summary Update a User
+// @Description Partially updates a user; omitted fields are left unchanged. Changing the role needs the users:update permission. Users changing their own email or password must send their current password. A password change signs the user out everywhere.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param user body dto.UpdateUser true "Fields to Update"
+// @Success 200 {object} dto.StandardResponse{data=dto.UserResponse}
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 409 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /user/{id} [patch]
+func (uh *UserHandler) update(ctx *fiber.Ctx) error {
+	userID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	var userData dto.UpdateUser
+	if err := ctx.BodyParser(&userData); err != nil {
+		log.Printf("[ERROR] invalid request body: %v", err)
+		return uh.handleError(ctx, rest.ErrInvalidRequestJSON)
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(userData); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    buildValidationErrorMessages(validationErrors),
+			})
+		}
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    rest.ErrValidationField,
+		})
+	}
+
+	// Users may edit their own profile, but only admins may change roles.
+	if userData.Role != nil && !domain.HasPermission(middleware.AuthPayload(ctx).Role, domain.PermissionUsersUpdate) {
+		return ctx.Status(http.StatusForbidden).JSON(&fiber.Map{
+			"success": false,
+			"data":    middleware.ErrPermissionDenied.Error(),
+		})
+	}
+
+	user, err := uh.userService.Update(middleware.AuthActor(ctx), userID, domain.UserUpdate{
+		FirstName: userData.FirstName,
+		LastName:  userData.LastName,
+		Email:     userData.Email,
+		Mobile:    userData.Mobile,
+		Password:  userData.Password,
+		Role:      userData.Role,
+	}, userData.CurrentPassword)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrUserNotFound):
+			return ctx.Status(http.StatusNotFound).JSON(&fiber.Map{
+				"success": false,
+				"data":    repository.ErrUserNotFound.Error(),
+			})
+		case errors.Is(err, repository.ErrEmailAlreadyExists):
+			return ctx.Status(http.StatusConflict).JSON(&fiber.Map{
+				"success": false,
+				"data":    repository.ErrEmailAlreadyExists.Error(),
+			})
+		case errors.Is(err, repository.ErrUserAlreadyExists):
+			return ctx.Status(http.StatusConflict).JSON(&fiber.Map{
+				"success": false,
+				"data":    ErrUniqueMobileComplaint.Error(),
+			})
+		case errors.Is(err, service.ErrWrongCurrentPassword):
+			return ctx.Status(http.StatusForbidden).JSON(&fiber.Map{
+				"success": false,
+				"data":    service.ErrWrongCurrentPassword.Error(),
+			})
+		case errors.Is(err, helper.ErrWeakPassword), errors.Is(err, helper.ErrInvalidMobile), errors.Is(err, service.ErrInvalidRole),
+			errors.Is(err, service.ErrCurrentPasswordRequired):
+			return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+				"success": false,
+				"data":    err.Error(),
+			})
+		}
+		return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
+			"success": false,
+			"data":    "failed to update user.",
+		})
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    dto.NewUserResponse(user),
+	})
+}
+
+// @Summary Delete a User
+// @Description Soft deletes a user and signs them out everywhere. Users can delete themselves, others need the users:delete permission.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.StandardResponse
+// @Failure 400 {object} dto.StandardResponse
+// @Failure 403 {object} dto.StandardResponse
+// @Failure 404 {object} dto.StandardResponse
+// @Failure 500 {object} dto.StandardResponse
+// @Router /user/{id} [delete]
+func (uh *UserHandler) delete(ctx *fiber.Ctx) error {
+	userID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+			"success": false,
+			"data":    ErrInvalidUUIDFormat.Error(),
+		})
+	}
+
+	if err := uh.userService.Delete(userID); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return ctx.Status(http.StatusNotFound).JSON(&fiber.Map{
+				"success": false,
+				"data":    repository.ErrUserNotFound.Error(),
+			})
+		}
+		return ctx.Status(http.StatusInternalServerError).JSON(&fiber.Map{
+			"success": false,
+			"data":    "failed to delete user.",
+		})
+	}
+
+	return ctx.Status(http.StatusOK).JSON(&fiber.Map{
+		"success": true,
+		"data":    userID,
+	})
+}
+
+// validationErrorData returns the response data describing a failed validation.
+func validationErrorData(err error) any {
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		return buildValidationErrorMessages(validationErrors)
+	}
+	return rest.ErrValidationField.Error()
+}
+
 func buildValidationErrorMessages(validationErrors validator.ValidationErrors) []string {
 	var validationMessages []string
 	fieldNames := map[string]string{
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/vgrigalashvili/veemon/internal/domain"
 	"github.com/vgrigalashvili/veemon/pkg/token"
 )
 
@@ -14,6 +15,8 @@ var (
 	ErrAuthHeaderRequired               = errors.New("authorization header required")
 	ErrInvalidAuthorizationHeaderFormat = errors.New("authorization header format is not valid")
 	ErrInvalidOrExpiredToken            = errors.New("invalid or expired token")
+	ErrRevokedToken                     = errors.New("token has been revoked")
+	ErrTokenCheckUnavailable            = errors.New("could not check token revocation")
 )
 
 const (
@@ -22,7 +25,7 @@ const (
 	authorizationPayloadKey = "authorization_payload"
 )
 
-func AuthMiddleware(tm token.Maker) fiber.Handler {
+func AuthMiddleware(tm token.Maker, dl token.Denylist) fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
 
 		authHeader := ctx.Get(authorizationHeaderKey)
@@ -57,6 +60,24 @@ func AuthMiddleware(tm token.Maker) fiber.Handler {
 			})
 		}
 
+		revoked, err := dl.IsRevoked(ctx.Context(), payload)
+		if err != nil {
+			log.Printf("[ERROR] Token revocation check failed: %v", err)
+
+			return ctx.Status(http.StatusServiceUnavailable).JSON(&fiber.Map{
+				"success": false,
+				"data":    ErrTokenCheckUnavailable.Error(),
+			})
+		}
+		if revoked {
+			log.Printf("[WARN] Revoked token used: ID=%s", payload.TokenID)
+
+			return ctx.Status(http.StatusUnauthorized).JSON(&fiber.Map{
+				"success": false,
+				"data":    ErrRevokedToken.Error(),
+			})
+		}
+
 		ctx.Locals(authorizationPayloadKey, payload)
 		ctx.Locals("userID", payload.UserID)
 		ctx.Locals("userRole", payload.Role)
@@ -64,3 +85,19 @@ func AuthMiddleware(tm token.Maker) fiber.Handler {
 		return ctx.Next()
 	}
 }
+
+// AuthPayload returns the token payload stored by AuthMiddleware, or nil if
+// the request was not authenticated.
+func AuthPayload(ctx *fiber.Ctx) *token.Payload {
+	payload, _ := ctx.Locals(authorizationPayloadKey).(*token.Payload)
+	return payload
+}
+
+// AuthActor returns the authenticated user of the request as a domain.Actor.
+func AuthActor(ctx *fiber.Ctx) domain.Actor {
+	payload := AuthPayload(ctx)
+	if payload == nil {
+		return domain.Actor{}
+	}
+	return domain.Actor{ID: payload.UserID, Role: payload.Role}
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	ErrBodyTooLarge   = errors.New("request body too large")
+	ErrUnreadableBody = errors.New("request body could not be read")
+)
+
+const bodyLimitKey = "body_limit"
+
+// BodyLimit rejects requests whose body is larger than limit bytes. The server
+// streams request bodies, so every request must pass through a body limit.
+// Register path specific limits with Use before the global one: the first
+// limit a request passes through applies and the later ones are skipped.
+//
+// The body is read into memory before the route runs, so handlers parse it as usual.
+func BodyLimit(limit int) fiber.Handler {
+	return bodyLimit(limit, false)
+}
+
+// StreamingBodyLimit works like BodyLimit, but leaves the body unread for
+// handlers that stream it through BodyStream.
+func StreamingBodyLimit(limit int) fiber.Handler {
+	return bodyLimit(limit, true)
+}
+
+func bodyLimit(limit int, streaming bool) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if ctx.Locals(bodyLimitKey) != nil {
+			return ctx.Next()
+		}
+		ctx.Locals(bodyLimitKey, limit)
+
+		contentLength := ctx.Request().Header.ContentLength()
+		if contentLength > limit {
+			log.Printf("[WARN] request body of %d bytes exceeds the limit of %d bytes", contentLength, limit)
+			return bodyTooLargeResponse(ctx)
+		}
+
+		if !streaming {
+			body, err := io.ReadAll(BodyStream(ctx))
+			if err != nil {
+				if errors.Is(err, ErrBodyTooLarge) {
+					log.Printf("[WARN] chunked request body exceeds the limit of %d bytes", limit)
+					return bodyTooLargeResponse(ctx)
+				}
+				log.Printf("[ERROR] failed to read request body: %v", err)
+				ctx.Context().SetConnectionClose()
+				return ctx.Status(http.StatusBadRequest).JSON(&fiber.Map{
+					"success": false,
+					"data":    ErrUnreadableBody.Error(),
+				})
+			}
+			ctx.Request().SetBody(body)
+			return ctx.Next()
+		}
+
+		// The rest of a streamed body the handler did not read would be taken
+		// for the next request on the connection, so the connection is closed.
+		if contentLength == -1 || contentLength > ctx.App().Config().BodyLimit {
+			ctx.Context().SetConnectionClose()
+		}
+		return ctx.Next()
+	}
+}
+
+// BodyStream returns the request body as a stream that fails with
+// ErrBodyTooLarge once it yields more bytes than the body limit of the route.
+func BodyStream(ctx *fiber.Ctx) io.Reader {
+	limit, _ := ctx.Locals(bodyLimitKey).(int)
+
+	stream := ctx.Request().BodyStream()
+	if stream == nil {
+		stream = bytes.NewReader(ctx.Body())
+	}
+	return &limitedReader{r: stream, n: int64(limit)}
+}
+
+// bodyTooLargeResponse rejects the request without reading its body, so the
+// connection is closed after the response.
+func bodyTooLargeResponse(ctx *fiber.Ctx) error {
+	ctx.Context().SetConnectionClose()
+	return ctx.Status(http.StatusRequestEntityTooLarge).JSON(&fiber.Map{
+		"success": false,
+		"data":    ErrBodyTooLarge.Error(),
+	})
+}
+
+// limitedReader reads from r until n bytes were read, then fails with
+// ErrBodyTooLarge if r has more to give.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (lr *limitedReader) Read(p []byte) (int, error) {
+	if lr.n <= 0 {
+		// Probe for one more byte to tell a body of exactly the limit from a larger one.
+		var probe [1]byte
+		n, err := lr.r.Read(probe[:])
+		if n > 0 {
+			return 0, ErrBodyTooLarge
+		}
+		return 0, err
+	}
+	if int64(len(p)) > lr.n {
+		p = p[:lr.n]
+	}
+	n, err := lr.r.Read(p)
+	lr.n -= int64(n)
+	return n, err
+}
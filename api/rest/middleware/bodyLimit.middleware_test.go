@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestLimitedReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		limit   int64
+		wantErr error
+	}{
+		{"empty body", "", 4, nil},
+		{"empty body without limit", "", 0, nil},
+		{"under the limit", "abc", 4, nil},
+		{"exactly the limit", "abcd", 4, nil},
+		{"one byte over the limit", "abcde", 4, ErrBodyTooLarge},
+		{"far over the limit", strings.Repeat("x", 1024), 4, ErrBodyTooLarge},
+		{"any byte over a zero limit", "a", 0, ErrBodyTooLarge},
+	}
+
+	for _, tt := range tests {
+		readers := map[string]func() io.Reader{
+			"whole":        func() io.Reader { return strings.NewReader(tt.body) },
+			"byte by byte": func() io.Reader { return iotest.OneByteReader(strings.NewReader(tt.body)) },
+			"eof with data": func() io.Reader {
+				return iotest.DataErrReader(strings.NewReader(tt.body))
+			},
+		}
+		for kind, newReader := range readers {
+			t.Run(tt.name+"/"+kind, func(t *testing.T) {
+				got, err := io.ReadAll(&limitedReader{r: newReader(), n: tt.limit})
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ReadAll() error = %v, want %v", err, tt.wantErr)
+				}
+				if tt.wantErr == nil && string(got) != tt.body {
+					t.Errorf("ReadAll() = %q, want %q", got, tt.body)
+				}
+				if int64(len(got)) > tt.limit {
+					t.Errorf("ReadAll() read %d bytes, more than the limit of %d", len(got), tt.limit)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/vgrigalashvili/veemon/internal/domain"
+)
+
+var (
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// RequirePermission only lets requests through whose token role is granted the
+// permission. It must run after AuthMiddleware.
+func RequirePermission(permission string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		payload := AuthPayload(ctx)
+		if payload == nil {
+			log.Println("[WARN] RequirePermission used without AuthMiddleware")
+
+			return ctx.Status(http.StatusUnauthorized).JSON(&fiber.Map{
+				"success": false,
+				"data":    ErrAuthHeaderRequired.Error(),
+			})
+		}
+
+		if !domain.HasPermission(payload.Role, domain.Permission(permission)) {
+			log.Printf("[WARN] Permission %s denied for user %s with role %s", permission, payload.UserID, payload.Role)
+
+			return ctx.Status(http.StatusForbidden).JSON(&fiber.Map{
+				"success": false,
+				"data":    ErrPermissionDenied.Error(),
+			})
+		}
+
+		return ctx.Next()
+	}
+}
+
+// RequirePermissionOrSelf works like RequirePermission, but also lets a user
+// through when the route parameter param holds their own user ID.
+func RequirePermissionOrSelf(permission, param string) fiber.Handler {
+	requirePermission := RequirePermission(permission)
+	return func(ctx *fiber.Ctx) error {
+		payload := AuthPayload(ctx)
+		if payload != nil && ctx.Params(param) == payload.UserID.String() {
+			return ctx.Next()
+		}
+		return requirePermission(ctx)
+	}
+}
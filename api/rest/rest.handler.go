@@ -4,8 +4,14 @@ import (
 	"errors"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/vgrigalashvili/veemon/internal/config"
+	"github.com/vgrigalashvili/veemon/internal/domain"
 	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
+	"github.com/vgrigalashvili/veemon/pkg/mqtt"
+	"github.com/vgrigalashvili/veemon/pkg/storage"
 	"github.com/vgrigalashvili/veemon/pkg/token"
+	"github.com/vgrigalashvili/veemon/pkg/worker"
 )
 
 var (
@@ -21,9 +27,16 @@ var (
 )
 
 type RestHandler struct {
-	API     *fiber.App
-	Querier *db.Queries
-	Token   token.Maker
+	API             *fiber.App
+	Config          config.AppConfig
+	Store           *db.Store
+	Token           token.Maker
+	Denylist        token.Denylist
+	TaskDistributor worker.TaskDistributor
+	PasswordPolicy  helper.PasswordPolicy
+	IncidentPolicy  domain.IncidentPolicy
+	Blob            storage.Blob
+	MQTT            mqtt.Client
 	// ErrorHandler APIErrorHandler
 	// SEC string
 }
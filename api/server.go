@@ -2,24 +2,33 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/go-redis/redis/v8"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/hibiken/asynq"
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	swagger "github.com/swaggo/fiber-swagger"
 	"github.com/vgrigalashvili/veemon/api/rest"
 	"github.com/vgrigalashvili/veemon/api/rest/handler"
+	"github.com/vgrigalashvili/veemon/api/rest/middleware"
 	"github.com/vgrigalashvili/veemon/internal/config"
 	_ "github.com/vgrigalashvili/veemon/internal/docs"
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/internal/service"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
 	"github.com/vgrigalashvili/veemon/pkg/mail"
+	"github.com/vgrigalashvili/veemon/pkg/mqtt"
+	"github.com/vgrigalashvili/veemon/pkg/storage"
 	"github.com/vgrigalashvili/veemon/pkg/token"
 	"github.com/vgrigalashvili/veemon/pkg/worker"
 	"golang.org/x/sync/errgroup"
@@ -27,14 +36,23 @@ import (
 	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
 )
 
+const (
+	defaultBodyLimit = 1 * 1024  // Bodies up to this size are read into memory by the server.
+	taskBodyLimit    = 16 * 1024 // Task descriptions need more room than the rest of the API.
+)
+
 func StartServer(ac config.AppConfig) {
 
+	// Larger bodies are streamed instead of being rejected, so the routes that
+	// accept them can raise their limit; the body limit middleware enforces it.
 	api := fiber.New(fiber.Config{
-		AppName:       "veemon api v1.0.0",
-		CaseSensitive: true,
-		StrictRouting: true,
-		ServerHeader:  "veemon",
-		BodyLimit:     1 * 1024,
+		AppName:                      "veemon api v1.0.0",
+		CaseSensitive:                true,
+		StrictRouting:                true,
+		ServerHeader:                 "veemon",
+		BodyLimit:                    defaultBodyLimit,
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
 	})
 	api.Get("/swagger/*", swagger.WrapHandler)
 
@@ -53,35 +71,106 @@ func StartServer(ac config.AppConfig) {
 		}),
 	)
 
+	// The first body limit a request passes through applies, so the path
+	// specific ones come before the global one.
+	api.Use("/tasks/:id/attachments", middleware.StreamingBodyLimit(ac.AttachmentMaxSize))
+	api.Use("/tasks", middleware.BodyLimit(taskBodyLimit))
+	api.Use(middleware.BodyLimit(defaultBodyLimit))
+
 	ctx, cancel := context.WithCancel(context.Background())
 	waitGroup, ctx := errgroup.WithContext(ctx)
 
-	conn, err := pgx.Connect(ctx, ac.DatabaseURI)
+	connPool, err := pgxpool.New(ctx, ac.DatabaseURI)
 	if err != nil {
 		log.Fatalf("[ERROR] failed to connect to the database: %v", err)
 	}
 	log.Println("[INFO] database connection established successfully")
-	defer conn.Close(ctx)
+	defer connPool.Close()
 
-	queries := db.New(conn)
+	store := db.NewStore(connPool)
 
 	tokenMaker, err := token.NewPasetoMaker(ac.TokenSymmetricKey)
 	if err != nil {
 		log.Fatalf("[FATAL] error while creating Paseto maker: %v", err)
 	}
 
+	blob, err := newBlob(ac)
+	if err != nil {
+		log.Fatalf("[FATAL] error while creating blob storage: %v", err)
+	}
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: ac.RedisAddress,
+	})
+	defer redisClient.Close()
+
+	redisAddr := ac.RedisAddress
+	log.Printf("[DEBUG] redis address: %s", redisAddr)
+	redisOpt := asynq.RedisClientOpt{
+		Addr: redisAddr,
+	}
+
+	taskDistributor := worker.NewRedisTaskDistributor(redisOpt)
+	incidentPolicy := domain.IncidentPolicy{
+		EscalateAfterCritical: ac.IncidentEscalateAfterCritical,
+		EscalateAfterWarning:  ac.IncidentEscalateAfterWarning,
+		MaxEscalationLevel:    ac.IncidentMaxEscalationLevel,
+		ReopenWindow:          ac.IncidentReopenWindow,
+	}
+
+	deviceRepo := repository.NewDeviceRepository(store)
+	incidentService := service.NewIncidentService(
+		repository.NewIncidentRepository(store),
+		repository.NewDeviceContactRepository(store),
+		deviceRepo,
+		taskDistributor,
+		incidentPolicy,
+	)
+	deviceService := service.NewDeviceService(
+		deviceRepo,
+		repository.NewMetricRepository(store),
+		incidentService,
+		ac.HeartbeatInterval,
+		ac.HeartbeatMissedLimit,
+	)
+	eventService := service.NewDeviceEventService(deviceRepo, repository.NewDeviceEventRepository(store), incidentService)
+
+	// The client subscribes the routes once it runs, so the routes of the
+	// services publishing through it are added after creating it.
+	mqttRouter := mqtt.NewRouter()
+	mqttClient, err := newMQTTClient(ac, mqttRouter)
+	if err != nil {
+		log.Fatalf("[FATAL] error while creating MQTT client: %v", err)
+	}
+	commandService := service.NewDeviceCommandService(deviceRepo, repository.NewDeviceCommandRepository(store), mqttClient, ac.CommandTimeout)
+	routeMQTT(mqttRouter, deviceService, eventService, commandService)
+
 	restHandler := &rest.RestHandler{
-		API:     api,
-		Token:   tokenMaker,
-		Querier: queries,
+		API:             api,
+		Config:          ac,
+		Store:           store,
+		Token:           tokenMaker,
+		Denylist:        token.NewRedisDenylist(redisClient, ac.AccessTokenDuration),
+		TaskDistributor: taskDistributor,
+		PasswordPolicy: helper.PasswordPolicy{
+			MinLength:      ac.PasswordMinLength,
+			MinCharClasses: ac.PasswordMinCharClasses,
+		},
+		IncidentPolicy: incidentPolicy,
+		Blob:           blob,
+		MQTT:           mqttClient,
 	}
 	initializeHandler(restHandler)
 
 	mailer := mail.NewSMTPMailer(ac.MailerHost, ac.MailerPort, ac.MailerUserName, ac.MailerPassword, "veemon")
 
-	redisAddr := ac.RedisAddress
-	log.Printf("[DEBUG] redis address: %s", redisAddr)
-	runTaskProcessor(ctx, waitGroup, redisAddr, queries, mailer)
+	runTaskProcessor(ctx, waitGroup, redisOpt, store, mailer, ac.PublicURL)
+	runTaskScheduler(ctx, waitGroup, redisOpt)
+
+	runMQTTClient(ctx, waitGroup, mqttClient)
+	runOfflineWatcher(ctx, waitGroup, deviceService)
+	runIncidentEscalator(ctx, waitGroup, incidentService)
+	runCommandTimeoutWatcher(ctx, waitGroup, commandService)
 
 	waitGroup.Go(func() error {
 		if err := api.Listen(ac.HttpPort); err != nil {
@@ -95,15 +184,37 @@ func StartServer(ac config.AppConfig) {
 }
 
 func initializeHandler(rh *rest.RestHandler) {
+	handler.InitializeHealthHandler(rh)
 	handler.InitializeAuthHandler(rh)
 	handler.InitializeUserHandler(rh)
+	handler.InitializeTaskHandler(rh)
+	handler.InitializeAttachmentHandler(rh)
+	handler.InitializeBidHandler(rh)
+	handler.InitializeCommentHandler(rh)
+	handler.InitializeDeviceHandler(rh)
+	handler.InitializeIncidentHandler(rh)
 }
 
-func runTaskProcessor(ctx context.Context, waitGroup *errgroup.Group, redisAddr string, db *db.Queries, mailer mail.EmailSender) {
-	redisOpt := asynq.RedisClientOpt{
-		Addr: redisAddr,
+// newBlob creates the blob storage selected by STORAGE_DRIVER.
+func newBlob(ac config.AppConfig) (storage.Blob, error) {
+	switch ac.StorageDriver {
+	case "local":
+		return storage.NewLocalBlob(ac.StorageLocalDir)
+	case "s3":
+		return storage.NewS3Blob(storage.S3Config{
+			Endpoint:        ac.S3Endpoint,
+			Region:          ac.S3Region,
+			Bucket:          ac.S3Bucket,
+			AccessKeyID:     ac.S3AccessKeyID,
+			SecretAccessKey: ac.S3SecretAccessKey,
+			PathStyle:       ac.S3PathStyle,
+		})
 	}
-	taskProcessor := worker.NewRedisTaskProcessor(redisOpt, db, mailer)
+	return nil, fmt.Errorf("unknown storage driver %q", ac.StorageDriver)
+}
+
+func runTaskProcessor(ctx context.Context, waitGroup *errgroup.Group, redisOpt asynq.RedisClientOpt, store *db.Store, mailer mail.EmailSender, publicURL string) {
+	taskProcessor := worker.NewRedisTaskProcessor(redisOpt, store, mailer, publicURL)
 
 	waitGroup.Go(func() error {
 		if err := taskProcessor.Start(); err != nil {
@@ -122,6 +233,57 @@ func runTaskProcessor(ctx context.Context, waitGroup *errgroup.Group, redisAddr
 	})
 }
 
+// runTaskScheduler enqueues the periodic maintenance tasks, such as creating
+// the metric partitions of the coming months, until the server shuts down.
+func runTaskScheduler(ctx context.Context, waitGroup *errgroup.Group, redisOpt asynq.RedisClientOpt) {
+	taskScheduler, err := worker.NewRedisTaskScheduler(redisOpt)
+	if err != nil {
+		log.Fatalf("[FATAL] error while creating task scheduler: %v", err)
+	}
+
+	if err := taskScheduler.Start(); err != nil {
+		log.Fatalf("[ERROR] failed to start task scheduler: %v", err)
+	}
+
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Println("[INFO] graceful shutdown of task scheduler...")
+		taskScheduler.Shutdown()
+		log.Println("[INFO] task scheduler stopped.")
+		return nil
+	})
+}
+
+// runOfflineWatcher marks lifts offline when their heartbeats stop, until the
+// server shuts down.
+func runOfflineWatcher(ctx context.Context, waitGroup *errgroup.Group, deviceService *service.DeviceService) {
+	waitGroup.Go(func() error {
+		deviceService.WatchOffline(ctx)
+		log.Println("[INFO] device offline watcher stopped.")
+		return nil
+	})
+}
+
+// runIncidentEscalator escalates incidents nobody acknowledged in time, until
+// the server shuts down.
+func runIncidentEscalator(ctx context.Context, waitGroup *errgroup.Group, incidentService *service.IncidentService) {
+	waitGroup.Go(func() error {
+		incidentService.WatchEscalations(ctx)
+		log.Println("[INFO] incident escalator stopped.")
+		return nil
+	})
+}
+
+// runCommandTimeoutWatcher times out commands lifts did not complete in time,
+// until the server shuts down.
+func runCommandTimeoutWatcher(ctx context.Context, waitGroup *errgroup.Group, commandService *service.DeviceCommandService) {
+	waitGroup.Go(func() error {
+		commandService.WatchTimeouts(ctx)
+		log.Println("[INFO] device command timeout watcher stopped.")
+		return nil
+	})
+}
+
 func handleGracefulShutdown(api *fiber.App, cancel context.CancelFunc, waitGroup *errgroup.Group) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"log"
 	"os"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -23,6 +24,93 @@ type AppConfig struct {
 	MailerUserName    string `mapstructure:"MAILER_USERNAME"`
 	MailerPassword    string `mapstructure:"MAILER_PASSWORD"`
 	TokenSymmetricKey string `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+
+	PublicURL string `mapstructure:"PUBLIC_URL"`
+
+	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+
+	PasswordMinLength      int `mapstructure:"PASSWORD_MIN_LENGTH"`
+	PasswordMinCharClasses int `mapstructure:"PASSWORD_MIN_CHAR_CLASSES"`
+
+	DefaultCallingCode string `mapstructure:"DEFAULT_CALLING_CODE"`
+
+	StorageDriver     string `mapstructure:"STORAGE_DRIVER"` // "local" or "s3".
+	StorageLocalDir   string `mapstructure:"STORAGE_LOCAL_DIR"`
+	S3Endpoint        string `mapstructure:"S3_ENDPOINT"`
+	S3Region          string `mapstructure:"S3_REGION"`
+	S3Bucket          string `mapstructure:"S3_BUCKET"`
+	S3AccessKeyID     string `mapstructure:"S3_ACCESS_KEY_ID"`
+	S3SecretAccessKey string `mapstructure:"S3_SECRET_ACCESS_KEY"`
+	S3PathStyle       bool   `mapstructure:"S3_PATH_STYLE"`
+
+	AttachmentMaxSize int           `mapstructure:"ATTACHMENT_MAX_SIZE"` // In bytes.
+	AttachmentLinkTTL time.Duration `mapstructure:"ATTACHMENT_LINK_TTL"` // Lifetime of signed download links.
+
+	HeartbeatInterval    time.Duration `mapstructure:"HEARTBEAT_INTERVAL"`     // How often lifts publish a heartbeat.
+	HeartbeatMissedLimit int           `mapstructure:"HEARTBEAT_MISSED_LIMIT"` // Missed heartbeats before a lift is offline.
+	CommandTimeout       time.Duration `mapstructure:"COMMAND_TIMEOUT"`        // How long lifts get to complete a command by default.
+
+	MQTTBrokerURL            string        `mapstructure:"MQTT_BROKER_URL"`
+	MQTTClientID             string        `mapstructure:"MQTT_CLIENT_ID"` // Unique per instance, the broker keeps the session under it. Defaults to veemon-<hostname>.
+	MQTTUsername             string        `mapstructure:"MQTT_USERNAME"`
+	MQTTPassword             string        `mapstructure:"MQTT_PASSWORD"`
+	MQTTCAFile               string        `mapstructure:"MQTT_CA_FILE"` // CA of the broker certificate for ssl:// URLs, the system roots when empty.
+	MQTTKeepAlive            time.Duration `mapstructure:"MQTT_KEEPALIVE"`
+	MQTTQoS                  byte          `mapstructure:"MQTT_QOS"`
+	MQTTMaxReconnectInterval time.Duration `mapstructure:"MQTT_MAX_RECONNECT_INTERVAL"`
+
+	IncidentEscalateAfterCritical time.Duration `mapstructure:"INCIDENT_ESCALATE_AFTER_CRITICAL"`
+	IncidentEscalateAfterWarning  time.Duration `mapstructure:"INCIDENT_ESCALATE_AFTER_WARNING"`
+	IncidentMaxEscalationLevel    int           `mapstructure:"INCIDENT_MAX_ESCALATION_LEVEL"`
+	IncidentReopenWindow          time.Duration `mapstructure:"INCIDENT_REOPEN_WINDOW"` // Recurring problems reopen incidents resolved this recently.
+}
+
+// defaultVars holds optional settings and the values used when they are not set.
+var defaultVars = map[string]string{
+	"PUBLIC_URL":             "http://localhost:3000",
+	"ACCESS_TOKEN_DURATION":  "15m",
+	"REFRESH_TOKEN_DURATION": "720h",
+
+	"PASSWORD_MIN_LENGTH":       "10",
+	"PASSWORD_MIN_CHAR_CLASSES": "3",
+
+	"DEFAULT_CALLING_CODE": "995",
+
+	"STORAGE_DRIVER":    "local",
+	"STORAGE_LOCAL_DIR": "data/blobs",
+	"S3_REGION":         "us-east-1",
+	"S3_PATH_STYLE":     "false",
+
+	"ATTACHMENT_MAX_SIZE": "26214400",
+	"ATTACHMENT_LINK_TTL": "5m",
+
+	"HEARTBEAT_INTERVAL":     "30s",
+	"HEARTBEAT_MISSED_LIMIT": "3",
+	"COMMAND_TIMEOUT":        "2m",
+
+	"MQTT_BROKER_URL":             "tcp://localhost:1883",
+	"MQTT_KEEPALIVE":              "30s",
+	"MQTT_QOS":                    "1",
+	"MQTT_MAX_RECONNECT_INTERVAL": "1m",
+
+	"INCIDENT_ESCALATE_AFTER_CRITICAL": "10m",
+	"INCIDENT_ESCALATE_AFTER_WARNING":  "1h",
+	"INCIDENT_MAX_ESCALATION_LEVEL":    "2",
+	"INCIDENT_REOPEN_WINDOW":           "30m",
+}
+
+// optionalVars holds optional settings without a default value. Viper only
+// unmarshals environment variables of keys it knows about, so they are bound
+// explicitly.
+var optionalVars = []string{
+	"S3_ENDPOINT",
+	"S3_BUCKET",
+	"S3_ACCESS_KEY_ID",
+	"S3_SECRET_ACCESS_KEY",
+	"MQTT_USERNAME",
+	"MQTT_PASSWORD",
+	"MQTT_CA_FILE",
 }
 
 func SetupEnvironment() (AppConfig, error) {
@@ -50,6 +138,7 @@ func loadDevelopmentConfig() (AppConfig, error) {
 
 	viper.SetConfigFile("example.env")
 	viper.AutomaticEnv()
+	setDefaults()
 
 	if err := viper.ReadInConfig(); err != nil {
 		return AppConfig{}, errors.New("could not read .env file")
@@ -120,6 +209,38 @@ func loadProductionConfig() (AppConfig, error) {
 		return AppConfig{}, errors.New("TOKEN_SYMMETRIC_KEY must be exactly 32 characters long")
 	}
 
+	setDefaults()
+	viper.AutomaticEnv()
+	if err := viper.Unmarshal(&appConfig); err != nil {
+		return AppConfig{}, errors.New("could not unmarshal optional environment variables")
+	}
+
 	log.Println("[DEBUG] Production environment variables loaded successfully")
 	return appConfig, nil
 }
+
+// setDefaults registers the fallback values of optional settings with viper
+// and binds the optional settings without one to their environment variables.
+func setDefaults() {
+	for key, value := range defaultVars {
+		viper.SetDefault(key, value)
+	}
+	for _, key := range optionalVars {
+		// BindEnv only fails without a key.
+		_ = viper.BindEnv(key)
+	}
+	viper.SetDefault("MQTT_CLIENT_ID", defaultMQTTClientID())
+}
+
+// defaultMQTTClientID derives an MQTT client ID from the hostname, so that
+// instances on different hosts do not take over each other's broker session.
+// It returns an empty ID, which the MQTT client rejects, when the hostname is
+// unknown.
+func defaultMQTTClientID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		log.Printf("[WARN] could not derive the MQTT client ID from the hostname, set MQTT_CLIENT_ID: %v", err)
+		return ""
+	}
+	return "veemon-" + hostname
+}
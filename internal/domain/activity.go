@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of task activity.
+const (
+	ActivityStatusChanged   = "status_changed"
+	ActivityBidPlaced       = "bid_placed"
+	ActivityBidWithdrawn    = "bid_withdrawn"
+	ActivityBidAccepted     = "bid_accepted"
+	ActivityAttachmentAdded = "attachment_added"
+	ActivityCommentAdded    = "comment_added"
+)
+
+// Activity is an entry of the activity feed of a task. It is recorded
+// together with the change it describes.
+type Activity struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+
+	TaskID    uuid.UUID
+	ActorID   uuid.UUID
+	Kind      string
+	SubjectID uuid.UUID      // The bid, attachment or comment the entry is about, uuid.Nil for none.
+	Details   map[string]any // Facts of the event as they were when it happened.
+}
+
+// ActivityFilter selects a page of the activity of a task, newest first. The
+// page starts after the entry identified by CursorCreatedAt and CursorID when
+// set. Bids placed and withdrawn by other users are left out unless AllBids is set.
+type ActivityFilter struct {
+	ViewerID uuid.UUID
+	AllBids  bool
+
+	CursorCreatedAt *time.Time
+	CursorID        uuid.UUID
+	Limit           int
+}
@@ -11,10 +11,14 @@ import (
 type Attachment struct {
 	ID        uuid.UUID `json:"id"`
 	CreatedAt time.Time
-	UpdatedAt time.Time
 	DeletedAt time.Time
 
-	Name string `json:"name"`
-	Type string `json:"type"`
-	Size int    `json:"size"`
+	TaskID     uuid.UUID `json:"task_id"`
+	UploaderID uuid.UUID `json:"uploader_id"`
+
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"` // Sniffed from the content, not taken from the client.
+	Size        int64  `json:"size"`         // In bytes.
+	Checksum    string `json:"sha256"`       // Hex encoded SHA-256 of the content.
+	StorageKey  string `json:"-"`
 }
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vgrigalashvili/veemon/pkg/money"
+)
+
+// Statuses of a bid. Only pending bids can change their status.
+const (
+	BidStatusPending   = "pending"   // Waiting for the task owner to decide.
+	BidStatusAccepted  = "accepted"  // Picked by the owner; the bidder is the task assignee.
+	BidStatusRejected  = "rejected"  // Another bid on the task was accepted.
+	BidStatusWithdrawn = "withdrawn" // Taken back by the bidder.
+)
+
+// Bid is an offer of a user to do a task for an amount of money.
+type Bid struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	TaskID   uuid.UUID
+	BidderID uuid.UUID
+	Amount   money.Money
+	Message  string
+	Status   string
+}
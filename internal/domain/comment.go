@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Comment is a message on a task. Replies point at the comment they answer,
+// so comments form threads.
+type Comment struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	DeletedAt time.Time // Deleted comments keep their place in a thread, without their body.
+
+	TaskID   uuid.UUID
+	AuthorID uuid.UUID
+	ParentID uuid.UUID // The comment replied to, uuid.Nil for a top-level comment.
+	Body     string
+}
+
+// CommentFilter selects a page of the comments on a task, oldest first. The
+// page starts after the comment identified by CursorCreatedAt and CursorID when set.
+type CommentFilter struct {
+	CursorCreatedAt *time.Time
+	CursorID        uuid.UUID
+	Limit           int
+}
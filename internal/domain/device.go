@@ -0,0 +1,94 @@
+package domain
+
+import "time"
+
+// Device is a lift registered in the inventory. Its ID is the one the lift
+// publishes its MQTT messages under.
+type Device struct {
+	ID        string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt time.Time
+
+	Building    string
+	Address     string
+	Model       string
+	InstallDate time.Time // Zero when unknown.
+	OwnerOrg    string    // The organization operating the lift.
+}
+
+// DeviceUpdate holds the fields of a partial device update. Nil fields are left unchanged.
+type DeviceUpdate struct {
+	Building    *string
+	Address     *string
+	Model       *string
+	InstallDate *time.Time
+	OwnerOrg    *string
+}
+
+// DeviceFilter selects a page of devices ordered by ID. Nil filters match
+// every device. The page starts after the device CursorID when set.
+type DeviceFilter struct {
+	Building *string
+	OwnerOrg *string
+
+	CursorID string
+	Limit    int
+}
+
+// States of a device, derived from its heartbeats.
+const (
+	DeviceStateUnknown = "unknown" // No heartbeat was ever received.
+	DeviceStateOnline  = "online"
+	DeviceStateOffline = "offline" // Too many heartbeat intervals passed without one.
+)
+
+// Heartbeat is the payload a lift publishes on Lift/<device ID>/events/heartbeat.
+type Heartbeat struct {
+	Timestamp time.Time          `json:"timestamp"` // By the lift's clock, zero when not sent.
+	Firmware  string             `json:"firmware"`
+	Metrics   map[string]float64 `json:"metrics"`
+}
+
+// DeviceStatus is the live state of a device.
+type DeviceStatus struct {
+	DeviceID       string
+	State          string
+	StateChangedAt time.Time // Zero while the state is unknown.
+	LastSeenAt     time.Time // When the last heartbeat was received, zero if never.
+	ReportedAt     time.Time // The timestamp of the last heartbeat by the lift's clock.
+	Firmware       string
+	Metrics        map[string]float64 // From the last heartbeat.
+}
+
+// MetricsReport is the payload a lift publishes on Lift/<device ID>/events/metrics.
+type MetricsReport struct {
+	Timestamp time.Time          `json:"timestamp"` // By the lift's clock, the time of receipt when not sent.
+	Metrics   map[string]float64 `json:"metrics"`
+}
+
+// MetricQuery selects the samples of a device between From, inclusive, and
+// To, exclusive, downsampled into buckets of Step starting at From. A nil Name
+// selects every metric.
+type MetricQuery struct {
+	Name *string
+	From time.Time
+	To   time.Time
+	Step time.Duration
+}
+
+// MetricBucket summarizes the samples of a metric within one step.
+type MetricBucket struct {
+	Start   time.Time
+	Avg     float64
+	Min     float64
+	Max     float64
+	Samples int64
+}
+
+// MetricSeries holds the buckets of one metric, oldest first. Buckets without
+// samples are left out.
+type MetricSeries struct {
+	Name    string
+	Buckets []MetricBucket
+}
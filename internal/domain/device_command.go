@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Commands lifts accept, each published on Lift/<device ID>/commands/<name>.
+const (
+	DeviceCommandReboot          = "reboot"
+	DeviceCommandDiagnostics     = "diagnostics"      // The lift replies with a diagnostics report as the result.
+	DeviceCommandMaintenanceMode = "maintenance_mode" // Takes an enabled argument.
+)
+
+// Command statuses. A command is pending until the lift acknowledges it and
+// ends completed, failed or timed out.
+const (
+	CommandPending   = "pending"
+	CommandAcked     = "acked"
+	CommandCompleted = "completed"
+	CommandFailed    = "failed"
+	CommandTimedOut  = "timed_out"
+)
+
+// DeviceCommand is a command sent to a lift and its progress.
+type DeviceCommand struct {
+	ID        uuid.UUID // The correlation ID of the command.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	DeviceID  string
+	Name      string
+	Args      map[string]any
+	Status    string
+	IssuedBy  uuid.UUID
+	ExpiresAt time.Time // Commands without a final reply by then time out.
+
+	AckedAt     time.Time
+	CompletedAt time.Time      // When the command completed, failed or timed out.
+	Result      map[string]any // What the lift replied on completion, if anything.
+	Error       string         // Why the command failed.
+}
+
+// DeviceCommandFilter selects a page of the commands of a device, newest
+// first. A nil Status matches every status. The page starts after the command
+// identified by CursorCreatedAt and CursorID when set.
+type DeviceCommandFilter struct {
+	Status *string
+
+	CursorCreatedAt *time.Time
+	CursorID        uuid.UUID
+	Limit           int
+}
+
+// CommandMessage is the payload of a command published to a lift.
+type CommandMessage struct {
+	CorrelationID uuid.UUID      `json:"correlation_id"`
+	Args          map[string]any `json:"args"`
+	IssuedAt      time.Time      `json:"issued_at"`
+	ExpiresAt     time.Time      `json:"expires_at"` // Lifts should not run the command after this.
+}
+
+// CommandReply is the payload of a reply of a lift on
+// Lift/<device ID>/commands/<name>/reply. Lifts reply acked when they start
+// the command, then completed or failed.
+type CommandReply struct {
+	CorrelationID uuid.UUID      `json:"correlation_id"`
+	Status        string         `json:"status"`
+	Result        map[string]any `json:"result"`
+	Error         string         `json:"error"`
+}
+
+func (r CommandReply) Validate() error {
+	if r.CorrelationID == uuid.Nil {
+		return errors.New("correlation_id is required")
+	}
+	switch r.Status {
+	case CommandAcked, CommandCompleted, CommandFailed:
+		return nil
+	}
+	return errors.New("status must be acked, completed or failed")
+}
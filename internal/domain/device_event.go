@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of device events, each published on Lift/<device ID>/events/<kind>.
+const (
+	DeviceEventDoor  = "door"
+	DeviceEventFault = "fault"
+	DeviceEventTrip  = "trip"
+	DeviceEventAlarm = "alarm"
+)
+
+// DeviceEvent is an event a lift reported, stored as it was received.
+type DeviceEvent struct {
+	ID        uuid.UUID
+	CreatedAt time.Time // When the event was received.
+
+	DeviceID   string
+	Kind       string
+	OccurredAt time.Time      // By the lift's clock.
+	Details    map[string]any // The payload fields of the kind.
+}
+
+// DeviceEventFilter selects a page of the events of a device, newest first.
+// A nil Kind matches every kind. The page starts after the event identified
+// by CursorCreatedAt and CursorID when set.
+type DeviceEventFilter struct {
+	Kind *string
+
+	CursorCreatedAt *time.Time
+	CursorID        uuid.UUID
+	Limit           int
+}
+
+// Door actions.
+const (
+	DoorOpened     = "opened"
+	DoorClosed     = "closed"
+	DoorObstructed = "obstructed" // The door could not close.
+)
+
+// Fault severities.
+const (
+	FaultInfo     = "info"
+	FaultWarning  = "warning"
+	FaultCritical = "critical" // The lift is out of service.
+)
+
+// faultCodePattern bounds fault codes, which key incidents and end up in the
+// subject of notification emails.
+var faultCodePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// DoorEvent is the payload of a door event.
+type DoorEvent struct {
+	Timestamp time.Time `json:"timestamp"` // By the lift's clock, the time of receipt when not sent.
+	Floor     int       `json:"floor"`
+	Action    string    `json:"action"`
+}
+
+func (e DoorEvent) Validate() error {
+	switch e.Action {
+	case DoorOpened, DoorClosed, DoorObstructed:
+		return nil
+	}
+	return errors.New("action must be opened, closed or obstructed")
+}
+
+// FaultEvent is the payload of a fault event, reported when the controller of
+// a lift detects a fault.
+type FaultEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Code      string    `json:"code"` // Controller specific fault code.
+	Severity  string    `json:"severity"`
+	Message   string    `json:"message"`
+}
+
+func (e FaultEvent) Validate() error {
+	if !faultCodePattern.MatchString(e.Code) {
+		return errors.New("code must be 1 to 64 letters, digits, dots, dashes or underscores")
+	}
+	switch e.Severity {
+	case FaultInfo, FaultWarning, FaultCritical:
+		return nil
+	}
+	return errors.New("severity must be info, warning or critical")
+}
+
+// TripEvent is the payload of a trip event, reported when a lift arrives.
+type TripEvent struct {
+	Timestamp       time.Time `json:"timestamp"`
+	FromFloor       int       `json:"from_floor"`
+	ToFloor         int       `json:"to_floor"`
+	DurationSeconds float64   `json:"duration_seconds"`
+}
+
+func (e TripEvent) Validate() error {
+	if e.DurationSeconds < 0 {
+		return errors.New("duration_seconds must not be negative")
+	}
+	return nil
+}
+
+// AlarmEvent is the payload of an alarm event, such as a passenger pressing
+// the emergency button. Lifts report the alarm again with Active unset once
+// it is cleared.
+type AlarmEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Type      string    `json:"type"` // Such as emergency_button or trapped_passenger.
+	Active    bool      `json:"active"`
+}
+
+func (e AlarmEvent) Validate() error {
+	if e.Type == "" {
+		return errors.New("type is required")
+	}
+	return nil
+}
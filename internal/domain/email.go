@@ -1,6 +1,18 @@
 package domain
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
 type VerifyEmail struct {
-	Email      string `json:"email" gorm:"index;unique"`
-	SecretCode int    `json:"secret_code"`
+	ID        int64 `json:"id"`
+	CreatedAt time.Time
+	ExpiredAt time.Time
+
+	UserID         uuid.UUID `json:"user_id"`
+	Email          string    `json:"email"`
+	SecretCodeHash string    `json:"-"`
+	IsUsed         bool      `json:"is_used"`
 }
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of incidents.
+const (
+	IncidentFault   = "fault"   // The lift reported a warning or critical fault.
+	IncidentOffline = "offline" // The lift stopped sending heartbeats.
+)
+
+// Incident severities. Info faults do not open incidents.
+const (
+	IncidentSeverityWarning  = FaultWarning
+	IncidentSeverityCritical = FaultCritical
+)
+
+// Incident statuses. Acknowledged incidents no longer escalate.
+const (
+	IncidentStatusOpen         = "open"
+	IncidentStatusAcknowledged = "acknowledged"
+	IncidentStatusResolved     = "resolved"
+)
+
+// Incident is a problem of a lift someone has to take care of. A lift has at
+// most one unresolved incident per kind and code; the problem happening again
+// counts as another occurrence of it.
+type Incident struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	DeviceID    string
+	Kind        string
+	Code        string // The fault code, or the kind for other incidents.
+	Severity    string
+	Status      string
+	Summary     string
+	Occurrences int
+	OpenedAt    time.Time // When the incident opened, or last reopened.
+	LastSeenAt  time.Time // When the problem last happened.
+
+	EscalationLevel int
+	EscalatedAt     time.Time // When the incident last escalated or became critical, zero until then.
+
+	AcknowledgedBy uuid.UUID
+	AcknowledgedAt time.Time
+	ResolvedBy     uuid.UUID
+	ResolvedAt     time.Time
+}
+
+// IncidentOccurrence is a problem of a lift that opens an incident, or counts
+// as another occurrence of the unresolved one.
+type IncidentOccurrence struct {
+	DeviceID string
+	Kind     string
+	Code     string
+	Severity string
+	Summary  string
+}
+
+// IncidentChange tells how an occurrence changed its incident.
+type IncidentChange int
+
+const (
+	IncidentRecurred IncidentChange = iota // Another occurrence of an open incident.
+	IncidentOpened                         // The incident opened, or reopened after being resolved.
+	IncidentRaised                         // A warning incident became critical.
+)
+
+// Recurrence tells how another occurrence of the given severity changes the
+// incident: a resolved incident reopens, and a warning incident becomes
+// critical. The incident must still be unresolved or within the reopen window.
+func (i *Incident) Recurrence(severity string) IncidentChange {
+	switch {
+	case i.Status == IncidentStatusResolved:
+		return IncidentOpened
+	case i.Severity != IncidentSeverityCritical && severity == IncidentSeverityCritical:
+		return IncidentRaised
+	}
+	return IncidentRecurred
+}
+
+// IncidentFilter selects a page of incidents, newest first. Nil filters match
+// every incident. The page starts after the incident identified by
+// CursorCreatedAt and CursorID when set.
+type IncidentFilter struct {
+	Status   *string
+	DeviceID *string
+	Severity *string
+
+	CursorCreatedAt *time.Time
+	CursorID        uuid.UUID
+	Limit           int
+}
+
+// DeviceContact is a user notified of the incidents of a lift. Contacts of
+// level 0 are notified when an incident opens, contacts of higher levels once
+// it escalated that far.
+type DeviceContact struct {
+	DeviceID        string
+	UserID          uuid.UUID
+	CreatedAt       time.Time
+	EscalationLevel int
+}
+
+// IncidentPolicy configures how incidents escalate and recur.
+type IncidentPolicy struct {
+	EscalateAfterCritical time.Duration // Unacknowledged critical incidents escalate after this long.
+	EscalateAfterWarning  time.Duration // Unacknowledged warning incidents escalate after this long.
+	MaxEscalationLevel    int
+	ReopenWindow          time.Duration // Recurring problems reopen incidents resolved this recently.
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "testing"
+
+func TestIncidentRecurrence(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   string
+		severity string
+		occurred string
+		want     IncidentChange
+	}{
+		{"open warning recurs", IncidentStatusOpen, IncidentSeverityWarning, IncidentSeverityWarning, IncidentRecurred},
+		{"open critical recurs", IncidentStatusOpen, IncidentSeverityCritical, IncidentSeverityCritical, IncidentRecurred},
+		{"warning on critical recurs", IncidentStatusOpen, IncidentSeverityCritical, IncidentSeverityWarning, IncidentRecurred},
+		{"acknowledged recurs", IncidentStatusAcknowledged, IncidentSeverityWarning, IncidentSeverityWarning, IncidentRecurred},
+		{"open warning raised", IncidentStatusOpen, IncidentSeverityWarning, IncidentSeverityCritical, IncidentRaised},
+		{"acknowledged warning raised", IncidentStatusAcknowledged, IncidentSeverityWarning, IncidentSeverityCritical, IncidentRaised},
+		{"resolved reopens", IncidentStatusResolved, IncidentSeverityWarning, IncidentSeverityWarning, IncidentOpened},
+		{"resolved reopens as critical", IncidentStatusResolved, IncidentSeverityWarning, IncidentSeverityCritical, IncidentOpened},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incident := &Incident{Status: tt.status, Severity: tt.severity}
+			if got := incident.Recurrence(tt.occurred); got != tt.want {
+				t.Errorf("Recurrence(%q) = %d, want %d", tt.occurred, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Roles a user can have. New users get RoleUser unless an admin picks another role.
+const (
+	RoleAdmin    = "admin"    // Full access, manages users.
+	RoleOperator = "operator" // Support staff, can look users up and list them.
+	RoleUser     = "user"     // Regular signed-up user.
+)
+
+// Permission names an action on a resource, written as "resource:action".
+type Permission string
+
+const (
+	PermissionUsersCreate Permission = "users:create"
+	PermissionUsersRead   Permission = "users:read"
+	PermissionUsersUpdate Permission = "users:update"
+	PermissionUsersDelete Permission = "users:delete"
+	PermissionUsersList   Permission = "users:list"
+
+	PermissionTasksCreate   Permission = "tasks:create"
+	PermissionTasksRead     Permission = "tasks:read"     // Read private tasks of other users.
+	PermissionTasksUpdate   Permission = "tasks:update"   // Update tasks of other users.
+	PermissionTasksDelete   Permission = "tasks:delete"   // Delete tasks of other users.
+	PermissionTasksModerate Permission = "tasks:moderate" // Cancel tasks and settle disputes.
+
+	PermissionDevicesRead    Permission = "devices:read"
+	PermissionDevicesManage  Permission = "devices:manage"  // Register, update and remove devices.
+	PermissionDevicesCommand Permission = "devices:command" // Send remote commands to lifts.
+
+	PermissionIncidentsRead   Permission = "incidents:read"
+	PermissionIncidentsManage Permission = "incidents:manage" // Acknowledge and resolve incidents.
+)
+
+// rolePermissions is the permission matrix: the permissions granted to each role.
+// Roles missing from the matrix have no permissions.
+var rolePermissions = map[string][]Permission{
+	RoleAdmin: {
+		PermissionUsersCreate,
+		PermissionUsersRead,
+		PermissionUsersUpdate,
+		PermissionUsersDelete,
+		PermissionUsersList,
+		PermissionTasksCreate,
+		PermissionTasksRead,
+		PermissionTasksUpdate,
+		PermissionTasksDelete,
+		PermissionTasksModerate,
+		PermissionDevicesRead,
+		PermissionDevicesManage,
+		PermissionDevicesCommand,
+		PermissionIncidentsRead,
+		PermissionIncidentsManage,
+	},
+	RoleOperator: {
+		PermissionUsersRead,
+		PermissionUsersList,
+		PermissionTasksCreate,
+		PermissionTasksRead,
+		PermissionTasksModerate,
+		PermissionDevicesRead,
+		PermissionDevicesCommand,
+		PermissionIncidentsRead,
+		PermissionIncidentsManage,
+	},
+	RoleUser: {
+		PermissionTasksCreate,
+	},
+}
+
+// ValidRole reports whether the role is part of the permission matrix.
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// HasPermission reports whether the role is granted the permission.
+func HasPermission(role string, permission Permission) bool {
+	for _, granted := range rolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Actor is the authenticated user performing an action.
+type Actor struct {
+	ID   uuid.UUID
+	Role string
+}
+
+// Can reports whether the actor's role is granted the permission.
+func (a Actor) Can(permission Permission) bool {
+	return HasPermission(a.Role, permission)
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a refresh token issued to a user. Sessions that were rotated from
+// the same sign-in share a FamilyID.
+type Session struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	RevokedAt time.Time
+
+	FamilyID         uuid.UUID `json:"family_id"`
+	UserID           uuid.UUID `json:"user_id"`
+	RefreshTokenHash string    `json:"-"`
+	UserAgent        string    `json:"user_agent"`
+	ClientIP         string    `json:"client_ip"`
+	ReplacedBy       uuid.UUID `json:"replaced_by"`
+}
+
+// Revoked reports whether the session was revoked or already rotated.
+func (s *Session) Revoked() bool {
+	return !s.RevokedAt.IsZero()
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/vgrigalashvili/veemon/pkg/money"
 )
 
 type Task struct {
@@ -12,12 +13,83 @@ type Task struct {
 	UpdatedAt time.Time // The timestamp when the record was last updated.
 	DeletedAt time.Time // Soft delete field with an index for querying.
 
+	OwnerID    uuid.UUID `json:"owner_id"`    // The user who created the task.
+	AssigneeID uuid.UUID `json:"assignee_id"` // The user working on the task, uuid.Nil for none.
+
 	Public      bool   `json:"public"`      // Indicates if the task is public or private.
 	Title       string `json:"title"`       // Title of the task
 	Description string `json:"description"` // Description of the task
+	Category    string `json:"category"`    // Category of the task
 	Location    string `json:"location"`    // Location of the task
 	Address     string `json:"address"`     // Address of the task
-	DeadLine    string `json:"dead_line"`   // Deadline of the task
-	Budget      int    `json:"budget"`      // Budget of the task
 	Status      string `json:"status"`      // Status of the task
+
+	Deadline time.Time   `json:"deadline"` // Deadline of the task, zero when there is none
+	Budget   money.Money `json:"budget"`   // Budget of the task
+
+	Latitude  *float64 `json:"latitude"`  // Latitude of the task location, nil when unknown
+	Longitude *float64 `json:"longitude"` // Longitude of the task location, nil when unknown
+}
+
+// TaskUpdate holds the fields of a partial task update. Nil fields are left unchanged.
+type TaskUpdate struct {
+	Public      *bool
+	Title       *string
+	Description *string
+	Category    *string
+	Location    *string
+	Address     *string
+	Deadline    *time.Time
+	Budget      *money.Money
+	Latitude    *float64
+	Longitude   *float64
+}
+
+// TaskFilter selects a page of the tasks visible to a user. The page starts
+// after the task identified by CursorCreatedAt and CursorID when set.
+type TaskFilter struct {
+	OwnerID *uuid.UUID
+
+	CursorCreatedAt *time.Time
+	CursorID        uuid.UUID
+	Limit           int
+}
+
+// TaskSearch selects a page of the tasks visible to a user. Zero fields match
+// every task. Budget bounds are in the minor units of BudgetCurrency.
+type TaskSearch struct {
+	Query          string
+	Category       string
+	BudgetCurrency money.Currency
+	BudgetMin      *int64
+	BudgetMax      *int64
+	DeadlineFrom   *time.Time
+	DeadlineTo     *time.Time
+	Near           *GeoRadius
+
+	CursorCreatedAt *time.Time
+	CursorID        uuid.UUID
+	Limit           int
+}
+
+// GeoRadius is the circle of RadiusKm kilometers around a point.
+type GeoRadius struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+}
+
+// Parties returns the parties the actor is to the task.
+func (t *Task) Parties(actor Actor) TaskParty {
+	var parties TaskParty
+	if t.OwnerID == actor.ID {
+		parties |= TaskPartyOwner
+	}
+	if t.AssigneeID != uuid.Nil && t.AssigneeID == actor.ID {
+		parties |= TaskPartyAssignee
+	}
+	if actor.Can(PermissionTasksModerate) {
+		parties |= TaskPartyStaff
+	}
+	return parties
 }
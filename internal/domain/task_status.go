@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Statuses of the task lifecycle. New tasks start as drafts.
+const (
+	TaskStatusDraft      = "draft"       // Being written, not visible to bidders yet.
+	TaskStatusOpen       = "open"        // Published and waiting for an assignee.
+	TaskStatusAssigned   = "assigned"    // An assignee was picked but has not started.
+	TaskStatusInProgress = "in_progress" // The assignee is working on the task.
+	TaskStatusCompleted  = "completed"   // Done and accepted by the owner. Final.
+	TaskStatusCancelled  = "cancelled"   // Withdrawn before completion. Final.
+	TaskStatusDisputed   = "disputed"    // Owner and assignee disagree, staff decides.
+)
+
+// TaskParty is a set of the parties a user can be to a task.
+type TaskParty int
+
+const (
+	TaskPartyOwner    TaskParty = 1 << iota // The user who created the task.
+	TaskPartyAssignee                       // The user assigned to the task.
+	TaskPartyStaff                          // A user with the tasks:moderate permission.
+)
+
+// taskTransitions lists the allowed status changes and the parties that may make them.
+var taskTransitions = map[string]map[string]TaskParty{
+	TaskStatusDraft: {
+		TaskStatusOpen:      TaskPartyOwner,
+		TaskStatusCancelled: TaskPartyOwner | TaskPartyStaff,
+	},
+	TaskStatusOpen: {
+		TaskStatusDraft:     TaskPartyOwner,
+		TaskStatusAssigned:  TaskPartyOwner,
+		TaskStatusCancelled: TaskPartyOwner | TaskPartyStaff,
+	},
+	TaskStatusAssigned: {
+		TaskStatusInProgress: TaskPartyAssignee,
+		TaskStatusOpen:       TaskPartyOwner | TaskPartyAssignee,
+		TaskStatusCancelled:  TaskPartyOwner | TaskPartyStaff,
+	},
+	TaskStatusInProgress: {
+		TaskStatusCompleted: TaskPartyOwner,
+		TaskStatusDisputed:  TaskPartyOwner | TaskPartyAssignee,
+	},
+	TaskStatusDisputed: {
+		TaskStatusCompleted: TaskPartyStaff,
+		TaskStatusCancelled: TaskPartyStaff,
+	},
+}
+
+// ValidTaskStatus reports whether the status is part of the task lifecycle.
+func ValidTaskStatus(status string) bool {
+	switch status {
+	case TaskStatusDraft, TaskStatusOpen, TaskStatusAssigned, TaskStatusInProgress,
+		TaskStatusCompleted, TaskStatusCancelled, TaskStatusDisputed:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTask reports whether a task may move from one status to another.
+func CanTransitionTask(from, to string) bool {
+	_, ok := taskTransitions[from][to]
+	return ok
+}
+
+// TaskTransitionAllowed reports whether any of the parties may move a task
+// from one status to another.
+func TaskTransitionAllowed(from, to string, parties TaskParty) bool {
+	return taskTransitions[from][to]&parties != 0
+}
+
+// TaskTransition is a status change of a task, as recorded in its history.
+type TaskTransition struct {
+	ID        int64
+	CreatedAt time.Time
+
+	TaskID     uuid.UUID
+	From       string
+	To         string
+	ActorID    uuid.UUID
+	AssigneeID uuid.UUID // The assignee after the change, uuid.Nil for none.
+	Reason     string
+}
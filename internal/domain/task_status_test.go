@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTaskTransitionAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		parties TaskParty
+		want    bool
+	}{
+		{"owner publishes draft", TaskStatusDraft, TaskStatusOpen, TaskPartyOwner, true},
+		{"staff cannot publish draft", TaskStatusDraft, TaskStatusOpen, TaskPartyStaff, false},
+		{"staff cancels draft", TaskStatusDraft, TaskStatusCancelled, TaskPartyStaff, true},
+		{"owner assigns open task", TaskStatusOpen, TaskStatusAssigned, TaskPartyOwner, true},
+		{"staff cannot assign open task", TaskStatusOpen, TaskStatusAssigned, TaskPartyStaff, false},
+		{"assignee starts work", TaskStatusAssigned, TaskStatusInProgress, TaskPartyAssignee, true},
+		{"owner cannot start work", TaskStatusAssigned, TaskStatusInProgress, TaskPartyOwner, false},
+		{"assignee gives task back", TaskStatusAssigned, TaskStatusOpen, TaskPartyAssignee, true},
+		{"owner completes task", TaskStatusInProgress, TaskStatusCompleted, TaskPartyOwner, true},
+		{"assignee cannot complete task", TaskStatusInProgress, TaskStatusCompleted, TaskPartyAssignee, false},
+		{"assignee disputes task", TaskStatusInProgress, TaskStatusDisputed, TaskPartyAssignee, true},
+		{"staff settles dispute", TaskStatusDisputed, TaskStatusCompleted, TaskPartyStaff, true},
+		{"owner cannot settle dispute", TaskStatusDisputed, TaskStatusCompleted, TaskPartyOwner, false},
+		{"any of several parties", TaskStatusAssigned, TaskStatusInProgress, TaskPartyOwner | TaskPartyAssignee, true},
+		{"no party", TaskStatusDraft, TaskStatusOpen, 0, false},
+		{"completed is final", TaskStatusCompleted, TaskStatusOpen, TaskPartyOwner | TaskPartyAssignee | TaskPartyStaff, false},
+		{"cancelled is final", TaskStatusCancelled, TaskStatusDraft, TaskPartyOwner | TaskPartyAssignee | TaskPartyStaff, false},
+		{"skipping a status", TaskStatusDraft, TaskStatusAssigned, TaskPartyOwner, false},
+		{"unknown status", "archived", TaskStatusOpen, TaskPartyOwner, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TaskTransitionAllowed(tt.from, tt.to, tt.parties); got != tt.want {
+				t.Errorf("TaskTransitionAllowed(%q, %q, %b) = %v, want %v", tt.from, tt.to, tt.parties, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanTransitionTask(t *testing.T) {
+	tests := []struct {
+		from string
+		to   string
+		want bool
+	}{
+		{TaskStatusDraft, TaskStatusOpen, true},
+		{TaskStatusOpen, TaskStatusDraft, true},
+		{TaskStatusAssigned, TaskStatusOpen, true},
+		{TaskStatusInProgress, TaskStatusDisputed, true},
+		{TaskStatusDisputed, TaskStatusCancelled, true},
+		{TaskStatusInProgress, TaskStatusCancelled, false},
+		{TaskStatusCompleted, TaskStatusDisputed, false},
+		{TaskStatusOpen, TaskStatusOpen, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			if got := CanTransitionTask(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransitionTask(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidTaskStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{TaskStatusDraft, true},
+		{TaskStatusOpen, true},
+		{TaskStatusAssigned, true},
+		{TaskStatusInProgress, true},
+		{TaskStatusCompleted, true},
+		{TaskStatusCancelled, true},
+		{TaskStatusDisputed, true},
+		{"", false},
+		{"Open", false},
+		{"archived", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			if got := ValidTaskStatus(tt.status); got != tt.want {
+				t.Errorf("ValidTaskStatus(%q) = %v, want %v", tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskParties(t *testing.T) {
+	owner := uuid.New()
+	assignee := uuid.New()
+	task := &Task{OwnerID: owner, AssigneeID: assignee}
+
+	tests := []struct {
+		name  string
+		actor Actor
+		want  TaskParty
+	}{
+		{"owner", Actor{ID: owner, Role: RoleUser}, TaskPartyOwner},
+		{"assignee", Actor{ID: assignee, Role: RoleUser}, TaskPartyAssignee},
+		{"operator", Actor{ID: uuid.New(), Role: RoleOperator}, TaskPartyStaff},
+		{"owner with staff role", Actor{ID: owner, Role: RoleAdmin}, TaskPartyOwner | TaskPartyStaff},
+		{"stranger", Actor{ID: uuid.New(), Role: RoleUser}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := task.Parties(tt.actor); got != tt.want {
+				t.Errorf("Parties() = %b, want %b", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("unassigned task has no assignee", func(t *testing.T) {
+		unassigned := &Task{OwnerID: owner}
+		if got := unassigned.Parties(Actor{ID: uuid.Nil, Role: RoleUser}); got != 0 {
+			t.Errorf("Parties() = %b, want 0", got)
+		}
+	})
+}
@@ -15,7 +15,31 @@ type User struct {
 	FirstName      string `json:"first_name"`
 	LastName       string `json:"last_name"`
 	Email          string `json:"email"`
+	Mobile         string `json:"mobile"`
 	Password       string `json:"password"`
 	Role           string `json:"role"`
 	Email_verified bool   `json:"email_verified"`
 }
+
+// UserUpdate holds the fields of a partial user update. Nil fields are left unchanged.
+type UserUpdate struct {
+	FirstName *string
+	LastName  *string
+	Email     *string
+	Mobile    *string
+	Password  *string
+	Role      *string
+}
+
+// UserFilter selects a page of users. Nil filters match every user. The page
+// starts after the user identified by CursorCreatedAt and CursorID when set.
+type UserFilter struct {
+	Role          *string
+	EmailVerified *bool
+	Deleted       *bool
+	Search        string
+
+	CursorCreatedAt *time.Time
+	CursorID        uuid.UUID
+	Limit           int
+}
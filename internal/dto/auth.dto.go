@@ -1,10 +1,42 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
 type AuthSignUp struct {
-	Email  string `json:"email" validate:"required,email"`
-	Secure bool   `json:"secure"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+	Secure   bool   `json:"secure"`
 }
 type AuthSignIn struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
 }
+type AuthRefresh struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// AuthTokens is returned by sign-in and refresh.
+type AuthTokens struct {
+	SessionID             uuid.UUID `json:"session_id"`
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+type AuthLogout struct {
+	RefreshToken string `json:"refresh_token" validate:"omitempty"`
+}
+
+type AuthForgotPassword struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type AuthResetPassword struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
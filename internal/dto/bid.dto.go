@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/pkg/money"
+)
+
+// CreateBid is an offer to do a task for the given amount, in the currency of the task budget.
+type CreateBid struct {
+	Amount  money.Money `json:"amount"`
+	Message string      `json:"message" validate:"omitempty,max=2000"`
+}
+
+// BidResponse is the representation of a bid returned by the API.
+type BidResponse struct {
+	ID        uuid.UUID   `json:"id"`
+	TaskID    uuid.UUID   `json:"task_id"`
+	BidderID  uuid.UUID   `json:"bidder_id"`
+	Amount    money.Money `json:"amount"`
+	Message   string      `json:"message"`
+	Status    string      `json:"status"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+func NewBidResponse(b *domain.Bid) BidResponse {
+	return BidResponse{
+		ID:        b.ID,
+		TaskID:    b.TaskID,
+		BidderID:  b.BidderID,
+		Amount:    b.Amount,
+		Message:   b.Message,
+		Status:    b.Status,
+		CreatedAt: b.CreatedAt,
+		UpdatedAt: b.UpdatedAt,
+	}
+}
+
+// AcceptBidResponse holds the accepted bid and the task it was assigned by.
+type AcceptBidResponse struct {
+	Bid  BidResponse  `json:"bid"`
+	Task TaskResponse `json:"task"`
+}
@@ -0,0 +1,92 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vgrigalashvili/veemon/internal/domain"
+)
+
+// CreateComment is a comment on a task, or a reply when ParentID is set.
+type CreateComment struct {
+	ParentID uuid.UUID `json:"parent_id" validate:"omitempty"`
+	Body     string    `json:"body" validate:"required,max=5000"`
+}
+
+// ListComments holds the query parameters of the comment listing.
+type ListComments struct {
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// CommentResponse is the representation of a comment returned by the API.
+type CommentResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	TaskID    uuid.UUID  `json:"task_id"`
+	AuthorID  uuid.UUID  `json:"author_id"`
+	ParentID  *uuid.UUID `json:"parent_id,omitempty"`
+	Body      string     `json:"body"`
+	Deleted   bool       `json:"deleted"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// CommentListResponse is a page of comments. NextCursor is empty on the last page.
+type CommentListResponse struct {
+	Comments   []CommentResponse `json:"comments"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+func NewCommentResponse(c *domain.Comment) CommentResponse {
+	response := CommentResponse{
+		ID:        c.ID,
+		TaskID:    c.TaskID,
+		AuthorID:  c.AuthorID,
+		Body:      c.Body,
+		Deleted:   !c.DeletedAt.IsZero(),
+		CreatedAt: c.CreatedAt,
+	}
+	if c.ParentID != uuid.Nil {
+		response.ParentID = &c.ParentID
+	}
+	return response
+}
+
+// ListActivity holds the query parameters of the task activity feed.
+type ListActivity struct {
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// ActivityResponse is an entry of the activity feed of a task. Details
+// depend on the kind: status changes hold from, to and reason, bids the
+// amount, attachments the file name and size, and comments the body.
+type ActivityResponse struct {
+	ID        uuid.UUID      `json:"id"`
+	TaskID    uuid.UUID      `json:"task_id"`
+	ActorID   uuid.UUID      `json:"actor_id"`
+	Kind      string         `json:"kind"`
+	SubjectID *uuid.UUID     `json:"subject_id,omitempty"`
+	Details   map[string]any `json:"details"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// ActivityListResponse is a page of the activity feed. NextCursor is empty on the last page.
+type ActivityListResponse struct {
+	Activity   []ActivityResponse `json:"activity"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+func NewActivityResponse(a *domain.Activity) ActivityResponse {
+	response := ActivityResponse{
+		ID:        a.ID,
+		TaskID:    a.TaskID,
+		ActorID:   a.ActorID,
+		Kind:      a.Kind,
+		Details:   a.Details,
+		CreatedAt: a.CreatedAt,
+	}
+	if a.SubjectID != uuid.Nil {
+		response.SubjectID = &a.SubjectID
+	}
+	return response
+}
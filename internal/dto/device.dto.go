@@ -0,0 +1,162 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+)
+
+// CreateDevice registers a lift. ID is the one the lift publishes under, and
+// the install date is written as YYYY-MM-DD.
+type CreateDevice struct {
+	ID          string `json:"id" validate:"required,max=64"`
+	Building    string `json:"building" validate:"omitempty,max=200"`
+	Address     string `json:"address" validate:"omitempty,max=500"`
+	Model       string `json:"model" validate:"omitempty,max=200"`
+	InstallDate string `json:"install_date" validate:"omitempty,datetime=2006-01-02"`
+	OwnerOrg    string `json:"owner_org" validate:"omitempty,max=200"`
+}
+
+// UpdateDevice holds the fields of a partial device update; omitted fields are left unchanged.
+type UpdateDevice struct {
+	Building    *string `json:"building" validate:"omitempty,max=200"`
+	Address     *string `json:"address" validate:"omitempty,max=500"`
+	Model       *string `json:"model" validate:"omitempty,max=200"`
+	InstallDate *string `json:"install_date" validate:"omitempty,datetime=2006-01-02"`
+	OwnerOrg    *string `json:"owner_org" validate:"omitempty,max=200"`
+}
+
+// ListDevices holds the query parameters of the device listing.
+type ListDevices struct {
+	Building string `query:"building"`
+	OwnerOrg string `query:"owner_org"`
+	Cursor   string `query:"cursor"`
+	Limit    int    `query:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+// ListDeviceMetrics holds the query parameters of the device metrics endpoint.
+// From and to are RFC 3339 timestamps, step a Go duration such as 5m or 1h.
+type ListDeviceMetrics struct {
+	Name string `query:"name" validate:"omitempty,max=64"`
+	From string `query:"from"`
+	To   string `query:"to"`
+	Step string `query:"step"`
+}
+
+// DeviceResponse is the representation of a device returned by the API.
+type DeviceResponse struct {
+	ID          string    `json:"id"`
+	Building    string    `json:"building"`
+	Address     string    `json:"address"`
+	Model       string    `json:"model"`
+	InstallDate string    `json:"install_date,omitempty"`
+	OwnerOrg    string    `json:"owner_org"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// DeviceListResponse is a page of devices. NextCursor is empty on the last page.
+type DeviceListResponse struct {
+	Devices    []DeviceResponse `json:"devices"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+func NewDeviceResponse(d *domain.Device) DeviceResponse {
+	response := DeviceResponse{
+		ID:        d.ID,
+		Building:  d.Building,
+		Address:   d.Address,
+		Model:     d.Model,
+		OwnerOrg:  d.OwnerOrg,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+	}
+	if !d.InstallDate.IsZero() {
+		response.InstallDate = d.InstallDate.Format(time.DateOnly)
+	}
+	return response
+}
+
+// DeviceStatusResponse is the live state of a device: unknown until its first
+// heartbeat, then online or offline.
+type DeviceStatusResponse struct {
+	DeviceID       string             `json:"device_id"`
+	State          string             `json:"state"`
+	StateChangedAt *time.Time         `json:"state_changed_at,omitempty"`
+	LastSeenAt     *time.Time         `json:"last_seen_at,omitempty"`
+	ReportedAt     *time.Time         `json:"reported_at,omitempty"` // Timestamp of the last heartbeat by the lift's clock.
+	Firmware       string             `json:"firmware,omitempty"`
+	Metrics        map[string]float64 `json:"metrics,omitempty"`
+}
+
+func NewDeviceStatusResponse(s *domain.DeviceStatus) DeviceStatusResponse {
+	response := DeviceStatusResponse{
+		DeviceID: s.DeviceID,
+		State:    s.State,
+		Firmware: s.Firmware,
+		Metrics:  s.Metrics,
+	}
+	if !s.StateChangedAt.IsZero() {
+		response.StateChangedAt = &s.StateChangedAt
+	}
+	if !s.LastSeenAt.IsZero() {
+		response.LastSeenAt = &s.LastSeenAt
+	}
+	if !s.ReportedAt.IsZero() {
+		response.ReportedAt = &s.ReportedAt
+	}
+	return response
+}
+
+// MetricBucketResponse summarizes the samples of a metric within one step.
+type MetricBucketResponse struct {
+	Start   time.Time `json:"start"`
+	Avg     float64   `json:"avg"`
+	Min     float64   `json:"min"`
+	Max     float64   `json:"max"`
+	Samples int64     `json:"samples"`
+}
+
+// MetricSeriesResponse holds the buckets of one metric, oldest first. Steps
+// without samples have no bucket.
+type MetricSeriesResponse struct {
+	Name    string                 `json:"name"`
+	Buckets []MetricBucketResponse `json:"buckets"`
+}
+
+// DeviceMetricsResponse is the downsampled metrics of a device over the
+// queried time range.
+type DeviceMetricsResponse struct {
+	DeviceID string                 `json:"device_id"`
+	From     time.Time              `json:"from"`
+	To       time.Time              `json:"to"`
+	Step     string                 `json:"step"`
+	Series   []MetricSeriesResponse `json:"series"`
+}
+
+func NewDeviceMetricsResponse(deviceID string, query domain.MetricQuery, series []domain.MetricSeries) DeviceMetricsResponse {
+	response := DeviceMetricsResponse{
+		DeviceID: deviceID,
+		From:     query.From,
+		To:       query.To,
+		Step:     query.Step.String(),
+		Series:   make([]MetricSeriesResponse, 0, len(series)),
+	}
+	for _, s := range series {
+		seriesResponse := MetricSeriesResponse{
+			Name:    s.Name,
+			Buckets: make([]MetricBucketResponse, 0, len(s.Buckets)),
+		}
+		for _, b := range s.Buckets {
+			seriesResponse.Buckets = append(seriesResponse.Buckets, MetricBucketResponse{
+				Start:   b.Start,
+				Avg:     b.Avg,
+				Min:     b.Min,
+				Max:     b.Max,
+				Samples: b.Samples,
+			})
+		}
+		response.Series = append(response.Series, seriesResponse)
+	}
+	return response
+}
@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vgrigalashvili/veemon/internal/domain"
+)
+
+// SendDeviceCommand is a command to send to a lift. Maintenance mode takes a
+// boolean enabled argument, the other commands none. Without a timeout the
+// server default applies.
+type SendDeviceCommand struct {
+	Name           string         `json:"name" validate:"required,oneof=reboot diagnostics maintenance_mode"`
+	Args           map[string]any `json:"args"`
+	TimeoutSeconds int            `json:"timeout_seconds" validate:"omitempty,min=1,max=3600"`
+}
+
+// ListDeviceCommands holds the query parameters of the device command listing.
+type ListDeviceCommands struct {
+	Status string `query:"status" validate:"omitempty,oneof=pending acked completed failed timed_out"`
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+// DeviceCommandResponse is a command sent to a lift and its progress. The ID
+// is the correlation ID the lift replies with.
+type DeviceCommandResponse struct {
+	ID          uuid.UUID      `json:"id"`
+	DeviceID    string         `json:"device_id"`
+	Name        string         `json:"name"`
+	Args        map[string]any `json:"args"`
+	Status      string         `json:"status"`
+	IssuedBy    uuid.UUID      `json:"issued_by"`
+	ExpiresAt   time.Time      `json:"expires_at"`
+	AckedAt     *time.Time     `json:"acked_at,omitempty"`
+	CompletedAt *time.Time     `json:"completed_at,omitempty"`
+	Result      map[string]any `json:"result,omitempty"`
+	Error       string         `json:"error,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// DeviceCommandListResponse is a page of device commands. NextCursor is empty on the last page.
+type DeviceCommandListResponse struct {
+	Commands   []DeviceCommandResponse `json:"commands"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+}
+
+func NewDeviceCommandResponse(c *domain.DeviceCommand) DeviceCommandResponse {
+	response := DeviceCommandResponse{
+		ID:        c.ID,
+		DeviceID:  c.DeviceID,
+		Name:      c.Name,
+		Args:      c.Args,
+		Status:    c.Status,
+		IssuedBy:  c.IssuedBy,
+		ExpiresAt: c.ExpiresAt,
+		Result:    c.Result,
+		Error:     c.Error,
+		CreatedAt: c.CreatedAt,
+	}
+	if !c.AckedAt.IsZero() {
+		response.AckedAt = &c.AckedAt
+	}
+	if !c.CompletedAt.IsZero() {
+		response.CompletedAt = &c.CompletedAt
+	}
+	return response
+}
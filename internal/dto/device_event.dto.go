@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vgrigalashvili/veemon/internal/domain"
+)
+
+// ListDeviceEvents holds the query parameters of the device event listing.
+type ListDeviceEvents struct {
+	Kind   string `query:"kind" validate:"omitempty,oneof=door fault trip alarm"`
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+// DeviceEventResponse is an event a lift reported. Details depend on the kind:
+// door events hold the floor and action, faults the code, severity and
+// message, trips the floors and duration, and alarms the type and whether it
+// is active.
+type DeviceEventResponse struct {
+	ID         uuid.UUID      `json:"id"`
+	DeviceID   string         `json:"device_id"`
+	Kind       string         `json:"kind"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	Details    map[string]any `json:"details"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// DeviceEventListResponse is a page of device events. NextCursor is empty on the last page.
+type DeviceEventListResponse struct {
+	Events     []DeviceEventResponse `json:"events"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
+
+func NewDeviceEventResponse(e *domain.DeviceEvent) DeviceEventResponse {
+	return DeviceEventResponse{
+		ID:         e.ID,
+		DeviceID:   e.DeviceID,
+		Kind:       e.Kind,
+		OccurredAt: e.OccurredAt,
+		Details:    e.Details,
+		CreatedAt:  e.CreatedAt,
+	}
+}
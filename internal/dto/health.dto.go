@@ -0,0 +1,9 @@
+package dto
+
+// ReadinessResponse reports the state of the dependencies of the server.
+// Database is up or down; MQTT is the state of the broker connection:
+// connecting, connected, reconnecting or disconnected.
+type ReadinessResponse struct {
+	Database string `json:"database"`
+	MQTT     string `json:"mqtt"`
+}
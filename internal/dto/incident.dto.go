@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vgrigalashvili/veemon/internal/domain"
+)
+
+// ListIncidents holds the query parameters of the incident listing.
+type ListIncidents struct {
+	Status   string `query:"status" validate:"omitempty,oneof=open acknowledged resolved"`
+	DeviceID string `query:"device_id" validate:"omitempty,max=64"`
+	Severity string `query:"severity" validate:"omitempty,oneof=warning critical"`
+	Cursor   string `query:"cursor"`
+	Limit    int    `query:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+// IncidentResponse is a problem of a lift. Occurrences counts how often the
+// problem happened while the incident was unresolved. Acknowledgement and
+// resolution fields are left out until they happen.
+type IncidentResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	DeviceID        string     `json:"device_id"`
+	Kind            string     `json:"kind"`
+	Code            string     `json:"code"`
+	Severity        string     `json:"severity"`
+	Status          string     `json:"status"`
+	Summary         string     `json:"summary"`
+	Occurrences     int        `json:"occurrences"`
+	OpenedAt        time.Time  `json:"opened_at"`
+	LastSeenAt      time.Time  `json:"last_seen_at"`
+	EscalationLevel int        `json:"escalation_level"`
+	EscalatedAt     *time.Time `json:"escalated_at,omitempty"`
+	AcknowledgedBy  *uuid.UUID `json:"acknowledged_by,omitempty"`
+	AcknowledgedAt  *time.Time `json:"acknowledged_at,omitempty"`
+	ResolvedBy      *uuid.UUID `json:"resolved_by,omitempty"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// IncidentListResponse is a page of incidents. NextCursor is empty on the last page.
+type IncidentListResponse struct {
+	Incidents  []IncidentResponse `json:"incidents"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+// SetDeviceContact sets the escalation level a contact of a lift is notified
+// from: 0 for every incident, higher levels once an incident escalated that far.
+type SetDeviceContact struct {
+	EscalationLevel int `json:"escalation_level" validate:"min=0"`
+}
+
+type DeviceContactResponse struct {
+	DeviceID        string    `json:"device_id"`
+	UserID          uuid.UUID `json:"user_id"`
+	EscalationLevel int       `json:"escalation_level"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func NewIncidentResponse(i *domain.Incident) IncidentResponse {
+	response := IncidentResponse{
+		ID:              i.ID,
+		DeviceID:        i.DeviceID,
+		Kind:            i.Kind,
+		Code:            i.Code,
+		Severity:        i.Severity,
+		Status:          i.Status,
+		Summary:         i.Summary,
+		Occurrences:     i.Occurrences,
+		OpenedAt:        i.OpenedAt,
+		LastSeenAt:      i.LastSeenAt,
+		EscalationLevel: i.EscalationLevel,
+		CreatedAt:       i.CreatedAt,
+		UpdatedAt:       i.UpdatedAt,
+	}
+	if !i.EscalatedAt.IsZero() {
+		response.EscalatedAt = &i.EscalatedAt
+	}
+	if i.AcknowledgedBy != uuid.Nil {
+		response.AcknowledgedBy = &i.AcknowledgedBy
+		response.AcknowledgedAt = &i.AcknowledgedAt
+	}
+	if i.ResolvedBy != uuid.Nil {
+		response.ResolvedBy = &i.ResolvedBy
+		response.ResolvedAt = &i.ResolvedAt
+	}
+	return response
+}
+
+func NewDeviceContactResponse(c *domain.DeviceContact) DeviceContactResponse {
+	return DeviceContactResponse{
+		DeviceID:        c.DeviceID,
+		UserID:          c.UserID,
+		EscalationLevel: c.EscalationLevel,
+		CreatedAt:       c.CreatedAt,
+	}
+}
@@ -1,14 +1,157 @@
 package dto
 
-import "github.com/vgrigalashvili/veemon/internal/domain"
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/pkg/money"
+)
 
 type CreateTask struct {
-	Private     bool                `json:"private" validate:"omitempty"`
-	Title       string              `json:"title" validate:"required,min=3"`
-	Description string              `json:"description" validate:"omitempty"`
-	Category    string              `json:"category" validate:"omitempty"`
-	Location    string              `json:"location" validate:"omitempty"`
-	Deadline    string              `json:"deadline" validate:"omitempty"`
-	Budget      int                 `json:"budget" validate:"required"`
-	Attachments []domain.Attachment `json:"attachments" validate:"omitempty"`
+	Private     bool        `json:"private" validate:"omitempty"`
+	Title       string      `json:"title" validate:"required,min=3"`
+	Description string      `json:"description" validate:"omitempty"`
+	Category    string      `json:"category" validate:"omitempty"`
+	Location    string      `json:"location" validate:"omitempty"`
+	Address     string      `json:"address" validate:"omitempty"`
+	Deadline    *time.Time  `json:"deadline" validate:"omitempty"` // RFC 3339 with a time zone, e.g. 2025-05-01T18:00:00+04:00
+	Budget      money.Money `json:"budget"`
+	Latitude    *float64    `json:"latitude" validate:"required_with=Longitude,omitempty,min=-90,max=90"`
+	Longitude   *float64    `json:"longitude" validate:"required_with=Latitude,omitempty,min=-180,max=180"`
+}
+
+// UpdateTask holds the fields of a partial task update; omitted fields are left unchanged.
+type UpdateTask struct {
+	Private     *bool        `json:"private" validate:"omitempty"`
+	Title       *string      `json:"title" validate:"omitempty,min=3"`
+	Description *string      `json:"description" validate:"omitempty"`
+	Category    *string      `json:"category" validate:"omitempty"`
+	Location    *string      `json:"location" validate:"omitempty"`
+	Address     *string      `json:"address" validate:"omitempty"`
+	Deadline    *time.Time   `json:"deadline" validate:"omitempty"`
+	Budget      *money.Money `json:"budget" validate:"omitempty"`
+	Latitude    *float64     `json:"latitude" validate:"required_with=Longitude,omitempty,min=-90,max=90"`
+	Longitude   *float64     `json:"longitude" validate:"required_with=Latitude,omitempty,min=-180,max=180"`
+}
+
+// TransitionTask moves a task to another status of its lifecycle.
+type TransitionTask struct {
+	Status     string    `json:"status" validate:"required,oneof=draft open assigned in_progress completed cancelled disputed"`
+	AssigneeID uuid.UUID `json:"assignee_id" validate:"omitempty"`
+	Reason     string    `json:"reason" validate:"omitempty,max=500"`
+}
+
+// ListTasks holds the query parameters of the task listing.
+type ListTasks struct {
+	Owner  string `query:"owner"`
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// SearchTasks holds the query parameters of the task search. Deadlines are
+// RFC 3339 timestamps, budget bounds are in minor units of the currency, and
+// lat, lng and radius_km must be given together.
+type SearchTasks struct {
+	Query        string   `query:"q" validate:"omitempty,max=200"`
+	Category     string   `query:"category"`
+	Currency     string   `query:"currency" validate:"required_with=BudgetMin BudgetMax"`
+	BudgetMin    *int64   `query:"budget_min" validate:"omitempty,min=0"`
+	BudgetMax    *int64   `query:"budget_max" validate:"omitempty,min=0"`
+	DeadlineFrom string   `query:"deadline_from"`
+	DeadlineTo   string   `query:"deadline_to"`
+	Latitude     *float64 `query:"lat" validate:"required_with=Longitude RadiusKm,omitempty,min=-90,max=90"`
+	Longitude    *float64 `query:"lng" validate:"required_with=Latitude RadiusKm,omitempty,min=-180,max=180"`
+	RadiusKm     *float64 `query:"radius_km" validate:"required_with=Latitude Longitude,omitempty,gt=0,max=500"`
+	Cursor       string   `query:"cursor"`
+	Limit        int      `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// TaskResponse is the representation of a task returned by the API.
+type TaskResponse struct {
+	ID          uuid.UUID   `json:"id"`
+	OwnerID     uuid.UUID   `json:"owner_id"`
+	AssigneeID  *uuid.UUID  `json:"assignee_id,omitempty"`
+	Private     bool        `json:"private"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Category    string      `json:"category"`
+	Location    string      `json:"location"`
+	Address     string      `json:"address"`
+	Latitude    *float64    `json:"latitude,omitempty"`
+	Longitude   *float64    `json:"longitude,omitempty"`
+	Deadline    *time.Time  `json:"deadline,omitempty"`
+	Budget      money.Money `json:"budget"`
+	Status      string      `json:"status"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// TaskListResponse is a page of tasks. NextCursor is empty on the last page.
+type TaskListResponse struct {
+	Tasks      []TaskResponse `json:"tasks"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+func NewTaskResponse(t *domain.Task) TaskResponse {
+	response := TaskResponse{
+		ID:          t.ID,
+		OwnerID:     t.OwnerID,
+		Private:     !t.Public,
+		Title:       t.Title,
+		Description: t.Description,
+		Category:    t.Category,
+		Location:    t.Location,
+		Address:     t.Address,
+		Latitude:    t.Latitude,
+		Longitude:   t.Longitude,
+		Budget:      t.Budget,
+		Status:      t.Status,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+	if !t.Deadline.IsZero() {
+		response.Deadline = &t.Deadline
+	}
+	if t.AssigneeID != uuid.Nil {
+		response.AssigneeID = &t.AssigneeID
+	}
+	return response
+}
+
+// AttachmentResponse is the representation of a task attachment returned by the API.
+type AttachmentResponse struct {
+	ID          uuid.UUID `json:"id"`
+	TaskID      uuid.UUID `json:"task_id"`
+	UploaderID  uuid.UUID `json:"uploader_id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"sha256"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func NewAttachmentResponse(a *domain.Attachment) AttachmentResponse {
+	return AttachmentResponse{
+		ID:          a.ID,
+		TaskID:      a.TaskID,
+		UploaderID:  a.UploaderID,
+		Name:        a.Name,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Checksum:    a.Checksum,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+// DownloadAttachment holds the query parameters of a signed download link.
+type DownloadAttachment struct {
+	Expires   int64  `query:"expires" validate:"required"`
+	Signature string `query:"signature" validate:"required,hexadecimal"`
+}
+
+// AttachmentLinkResponse is a signed link to download an attachment without an access token.
+type AttachmentLinkResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
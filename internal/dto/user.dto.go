@@ -1,21 +1,74 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vgrigalashvili/veemon/internal/domain"
+)
+
 type CreateUser struct {
 	FirstName string `json:"first_name" validate:"required,min=2"`
 	LastName  string `json:"last_name" validate:"required,min=3"`
 	Mobile    string `json:"mobile" validate:"required,min=9"`
 	Email     string `json:"email" validate:"required,email"`
-	Password  string `json:"password" validate:"omitempty,min=6"`
-	Role      string `json:"role" validate:"required"`
+	Password  string `json:"password" validate:"omitempty"`
+	Role      string `json:"role" validate:"omitempty,oneof=admin operator user"`
 }
 type UpdateUser struct {
 	FirstName *string `json:"first_name" validate:"omitempty"`
 	LastName  *string `json:"last_name" validate:"omitempty"`
 	Type      *string `json:"type" validate:"omitempty"`
-	Role      *string `json:"role" validate:"omitempty"`
+	Role      *string `json:"role" validate:"omitempty,oneof=admin operator user"`
 	Mobile    *string `json:"mobile" validate:"omitempty,min=9"`
 	Email     *string `json:"email" validate:"omitempty,email"`
 	Password  *string `json:"password" validate:"omitempty,min=6"`
 
+	// CurrentPassword confirms changes of the own email or password.
+	CurrentPassword string `json:"current_password"`
+
 	// ExpiresAt *time.Time `json:"expires_at" validate:"omitempty"`
 }
+
+// ListUsers holds the query parameters of the user listing.
+type ListUsers struct {
+	Role          string `query:"role" validate:"omitempty,oneof=admin operator user"`
+	EmailVerified *bool  `query:"email_verified"`
+	Deleted       string `query:"deleted" validate:"omitempty,oneof=true false any"`
+	Search        string `query:"search" validate:"omitempty,max=100"`
+	Cursor        string `query:"cursor"`
+	Limit         int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// UserListResponse is a page of users. NextCursor is empty on the last page.
+type UserListResponse struct {
+	Users      []UserResponse `json:"users"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// UserResponse is the representation of a user returned by the API.
+type UserResponse struct {
+	ID            uuid.UUID `json:"id"`
+	FirstName     string    `json:"first_name"`
+	LastName      string    `json:"last_name"`
+	Email         string    `json:"email"`
+	Mobile        string    `json:"mobile,omitempty"`
+	Role          string    `json:"role"`
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func NewUserResponse(u *domain.User) UserResponse {
+	return UserResponse{
+		ID:            u.ID,
+		FirstName:     u.FirstName,
+		LastName:      u.LastName,
+		Email:         u.Email,
+		Mobile:        u.Mobile,
+		Role:          u.Role,
+		EmailVerified: u.Email_verified,
+		CreatedAt:     u.CreatedAt,
+		UpdatedAt:     u.UpdatedAt,
+	}
+}
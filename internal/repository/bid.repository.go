@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
+	"github.com/vgrigalashvili/veemon/pkg/money"
+)
+
+var (
+	ErrBidNotFound      = errors.New("bid not found")
+	ErrBidAlreadyExists = errors.New("a pending bid on this task already exists")
+	ErrBidNotPending    = errors.New("bid is no longer pending")
+)
+
+type (
+	BidRepository interface {
+		Create(ctx context.Context, bid domain.Bid) (*domain.Bid, error)
+		Read(ctx context.Context, id uuid.UUID) (*domain.Bid, error)
+		ListByTask(ctx context.Context, taskID uuid.UUID, bidderID uuid.UUID) ([]*domain.Bid, error)
+		Withdraw(ctx context.Context, id uuid.UUID) (*domain.Bid, error)
+		Accept(ctx context.Context, id uuid.UUID, transition domain.TaskTransition) (*domain.Bid, *domain.Task, error)
+	}
+)
+
+type bidRepository struct {
+	store *db.Store
+}
+
+func NewBidRepository(store *db.Store) BidRepository {
+	if store == nil {
+		log.Fatalf("[FATAL] store cannot be nil")
+	}
+	return &bidRepository{store: store}
+}
+
+// Create places a bid and adds it to the task activity feed.
+func (br *bidRepository) Create(ctx context.Context, bid domain.Bid) (*domain.Bid, error) {
+	var createdBid *domain.Bid
+	err := br.store.ExecTx(ctx, func(q *db.Queries) error {
+		dbBid, err := q.CreateBid(ctx, db.CreateBidParams{
+			ID:       bid.ID,
+			TaskID:   bid.TaskID,
+			BidderID: bid.BidderID,
+			Amount:   bid.Amount.Amount,
+			Currency: bid.Amount.Currency,
+			Message:  bid.Message,
+		})
+		if err != nil {
+			return err
+		}
+		createdBid = dbToDomainBid(dbBid)
+
+		return recordActivity(ctx, q, domain.Activity{
+			TaskID:    createdBid.TaskID,
+			ActorID:   createdBid.BidderID,
+			Kind:      domain.ActivityBidPlaced,
+			SubjectID: createdBid.ID,
+			Details:   map[string]any{"amount": createdBid.Amount},
+		})
+	})
+	if err != nil {
+		if isUniqueViolation(err, "bids_task_id_bidder_id_pending_key") {
+			return nil, ErrBidAlreadyExists
+		}
+		return nil, err
+	}
+	return createdBid, nil
+}
+
+func (br *bidRepository) Read(ctx context.Context, id uuid.UUID) (*domain.Bid, error) {
+	dbBid, err := br.store.GetBid(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrBidNotFound
+		}
+		return nil, err
+	}
+	return dbToDomainBid(dbBid), nil
+}
+
+// ListByTask returns the bids on a task, oldest first. When bidderID is set,
+// only the bids of that bidder are returned.
+func (br *bidRepository) ListByTask(ctx context.Context, taskID uuid.UUID, bidderID uuid.UUID) ([]*domain.Bid, error) {
+	params := db.ListTaskBidsParams{TaskID: taskID}
+	if bidderID != uuid.Nil {
+		params.BidderID = pgtype.UUID{Bytes: bidderID, Valid: true}
+	}
+
+	dbBids, err := br.store.ListTaskBids(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	bids := make([]*domain.Bid, 0, len(dbBids))
+	for _, b := range dbBids {
+		bids = append(bids, dbToDomainBid(b))
+	}
+	return bids, nil
+}
+
+// Withdraw marks a pending bid as withdrawn and records it in the task activity
+// feed. It fails with ErrBidNotPending if the bid was accepted, rejected or
+// withdrawn in the meantime.
+func (br *bidRepository) Withdraw(ctx context.Context, id uuid.UUID) (*domain.Bid, error) {
+	var bid *domain.Bid
+	err := br.store.ExecTx(ctx, func(q *db.Queries) error {
+		dbBid, err := q.SetBidStatus(ctx, db.SetBidStatusParams{
+			ID:         id,
+			FromStatus: domain.BidStatusPending,
+			ToStatus:   domain.BidStatusWithdrawn,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrBidNotPending
+			}
+			return err
+		}
+		bid = dbToDomainBid(dbBid)
+
+		return recordActivity(ctx, q, domain.Activity{
+			TaskID:    bid.TaskID,
+			ActorID:   bid.BidderID,
+			Kind:      domain.ActivityBidWithdrawn,
+			SubjectID: bid.ID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bid, nil
+}
+
+// Accept accepts a pending bid, rejects the other pending bids on its task and
+// applies the task transition within one transaction, recording the status
+// change and the acceptance in the task activity feed. Changing the task
+// status first makes concurrent accepts of the same task fail with
+// ErrTaskStatusConflict.
+func (br *bidRepository) Accept(ctx context.Context, id uuid.UUID, transition domain.TaskTransition) (*domain.Bid, *domain.Task, error) {
+	var (
+		bid  *domain.Bid
+		task *domain.Task
+	)
+	err := br.store.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		task, err = transitionTask(ctx, q, transition)
+		if err != nil {
+			return err
+		}
+
+		dbBid, err := q.SetBidStatus(ctx, db.SetBidStatusParams{
+			ID:         id,
+			FromStatus: domain.BidStatusPending,
+			ToStatus:   domain.BidStatusAccepted,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrBidNotPending
+			}
+			return err
+		}
+		bid = dbToDomainBid(dbBid)
+
+		_, err = q.RejectPendingBids(ctx, db.RejectPendingBidsParams{
+			TaskID:     bid.TaskID,
+			AcceptedID: bid.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordActivity(ctx, q, domain.Activity{
+			TaskID:    bid.TaskID,
+			ActorID:   transition.ActorID,
+			Kind:      domain.ActivityBidAccepted,
+			SubjectID: bid.ID,
+			Details:   map[string]any{"bidder_id": bid.BidderID},
+		})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return bid, task, nil
+}
+
+func dbToDomainBid(b db.Bid) *domain.Bid {
+	return &domain.Bid{
+		ID:        b.ID,
+		CreatedAt: b.CreatedAt,
+		UpdatedAt: b.UpdatedAt.Time,
+
+		TaskID:   b.TaskID,
+		BidderID: b.BidderID,
+		Amount: money.Money{
+			Amount:   b.Amount,
+			Currency: b.Currency,
+		},
+		Message: b.Message,
+		Status:  b.Status,
+	}
+}
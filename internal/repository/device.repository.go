@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
+)
+
+var (
+	ErrDeviceNotFound      = errors.New("device not found")
+	ErrDeviceAlreadyExists = errors.New("device with this ID is already registered")
+	ErrDeviceNeverSeen     = errors.New("no heartbeat received from device")
+)
+
+type (
+	DeviceRepository interface {
+		Create(ctx context.Context, device domain.Device) (*domain.Device, error)
+		Read(ctx context.Context, id string) (*domain.Device, error)
+		Update(ctx context.Context, id string, update domain.DeviceUpdate) (*domain.Device, error)
+		Delete(ctx context.Context, id string) error
+		List(ctx context.Context, filter domain.DeviceFilter) ([]*domain.Device, error)
+		RecordHeartbeat(ctx context.Context, id string, heartbeat domain.Heartbeat) (*domain.DeviceStatus, string, error)
+		Status(ctx context.Context, id string) (*domain.DeviceStatus, error)
+		MarkOffline(ctx context.Context, seenBefore time.Time) ([]string, error)
+	}
+)
+
+type deviceRepository struct {
+	store *db.Store
+}
+
+func NewDeviceRepository(store *db.Store) DeviceRepository {
+	if store == nil {
+		log.Fatalf("[FATAL] store cannot be nil")
+	}
+	return &deviceRepository{store: store}
+}
+
+// Create registers a device. A deleted device is registered again under its
+// old ID, while registering a live device fails with ErrDeviceAlreadyExists.
+func (dr *deviceRepository) Create(ctx context.Context, device domain.Device) (*domain.Device, error) {
+	params := db.CreateDeviceParams{
+		ID:       device.ID,
+		Building: device.Building,
+		Address:  device.Address,
+		Model:    device.Model,
+		OwnerOrg: device.OwnerOrg,
+	}
+	if !device.InstallDate.IsZero() {
+		params.InstallDate = pgtype.Date{Time: device.InstallDate, Valid: true}
+	}
+
+	dbDevice, err := dr.store.CreateDevice(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDeviceAlreadyExists
+		}
+		return nil, err
+	}
+	return dbToDomainDevice(dbDevice), nil
+}
+
+func (dr *deviceRepository) Read(ctx context.Context, id string) (*domain.Device, error) {
+	dbDevice, err := dr.store.GetDevice(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDeviceNotFound
+		}
+		return nil, err
+	}
+	return dbToDomainDevice(dbDevice), nil
+}
+
+func (dr *deviceRepository) Update(ctx context.Context, id string, update domain.DeviceUpdate) (*domain.Device, error) {
+	params := db.UpdateDeviceParams{
+		ID:       id,
+		Building: update.Building,
+		Address:  update.Address,
+		Model:    update.Model,
+		OwnerOrg: update.OwnerOrg,
+	}
+	if update.InstallDate != nil {
+		params.InstallDate = pgtype.Date{Time: *update.InstallDate, Valid: true}
+	}
+
+	dbDevice, err := dr.store.UpdateDevice(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDeviceNotFound
+		}
+		return nil, err
+	}
+	return dbToDomainDevice(dbDevice), nil
+}
+
+func (dr *deviceRepository) Delete(ctx context.Context, id string) error {
+	rows, err := dr.store.SoftDeleteDevice(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrDeviceNotFound
+	}
+	return nil
+}
+
+// List returns a page of the registered devices ordered by ID.
+func (dr *deviceRepository) List(ctx context.Context, filter domain.DeviceFilter) ([]*domain.Device, error) {
+	params := db.ListDevicesParams{
+		Building: filter.Building,
+		OwnerOrg: filter.OwnerOrg,
+		PageSize: int32(filter.Limit),
+	}
+	if filter.CursorID != "" {
+		params.CursorID = &filter.CursorID
+	}
+
+	dbDevices, err := dr.store.ListDevices(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]*domain.Device, 0, len(dbDevices))
+	for _, d := range dbDevices {
+		devices = append(devices, dbToDomainDevice(d))
+	}
+	return devices, nil
+}
+
+// RecordHeartbeat stores a heartbeat of a registered device and marks it
+// online. It returns the new status and the state before the heartbeat, which
+// is DeviceStateUnknown for the first heartbeat of a device. Heartbeats of
+// unregistered devices fail with ErrDeviceNotFound.
+func (dr *deviceRepository) RecordHeartbeat(ctx context.Context, id string, heartbeat domain.Heartbeat) (*domain.DeviceStatus, string, error) {
+	metrics, err := json.Marshal(heartbeat.Metrics)
+	if err != nil {
+		return nil, "", err
+	}
+	if heartbeat.Metrics == nil {
+		metrics = []byte("{}")
+	}
+
+	params := db.RecordDeviceHeartbeatParams{
+		DeviceID: id,
+		Firmware: heartbeat.Firmware,
+		Metrics:  metrics,
+	}
+	if !heartbeat.Timestamp.IsZero() {
+		params.ReportedAt = pgtype.Timestamptz{Time: heartbeat.Timestamp, Valid: true}
+	}
+
+	row, err := dr.store.RecordDeviceHeartbeat(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, "", ErrDeviceNotFound
+		}
+		return nil, "", err
+	}
+
+	status, err := dbToDomainDeviceStatus(db.DeviceStatus{
+		DeviceID:       row.DeviceID,
+		State:          row.State,
+		StateChangedAt: row.StateChangedAt,
+		LastSeenAt:     row.LastSeenAt,
+		ReportedAt:     row.ReportedAt,
+		Firmware:       row.Firmware,
+		Metrics:        row.Metrics,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	previousState := row.PreviousState
+	if previousState == "" {
+		previousState = domain.DeviceStateUnknown
+	}
+	return status, previousState, nil
+}
+
+// Status returns the live state of a device. It fails with ErrDeviceNeverSeen
+// if no heartbeat of the device was ever received.
+func (dr *deviceRepository) Status(ctx context.Context, id string) (*domain.DeviceStatus, error) {
+	dbStatus, err := dr.store.GetDeviceStatus(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDeviceNeverSeen
+		}
+		return nil, err
+	}
+	return dbToDomainDeviceStatus(dbStatus)
+}
+
+// MarkOffline marks the online devices last seen before seenBefore as offline
+// and returns their IDs.
+func (dr *deviceRepository) MarkOffline(ctx context.Context, seenBefore time.Time) ([]string, error) {
+	return dr.store.MarkStaleDevicesOffline(ctx, seenBefore)
+}
+
+func dbToDomainDevice(d db.Device) *domain.Device {
+	return &domain.Device{
+		ID:        d.ID,
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt.Time,
+		DeletedAt: d.DeletedAt.Time,
+
+		Building:    d.Building,
+		Address:     d.Address,
+		Model:       d.Model,
+		InstallDate: d.InstallDate.Time,
+		OwnerOrg:    d.OwnerOrg,
+	}
+}
+
+func dbToDomainDeviceStatus(s db.DeviceStatus) (*domain.DeviceStatus, error) {
+	status := &domain.DeviceStatus{
+		DeviceID:       s.DeviceID,
+		State:          s.State,
+		StateChangedAt: s.StateChangedAt,
+		LastSeenAt:     s.LastSeenAt,
+		ReportedAt:     s.ReportedAt.Time,
+		Firmware:       s.Firmware,
+	}
+	if err := json.Unmarshal(s.Metrics, &status.Metrics); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
+)
+
+var (
+	ErrCommandNotFound    = errors.New("device command not found")
+	ErrCommandNotAwaiting = errors.New("device command is unknown, finished or expired")
+)
+
+type (
+	DeviceCommandRepository interface {
+		Create(ctx context.Context, command domain.DeviceCommand) (*domain.DeviceCommand, error)
+		Read(ctx context.Context, deviceID string, id uuid.UUID) (*domain.DeviceCommand, error)
+		ListByDevice(ctx context.Context, deviceID string, filter domain.DeviceCommandFilter) ([]*domain.DeviceCommand, error)
+		Ack(ctx context.Context, deviceID, name string, id uuid.UUID) (*domain.DeviceCommand, error)
+		Finish(ctx context.Context, deviceID, name string, id uuid.UUID, status string, result map[string]any, reason string) (*domain.DeviceCommand, error)
+		TimeOut(ctx context.Context) ([]*domain.DeviceCommand, error)
+	}
+)
+
+type deviceCommandRepository struct {
+	store *db.Store
+}
+
+func NewDeviceCommandRepository(store *db.Store) DeviceCommandRepository {
+	if store == nil {
+		log.Fatalf("[FATAL] store cannot be nil")
+	}
+	return &deviceCommandRepository{store: store}
+}
+
+// Create stores a pending command of a registered device. Commands of
+// unregistered devices fail with ErrDeviceNotFound.
+func (cr *deviceCommandRepository) Create(ctx context.Context, command domain.DeviceCommand) (*domain.DeviceCommand, error) {
+	args, err := json.Marshal(command.Args)
+	if err != nil {
+		return nil, err
+	}
+	if command.Args == nil {
+		args = []byte("{}")
+	}
+
+	dbCommand, err := cr.store.CreateDeviceCommand(ctx, db.CreateDeviceCommandParams{
+		ID:        command.ID,
+		Name:      command.Name,
+		Args:      args,
+		IssuedBy:  command.IssuedBy,
+		ExpiresAt: command.ExpiresAt,
+		DeviceID:  command.DeviceID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDeviceNotFound
+		}
+		return nil, err
+	}
+	return dbToDomainDeviceCommand(dbCommand)
+}
+
+func (cr *deviceCommandRepository) Read(ctx context.Context, deviceID string, id uuid.UUID) (*domain.DeviceCommand, error) {
+	dbCommand, err := cr.store.GetDeviceCommand(ctx, db.GetDeviceCommandParams{
+		ID:       id,
+		DeviceID: deviceID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCommandNotFound
+		}
+		return nil, err
+	}
+	return dbToDomainDeviceCommand(dbCommand)
+}
+
+// ListByDevice returns a page of the commands of a device, newest first.
+func (cr *deviceCommandRepository) ListByDevice(ctx context.Context, deviceID string, filter domain.DeviceCommandFilter) ([]*domain.DeviceCommand, error) {
+	params := db.ListDeviceCommandsParams{
+		DeviceID: deviceID,
+		Status:   filter.Status,
+		PageSize: int32(filter.Limit),
+	}
+	if filter.CursorCreatedAt != nil {
+		params.CursorCreatedAt = pgtype.Timestamptz{Time: *filter.CursorCreatedAt, Valid: true}
+		params.CursorID = pgtype.UUID{Bytes: filter.CursorID, Valid: true}
+	}
+
+	dbCommands, err := cr.store.ListDeviceCommands(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return dbToDomainDeviceCommands(dbCommands)
+}
+
+// Ack marks a pending command as acknowledged by the lift. It fails with
+// ErrCommandNotAwaiting unless the command of the device and name is pending
+// and not expired.
+func (cr *deviceCommandRepository) Ack(ctx context.Context, deviceID, name string, id uuid.UUID) (*domain.DeviceCommand, error) {
+	dbCommand, err := cr.store.AckDeviceCommand(ctx, db.AckDeviceCommandParams{
+		ID:       id,
+		DeviceID: deviceID,
+		Name:     name,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCommandNotAwaiting
+		}
+		return nil, err
+	}
+	return dbToDomainDeviceCommand(dbCommand)
+}
+
+// Finish moves a pending or acknowledged command to its final status. It
+// fails with ErrCommandNotAwaiting unless the command of the device and name
+// is still running and not expired.
+func (cr *deviceCommandRepository) Finish(ctx context.Context, deviceID, name string, id uuid.UUID, status string, result map[string]any, reason string) (*domain.DeviceCommand, error) {
+	params := db.FinishDeviceCommandParams{
+		Status:   status,
+		Error:    reason,
+		ID:       id,
+		DeviceID: deviceID,
+		Name:     name,
+	}
+	if result != nil {
+		var err error
+		if params.Result, err = json.Marshal(result); err != nil {
+			return nil, err
+		}
+	}
+
+	dbCommand, err := cr.store.FinishDeviceCommand(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCommandNotAwaiting
+		}
+		return nil, err
+	}
+	return dbToDomainDeviceCommand(dbCommand)
+}
+
+// TimeOut marks the commands that got no final reply before they expired as
+// timed out and returns them.
+func (cr *deviceCommandRepository) TimeOut(ctx context.Context) ([]*domain.DeviceCommand, error) {
+	dbCommands, err := cr.store.TimeOutDeviceCommands(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dbToDomainDeviceCommands(dbCommands)
+}
+
+func dbToDomainDeviceCommands(dbCommands []db.DeviceCommand) ([]*domain.DeviceCommand, error) {
+	commands := make([]*domain.DeviceCommand, 0, len(dbCommands))
+	for _, c := range dbCommands {
+		command, err := dbToDomainDeviceCommand(c)
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, command)
+	}
+	return commands, nil
+}
+
+func dbToDomainDeviceCommand(c db.DeviceCommand) (*domain.DeviceCommand, error) {
+	command := &domain.DeviceCommand{
+		ID:        c.ID,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt.Time,
+
+		DeviceID:  c.DeviceID,
+		Name:      c.Name,
+		Status:    c.Status,
+		IssuedBy:  c.IssuedBy,
+		ExpiresAt: c.ExpiresAt,
+
+		AckedAt:     c.AckedAt.Time,
+		CompletedAt: c.CompletedAt.Time,
+		Error:       c.Error,
+	}
+	if err := json.Unmarshal(c.Args, &command.Args); err != nil {
+		return nil, err
+	}
+	if c.Result != nil {
+		if err := json.Unmarshal(c.Result, &command.Result); err != nil {
+			return nil, err
+		}
+	}
+	return command, nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
+)
+
+type (
+	DeviceEventRepository interface {
+		Create(ctx context.Context, event domain.DeviceEvent) (*domain.DeviceEvent, error)
+		ListByDevice(ctx context.Context, deviceID string, filter domain.DeviceEventFilter) ([]*domain.DeviceEvent, error)
+	}
+)
+
+type deviceEventRepository struct {
+	store *db.Store
+}
+
+func NewDeviceEventRepository(store *db.Store) DeviceEventRepository {
+	if store == nil {
+		log.Fatalf("[FATAL] store cannot be nil")
+	}
+	return &deviceEventRepository{store: store}
+}
+
+// Create stores an event of a registered device. Events of unregistered
+// devices fail with ErrDeviceNotFound.
+func (er *deviceEventRepository) Create(ctx context.Context, event domain.DeviceEvent) (*domain.DeviceEvent, error) {
+	details, err := json.Marshal(event.Details)
+	if err != nil {
+		return nil, err
+	}
+	if event.Details == nil {
+		details = []byte("{}")
+	}
+
+	dbEvent, err := er.store.CreateDeviceEvent(ctx, db.CreateDeviceEventParams{
+		ID:         event.ID,
+		Kind:       event.Kind,
+		OccurredAt: event.OccurredAt,
+		Details:    details,
+		DeviceID:   event.DeviceID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDeviceNotFound
+		}
+		return nil, err
+	}
+	return dbToDomainDeviceEvent(dbEvent)
+}
+
+// ListByDevice returns a page of the events of a device, newest first.
+func (er *deviceEventRepository) ListByDevice(ctx context.Context, deviceID string, filter domain.DeviceEventFilter) ([]*domain.DeviceEvent, error) {
+	params := db.ListDeviceEventsParams{
+		DeviceID: deviceID,
+		Kind:     filter.Kind,
+		PageSize: int32(filter.Limit),
+	}
+	if filter.CursorCreatedAt != nil {
+		params.CursorCreatedAt = pgtype.Timestamptz{Time: *filter.CursorCreatedAt, Valid: true}
+		params.CursorID = pgtype.UUID{Bytes: filter.CursorID, Valid: true}
+	}
+
+	dbEvents, err := er.store.ListDeviceEvents(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*domain.DeviceEvent, 0, len(dbEvents))
+	for _, e := range dbEvents {
+		event, err := dbToDomainDeviceEvent(e)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func dbToDomainDeviceEvent(e db.DeviceEvent) (*domain.DeviceEvent, error) {
+	event := &domain.DeviceEvent{
+		ID:         e.ID,
+		CreatedAt:  e.CreatedAt,
+		DeviceID:   e.DeviceID,
+		Kind:       e.Kind,
+		OccurredAt: e.OccurredAt,
+	}
+	if err := json.Unmarshal(e.Details, &event.Details); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
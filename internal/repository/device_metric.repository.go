@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
+)
+
+type (
+	MetricRepository interface {
+		Record(ctx context.Context, deviceID string, recordedAt time.Time, metrics map[string]float64) error
+		Series(ctx context.Context, deviceID string, query domain.MetricQuery) ([]domain.MetricSeries, error)
+	}
+)
+
+type metricRepository struct {
+	store *db.Store
+}
+
+func NewMetricRepository(store *db.Store) MetricRepository {
+	if store == nil {
+		log.Fatalf("[FATAL] store cannot be nil")
+	}
+	return &metricRepository{store: store}
+}
+
+// Record stores metric samples of a device taken at recordedAt. Samples of a
+// month without a partition are rejected by the database.
+func (mr *metricRepository) Record(ctx context.Context, deviceID string, recordedAt time.Time, metrics map[string]float64) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	params := db.CreateDeviceMetricsParams{
+		DeviceID:   deviceID,
+		RecordedAt: recordedAt,
+		Names:      make([]string, 0, len(metrics)),
+		Values:     make([]float64, 0, len(metrics)),
+	}
+	for name, value := range metrics {
+		params.Names = append(params.Names, name)
+		params.Values = append(params.Values, value)
+	}
+	return mr.store.CreateDeviceMetrics(ctx, params)
+}
+
+// Series returns the downsampled series of the metrics of a device, ordered by
+// name.
+func (mr *metricRepository) Series(ctx context.Context, deviceID string, query domain.MetricQuery) ([]domain.MetricSeries, error) {
+	rows, err := mr.store.ListDeviceMetricBuckets(ctx, db.ListDeviceMetricBucketsParams{
+		Step:     pgtype.Interval{Microseconds: query.Step.Microseconds(), Valid: true},
+		FromTime: query.From,
+		DeviceID: deviceID,
+		ToTime:   query.To,
+		Name:     query.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	series := []domain.MetricSeries{}
+	for _, row := range rows {
+		if len(series) == 0 || series[len(series)-1].Name != row.Name {
+			series = append(series, domain.MetricSeries{Name: row.Name})
+		}
+		current := &series[len(series)-1]
+		current.Buckets = append(current.Buckets, domain.MetricBucket{
+			Start:   row.Bucket,
+			Avg:     row.AvgValue,
+			Min:     row.MinValue,
+			Max:     row.MaxValue,
+			Samples: row.Samples,
+		})
+	}
+	return series, nil
+}
@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
+)
+
+var (
+	ErrIncidentNotFound       = errors.New("incident not found")
+	ErrIncidentStatusConflict = errors.New("incident status does not allow this change")
+	ErrContactNotFound        = errors.New("device contact not found")
+)
+
+type (
+	IncidentRepository interface {
+		Open(ctx context.Context, occurrence domain.IncidentOccurrence, reopenWindow time.Duration) (*domain.Incident, domain.IncidentChange, error)
+		Read(ctx context.Context, id uuid.UUID) (*domain.Incident, error)
+		List(ctx context.Context, filter domain.IncidentFilter) ([]*domain.Incident, error)
+		Acknowledge(ctx context.Context, id, userID uuid.UUID) (*domain.Incident, error)
+		Resolve(ctx context.Context, id, userID uuid.UUID) (*domain.Incident, error)
+		Escalate(ctx context.Context, maxLevel int, criticalBefore, warningBefore time.Time) ([]*domain.Incident, error)
+		Recipients(ctx context.Context, deviceID string, minLevel, maxLevel int) ([]string, error)
+	}
+
+	DeviceContactRepository interface {
+		Upsert(ctx context.Context, contact domain.DeviceContact) (*domain.DeviceContact, error)
+		ListByDevice(ctx context.Context, deviceID string) ([]*domain.DeviceContact, error)
+		Delete(ctx context.Context, deviceID string, userID uuid.UUID) error
+	}
+)
+
+type incidentRepository struct {
+	store *db.Store
+}
+
+func NewIncidentRepository(store *db.Store) IncidentRepository {
+	if store == nil {
+		log.Fatalf("[FATAL] store cannot be nil")
+	}
+	return &incidentRepository{store: store}
+}
+
+// Open opens an incident for the occurrence of a problem, or counts it as
+// another occurrence of the unresolved incident of the same lift, kind and
+// code. An incident resolved less than reopenWindow ago is reopened instead of
+// opening a new one. It reports how the occurrence changed the incident.
+func (ir *incidentRepository) Open(ctx context.Context, occurrence domain.IncidentOccurrence, reopenWindow time.Duration) (*domain.Incident, domain.IncidentChange, error) {
+	var incident *domain.Incident
+	var change domain.IncidentChange
+	openTx := func() error {
+		return ir.store.ExecTx(ctx, func(q *db.Queries) error {
+			var err error
+			incident, change, err = openIncident(ctx, q, occurrence, reopenWindow)
+			return err
+		})
+	}
+
+	err := openTx()
+	// Two occurrences racing to open the same incident both miss it; the one
+	// losing on the unique index counts as an occurrence of the other.
+	if isUniqueViolation(err, "incidents_device_id_kind_code_unresolved_key") {
+		err = openTx()
+	}
+	if err != nil {
+		if isForeignKeyViolation(err, "incidents_device_id_fkey") {
+			return nil, domain.IncidentRecurred, ErrDeviceNotFound
+		}
+		return nil, domain.IncidentRecurred, err
+	}
+	return incident, change, nil
+}
+
+// openIncident opens or recurs the incident of an occurrence using the
+// queries of a running transaction.
+func openIncident(ctx context.Context, q *db.Queries, occurrence domain.IncidentOccurrence, reopenWindow time.Duration) (*domain.Incident, domain.IncidentChange, error) {
+	existing, err := q.GetRecurringIncident(ctx, db.GetRecurringIncidentParams{
+		DeviceID:      occurrence.DeviceID,
+		Kind:          occurrence.Kind,
+		Code:          occurrence.Code,
+		ResolvedAfter: time.Now().Add(-reopenWindow),
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.IncidentRecurred, err
+	}
+
+	if err == nil {
+		dbIncident, err := q.RecurIncident(ctx, db.RecurIncidentParams{
+			Severity: occurrence.Severity,
+			Summary:  occurrence.Summary,
+			ID:       existing.ID,
+		})
+		if err != nil {
+			return nil, domain.IncidentRecurred, err
+		}
+		return dbToDomainIncident(dbIncident), dbToDomainIncident(existing).Recurrence(occurrence.Severity), nil
+	}
+
+	dbIncident, err := q.CreateIncident(ctx, db.CreateIncidentParams{
+		ID:       uuid.New(),
+		DeviceID: occurrence.DeviceID,
+		Kind:     occurrence.Kind,
+		Code:     occurrence.Code,
+		Severity: occurrence.Severity,
+		Summary:  occurrence.Summary,
+	})
+	if err != nil {
+		return nil, domain.IncidentRecurred, err
+	}
+	return dbToDomainIncident(dbIncident), domain.IncidentOpened, nil
+}
+
+func (ir *incidentRepository) Read(ctx context.Context, id uuid.UUID) (*domain.Incident, error) {
+	dbIncident, err := ir.store.GetIncident(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrIncidentNotFound
+		}
+		return nil, err
+	}
+	return dbToDomainIncident(dbIncident), nil
+}
+
+// List returns a page of incidents, newest first.
+func (ir *incidentRepository) List(ctx context.Context, filter domain.IncidentFilter) ([]*domain.Incident, error) {
+	params := db.ListIncidentsParams{
+		Status:   filter.Status,
+		DeviceID: filter.DeviceID,
+		Severity: filter.Severity,
+		PageSize: int32(filter.Limit),
+	}
+	if filter.CursorCreatedAt != nil {
+		params.CursorCreatedAt = pgtype.Timestamptz{Time: *filter.CursorCreatedAt, Valid: true}
+		params.CursorID = pgtype.UUID{Bytes: filter.CursorID, Valid: true}
+	}
+
+	dbIncidents, err := ir.store.ListIncidents(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return dbToDomainIncidents(dbIncidents), nil
+}
+
+// Acknowledge marks an open incident as acknowledged by the user. It fails
+// with ErrIncidentStatusConflict if the incident is no longer open.
+func (ir *incidentRepository) Acknowledge(ctx context.Context, id, userID uuid.UUID) (*domain.Incident, error) {
+	dbIncident, err := ir.store.AcknowledgeIncident(ctx, db.AcknowledgeIncidentParams{
+		UserID: userID,
+		ID:     id,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ir.statusConflict(ctx, id)
+		}
+		return nil, err
+	}
+	return dbToDomainIncident(dbIncident), nil
+}
+
+// Resolve marks an incident as resolved by the user. It fails with
+// ErrIncidentStatusConflict if the incident is already resolved.
+func (ir *incidentRepository) Resolve(ctx context.Context, id, userID uuid.UUID) (*domain.Incident, error) {
+	dbIncident, err := ir.store.ResolveIncident(ctx, db.ResolveIncidentParams{
+		UserID: userID,
+		ID:     id,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ir.statusConflict(ctx, id)
+		}
+		return nil, err
+	}
+	return dbToDomainIncident(dbIncident), nil
+}
+
+// statusConflict tells apart an incident a status update did not match from
+// a missing one.
+func (ir *incidentRepository) statusConflict(ctx context.Context, id uuid.UUID) error {
+	if _, err := ir.Read(ctx, id); err != nil {
+		return err
+	}
+	return ErrIncidentStatusConflict
+}
+
+// Escalate raises the level of the open incidents below maxLevel that were
+// opened, or last escalated, before criticalBefore or warningBefore depending
+// on their severity, and returns them.
+func (ir *incidentRepository) Escalate(ctx context.Context, maxLevel int, criticalBefore, warningBefore time.Time) ([]*domain.Incident, error) {
+	dbIncidents, err := ir.store.EscalateIncidents(ctx, db.EscalateIncidentsParams{
+		MaxLevel:       int32(maxLevel),
+		CriticalBefore: criticalBefore,
+		WarningBefore:  warningBefore,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dbToDomainIncidents(dbIncidents), nil
+}
+
+// Recipients returns the email addresses of the contacts of a device from
+// minLevel up to maxLevel.
+func (ir *incidentRepository) Recipients(ctx context.Context, deviceID string, minLevel, maxLevel int) ([]string, error) {
+	return ir.store.ListIncidentRecipients(ctx, db.ListIncidentRecipientsParams{
+		DeviceID: deviceID,
+		MinLevel: int32(minLevel),
+		MaxLevel: int32(maxLevel),
+	})
+}
+
+type deviceContactRepository struct {
+	store *db.Store
+}
+
+func NewDeviceContactRepository(store *db.Store) DeviceContactRepository {
+	if store == nil {
+		log.Fatalf("[FATAL] store cannot be nil")
+	}
+	return &deviceContactRepository{store: store}
+}
+
+// Upsert adds a user to the contacts of a device, or changes the escalation
+// level of an existing contact. Unknown users fail with ErrUserNotFound.
+func (cr *deviceContactRepository) Upsert(ctx context.Context, contact domain.DeviceContact) (*domain.DeviceContact, error) {
+	dbContact, err := cr.store.UpsertDeviceContact(ctx, db.UpsertDeviceContactParams{
+		DeviceID:        contact.DeviceID,
+		UserID:          contact.UserID,
+		EscalationLevel: int32(contact.EscalationLevel),
+	})
+	if err != nil {
+		if isForeignKeyViolation(err, "device_contacts_user_id_fkey") {
+			return nil, ErrUserNotFound
+		}
+		if isForeignKeyViolation(err, "device_contacts_device_id_fkey") {
+			return nil, ErrDeviceNotFound
+		}
+		return nil, err
+	}
+	return dbToDomainDeviceContact(dbContact), nil
+}
+
+func (cr *deviceContactRepository) ListByDevice(ctx context.Context, deviceID string) ([]*domain.DeviceContact, error) {
+	dbContacts, err := cr.store.ListDeviceContacts(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts := make([]*domain.DeviceContact, 0, len(dbContacts))
+	for _, c := range dbContacts {
+		contacts = append(contacts, dbToDomainDeviceContact(c))
+	}
+	return contacts, nil
+}
+
+func (cr *deviceContactRepository) Delete(ctx context.Context, deviceID string, userID uuid.UUID) error {
+	rows, err := cr.store.DeleteDeviceContact(ctx, db.DeleteDeviceContactParams{
+		DeviceID: deviceID,
+		UserID:   userID,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrContactNotFound
+	}
+	return nil
+}
+
+func dbToDomainIncidents(dbIncidents []db.Incident) []*domain.Incident {
+	incidents := make([]*domain.Incident, 0, len(dbIncidents))
+	for _, i := range dbIncidents {
+		incidents = append(incidents, dbToDomainIncident(i))
+	}
+	return incidents
+}
+
+func dbToDomainIncident(i db.Incident) *domain.Incident {
+	incident := &domain.Incident{
+		ID:        i.ID,
+		CreatedAt: i.CreatedAt,
+		UpdatedAt: i.UpdatedAt.Time,
+
+		DeviceID:    i.DeviceID,
+		Kind:        i.Kind,
+		Code:        i.Code,
+		Severity:    i.Severity,
+		Status:      i.Status,
+		Summary:     i.Summary,
+		Occurrences: int(i.Occurrences),
+		OpenedAt:    i.OpenedAt,
+		LastSeenAt:  i.LastSeenAt,
+
+		EscalationLevel: int(i.EscalationLevel),
+		EscalatedAt:     i.EscalatedAt.Time,
+
+		AcknowledgedAt: i.AcknowledgedAt.Time,
+		ResolvedAt:     i.ResolvedAt.Time,
+	}
+	if i.AcknowledgedBy.Valid {
+		incident.AcknowledgedBy = i.AcknowledgedBy.Bytes
+	}
+	if i.ResolvedBy.Valid {
+		incident.ResolvedBy = i.ResolvedBy.Bytes
+	}
+	return incident
+}
+
+func dbToDomainDeviceContact(c db.DeviceContact) *domain.DeviceContact {
+	return &domain.DeviceContact{
+		DeviceID:        c.DeviceID,
+		UserID:          c.UserID,
+		CreatedAt:       c.CreatedAt,
+		EscalationLevel: int(c.EscalationLevel),
+	}
+}
@@ -0,0 +1,15 @@
+CREATE TABLE "sessions" (
+  "id" uuid PRIMARY KEY,
+  "family_id" uuid NOT NULL,
+  "user_id" uuid NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+  "refresh_token_hash" varchar NOT NULL,
+  "user_agent" varchar NOT NULL,
+  "client_ip" varchar NOT NULL,
+  "replaced_by" uuid,
+  "revoked_at" timestamptz,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "sessions" ("user_id");
+CREATE INDEX ON "sessions" ("family_id");
@@ -0,0 +1,3 @@
+UPDATE "users" SET "role" = 'user' WHERE "role" NOT IN ('admin', 'operator', 'user');
+
+ALTER TABLE "users" ADD CONSTRAINT "users_role_check" CHECK ("role" IN ('admin', 'operator', 'user'));
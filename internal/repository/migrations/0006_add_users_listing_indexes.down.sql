@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "users_last_name_trgm_idx";
+DROP INDEX IF EXISTS "users_first_name_trgm_idx";
+DROP INDEX IF EXISTS "users_email_trgm_idx";
+DROP INDEX IF EXISTS "users_role_idx";
+DROP INDEX IF EXISTS "users_created_at_id_idx";
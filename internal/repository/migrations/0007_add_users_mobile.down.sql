@@ -0,0 +1,3 @@
+ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "users_mobile_key";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "mobile";
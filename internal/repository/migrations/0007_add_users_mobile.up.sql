@@ -0,0 +1,3 @@
+ALTER TABLE "users" ADD COLUMN "mobile" varchar(16);
+
+ALTER TABLE "users" ADD CONSTRAINT "users_mobile_key" UNIQUE ("mobile");
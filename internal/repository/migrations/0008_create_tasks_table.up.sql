@@ -0,0 +1,19 @@
+CREATE TABLE "tasks" (
+  "id" uuid PRIMARY KEY,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "owner_id" uuid NOT NULL REFERENCES "users" ("id"),
+  "public" bool NOT NULL DEFAULT true,
+  "title" varchar NOT NULL,
+  "description" text NOT NULL DEFAULT '',
+  "category" varchar NOT NULL DEFAULT '',
+  "location" varchar NOT NULL DEFAULT '',
+  "address" varchar NOT NULL DEFAULT '',
+  "deadline" varchar,
+  "budget" bigint NOT NULL DEFAULT 0,
+  "status" varchar NOT NULL DEFAULT 'open'
+);
+
+CREATE INDEX ON "tasks" ("owner_id");
+CREATE INDEX "tasks_created_at_id_idx" ON "tasks" ("created_at" DESC, "id" DESC) WHERE "deleted_at" IS NULL;
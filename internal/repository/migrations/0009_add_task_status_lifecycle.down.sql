@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS "task_status_history";
+DROP FUNCTION IF EXISTS "task_status_history_append_only"();
+
+ALTER TABLE "tasks" DROP CONSTRAINT IF EXISTS "tasks_status_check";
+ALTER TABLE "tasks" ALTER COLUMN "status" SET DEFAULT 'open';
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "assignee_id";
@@ -0,0 +1,33 @@
+ALTER TABLE "tasks" ADD COLUMN "assignee_id" uuid REFERENCES "users" ("id");
+ALTER TABLE "tasks" ALTER COLUMN "status" SET DEFAULT 'draft';
+ALTER TABLE "tasks" ADD CONSTRAINT "tasks_status_check"
+  CHECK ("status" IN ('draft', 'open', 'assigned', 'in_progress', 'completed', 'cancelled', 'disputed'));
+
+CREATE INDEX ON "tasks" ("assignee_id");
+
+CREATE TABLE "task_status_history" (
+  "id" bigserial PRIMARY KEY,
+  "task_id" uuid NOT NULL REFERENCES "tasks" ("id"),
+  "from_status" varchar NOT NULL,
+  "to_status" varchar NOT NULL,
+  "actor_id" uuid NOT NULL REFERENCES "users" ("id"),
+  "reason" text NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "task_status_history" ("task_id", "created_at");
+
+-- History rows are never changed or removed once written.
+CREATE FUNCTION "task_status_history_append_only"() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'task_status_history is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "task_status_history_no_update_delete"
+  BEFORE UPDATE OR DELETE ON "task_status_history"
+  FOR EACH ROW EXECUTE FUNCTION "task_status_history_append_only"();
+
+CREATE TRIGGER "task_status_history_no_truncate"
+  BEFORE TRUNCATE ON "task_status_history"
+  FOR EACH STATEMENT EXECUTE FUNCTION "task_status_history_append_only"();
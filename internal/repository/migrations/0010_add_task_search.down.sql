@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS "tasks_budget_idx";
+DROP INDEX IF EXISTS "tasks_category_idx";
+DROP INDEX IF EXISTS "tasks_coordinates_idx";
+DROP INDEX IF EXISTS "tasks_search_idx";
+
+ALTER TABLE "tasks" DROP CONSTRAINT IF EXISTS "tasks_coordinates_check";
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "longitude";
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "latitude";
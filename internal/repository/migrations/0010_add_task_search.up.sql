@@ -0,0 +1,11 @@
+ALTER TABLE "tasks" ADD COLUMN "latitude" double precision;
+ALTER TABLE "tasks" ADD COLUMN "longitude" double precision;
+ALTER TABLE "tasks" ADD CONSTRAINT "tasks_coordinates_check" CHECK (
+  ("latitude" IS NULL AND "longitude" IS NULL)
+  OR ("latitude" BETWEEN -90 AND 90 AND "longitude" BETWEEN -180 AND 180)
+);
+
+CREATE INDEX "tasks_search_idx" ON "tasks" USING gin (to_tsvector('simple', "title" || ' ' || "description"));
+CREATE INDEX "tasks_coordinates_idx" ON "tasks" ("latitude", "longitude");
+CREATE INDEX "tasks_category_idx" ON "tasks" ("category");
+CREATE INDEX "tasks_budget_idx" ON "tasks" ("budget");
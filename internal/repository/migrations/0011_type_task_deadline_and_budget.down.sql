@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS "tasks_deadline_idx";
+DROP INDEX IF EXISTS "tasks_budget_idx";
+
+ALTER TABLE "tasks" DROP CONSTRAINT IF EXISTS "tasks_budget_check";
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "budget_currency";
+ALTER TABLE "tasks" RENAME COLUMN "budget_amount" TO "budget";
+CREATE INDEX "tasks_budget_idx" ON "tasks" ("budget");
+
+ALTER TABLE "tasks" ALTER COLUMN "deadline" TYPE varchar
+  USING to_char("deadline" AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"');
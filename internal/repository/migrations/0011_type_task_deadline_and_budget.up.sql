@@ -0,0 +1,17 @@
+-- Deadlines were stored as RFC 3339 text; anything else cannot be recovered
+-- and is cleared.
+ALTER TABLE "tasks" ALTER COLUMN "deadline" TYPE timestamptz USING (
+  CASE
+    WHEN "deadline" ~ '^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})$' THEN "deadline"::timestamptz
+  END
+);
+
+ALTER TABLE "tasks" RENAME COLUMN "budget" TO "budget_amount";
+ALTER TABLE "tasks" ADD COLUMN "budget_currency" varchar(3) NOT NULL DEFAULT 'GEL';
+ALTER TABLE "tasks" ADD CONSTRAINT "tasks_budget_check" CHECK (
+  "budget_amount" >= 0 AND "budget_currency" IN ('GEL', 'USD', 'EUR')
+);
+
+DROP INDEX IF EXISTS "tasks_budget_idx";
+CREATE INDEX "tasks_budget_idx" ON "tasks" ("budget_currency", "budget_amount");
+CREATE INDEX "tasks_deadline_idx" ON "tasks" ("deadline");
@@ -0,0 +1,14 @@
+CREATE TABLE "task_attachments" (
+  "id" uuid PRIMARY KEY,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "deleted_at" timestamptz,
+  "task_id" uuid NOT NULL REFERENCES "tasks" ("id"),
+  "uploader_id" uuid NOT NULL REFERENCES "users" ("id"),
+  "name" varchar NOT NULL,
+  "content_type" varchar NOT NULL,
+  "size" bigint NOT NULL CHECK ("size" >= 0),
+  "sha256" varchar(64) NOT NULL,
+  "storage_key" varchar NOT NULL UNIQUE
+);
+
+CREATE INDEX ON "task_attachments" ("task_id", "created_at") WHERE "deleted_at" IS NULL;
@@ -0,0 +1,19 @@
+CREATE TABLE "bids" (
+  "id" uuid PRIMARY KEY,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz,
+  "task_id" uuid NOT NULL REFERENCES "tasks" ("id"),
+  "bidder_id" uuid NOT NULL REFERENCES "users" ("id"),
+  "amount" bigint NOT NULL,
+  "currency" varchar(3) NOT NULL,
+  "message" text NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'pending',
+  CONSTRAINT "bids_amount_check" CHECK ("amount" >= 0 AND "currency" IN ('GEL', 'USD', 'EUR')),
+  CONSTRAINT "bids_status_check" CHECK ("status" IN ('pending', 'accepted', 'rejected', 'withdrawn'))
+);
+
+CREATE INDEX ON "bids" ("task_id", "created_at");
+CREATE INDEX ON "bids" ("bidder_id");
+
+-- A user has at most one pending bid on a task.
+CREATE UNIQUE INDEX "bids_task_id_bidder_id_pending_key" ON "bids" ("task_id", "bidder_id") WHERE "status" = 'pending';
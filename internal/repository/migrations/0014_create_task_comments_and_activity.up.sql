@@ -0,0 +1,30 @@
+CREATE TABLE "task_comments" (
+  "id" uuid PRIMARY KEY,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "deleted_at" timestamptz,
+  "task_id" uuid NOT NULL REFERENCES "tasks" ("id"),
+  "author_id" uuid NOT NULL REFERENCES "users" ("id"),
+  "parent_id" uuid REFERENCES "task_comments" ("id"),
+  "body" text NOT NULL
+);
+
+CREATE INDEX ON "task_comments" ("task_id", "created_at");
+CREATE INDEX ON "task_comments" ("parent_id");
+
+-- One row per event on a task, newest first in the activity feed. Details
+-- holds the facts of the event as they were when it happened.
+CREATE TABLE "task_activity" (
+  "id" uuid PRIMARY KEY,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "deleted_at" timestamptz,
+  "task_id" uuid NOT NULL REFERENCES "tasks" ("id"),
+  "actor_id" uuid NOT NULL REFERENCES "users" ("id"),
+  "kind" varchar NOT NULL,
+  "subject_id" uuid,
+  "details" jsonb NOT NULL DEFAULT '{}',
+  CONSTRAINT "task_activity_kind_check"
+    CHECK ("kind" IN ('status_changed', 'bid_placed', 'bid_withdrawn', 'bid_accepted', 'attachment_added', 'comment_added'))
+);
+
+CREATE INDEX ON "task_activity" ("task_id", "created_at");
+CREATE INDEX ON "task_activity" ("subject_id");
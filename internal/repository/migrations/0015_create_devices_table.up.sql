@@ -0,0 +1,16 @@
+-- Devices are the lifts that report over MQTT. The ID is the one the lift
+-- publishes under, the `+` in Lift/+/events/heartbeat.
+CREATE TABLE "devices" (
+  "id" varchar(64) PRIMARY KEY,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "building" varchar NOT NULL DEFAULT '',
+  "address" varchar NOT NULL DEFAULT '',
+  "model" varchar NOT NULL DEFAULT '',
+  "install_date" date,
+  "owner_org" varchar NOT NULL DEFAULT ''
+);
+
+CREATE INDEX ON "devices" ("building") WHERE "deleted_at" IS NULL;
+CREATE INDEX ON "devices" ("owner_org") WHERE "deleted_at" IS NULL;
@@ -0,0 +1,14 @@
+-- The live state of a device, kept apart from the inventory in devices since
+-- it is rewritten on every heartbeat. Devices without a row were never seen.
+CREATE TABLE "device_status" (
+  "device_id" varchar(64) PRIMARY KEY REFERENCES "devices" ("id"),
+  "state" varchar NOT NULL,
+  "state_changed_at" timestamptz NOT NULL DEFAULT (now()),
+  "last_seen_at" timestamptz NOT NULL DEFAULT (now()),
+  "reported_at" timestamptz,
+  "firmware" varchar NOT NULL DEFAULT '',
+  "metrics" jsonb NOT NULL DEFAULT '{}',
+  CONSTRAINT "device_status_state_check" CHECK ("state" IN ('online', 'offline'))
+);
+
+CREATE INDEX ON "device_status" ("last_seen_at") WHERE "state" = 'online';
@@ -0,0 +1,2 @@
+DROP FUNCTION IF EXISTS "create_device_metrics_partition"(date);
+DROP TABLE IF EXISTS "device_metrics";
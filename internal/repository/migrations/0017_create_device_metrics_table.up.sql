@@ -0,0 +1,29 @@
+-- Metric samples reported by lifts, range partitioned by month. Partitions are
+-- created ahead of time by the create_metric_partitions maintenance task, so
+-- there is no default partition; samples for a month without one are rejected.
+CREATE TABLE "device_metrics" (
+  "device_id" varchar(64) NOT NULL REFERENCES "devices" ("id"),
+  "recorded_at" timestamptz NOT NULL,
+  "name" varchar(64) NOT NULL,
+  "value" double precision NOT NULL
+) PARTITION BY RANGE ("recorded_at");
+
+CREATE INDEX ON "device_metrics" ("device_id", "name", "recorded_at");
+
+-- Creates the partition holding the month of day, named device_metrics_YYYY_MM.
+-- Months start at midnight UTC. Creating an existing partition does nothing.
+CREATE FUNCTION "create_device_metrics_partition"("day" date) RETURNS void AS $$
+DECLARE
+  month_start date := date_trunc('month', "day")::date;
+BEGIN
+  EXECUTE format(
+    'CREATE TABLE IF NOT EXISTS %I PARTITION OF "device_metrics" FOR VALUES FROM (%L) TO (%L)',
+    'device_metrics_' || to_char(month_start, 'YYYY_MM'),
+    month_start::timestamp AT TIME ZONE 'UTC',
+    (month_start + interval '1 month')::timestamp AT TIME ZONE 'UTC'
+  );
+END;
+$$ LANGUAGE plpgsql;
+
+SELECT "create_device_metrics_partition"(current_date);
+SELECT "create_device_metrics_partition"((current_date + interval '1 month')::date);
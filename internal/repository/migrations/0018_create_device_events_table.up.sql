@@ -0,0 +1,14 @@
+-- Events lifts publish on Lift/<device ID>/events/<kind>, apart from
+-- heartbeats and metrics. Details holds the payload fields of the kind;
+-- occurred_at is by the lift's clock, created_at when the event was received.
+CREATE TABLE "device_events" (
+  "id" uuid PRIMARY KEY,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "device_id" varchar(64) NOT NULL REFERENCES "devices" ("id"),
+  "kind" varchar NOT NULL,
+  "occurred_at" timestamptz NOT NULL,
+  "details" jsonb NOT NULL DEFAULT '{}',
+  CONSTRAINT "device_events_kind_check" CHECK ("kind" IN ('door', 'fault', 'trip', 'alarm'))
+);
+
+CREATE INDEX ON "device_events" ("device_id", "created_at");
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "device_contacts";
+DROP TABLE IF EXISTS "incidents";
@@ -0,0 +1,44 @@
+-- Incidents opened from lift faults and outages. At most one incident per
+-- lift, kind and code is unresolved at a time; the problem happening again
+-- counts as another occurrence instead of opening a new incident. Escalation
+-- is timed from escalated_at, or opened_at before the first escalation.
+CREATE TABLE "incidents" (
+  "id" uuid PRIMARY KEY,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz,
+  "device_id" varchar(64) NOT NULL REFERENCES "devices" ("id"),
+  "kind" varchar NOT NULL,
+  "code" varchar NOT NULL,
+  "severity" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'open',
+  "summary" text NOT NULL DEFAULT '',
+  "occurrences" integer NOT NULL DEFAULT 1,
+  "opened_at" timestamptz NOT NULL DEFAULT (now()),
+  "last_seen_at" timestamptz NOT NULL DEFAULT (now()),
+  "escalation_level" integer NOT NULL DEFAULT 0,
+  "escalated_at" timestamptz,
+  "acknowledged_by" uuid REFERENCES "users" ("id"),
+  "acknowledged_at" timestamptz,
+  "resolved_by" uuid REFERENCES "users" ("id"),
+  "resolved_at" timestamptz,
+  CONSTRAINT "incidents_kind_check" CHECK ("kind" IN ('fault', 'offline')),
+  CONSTRAINT "incidents_severity_check" CHECK ("severity" IN ('warning', 'critical')),
+  CONSTRAINT "incidents_status_check" CHECK ("status" IN ('open', 'acknowledged', 'resolved'))
+);
+
+CREATE UNIQUE INDEX "incidents_device_id_kind_code_unresolved_key"
+  ON "incidents" ("device_id", "kind", "code") WHERE "status" <> 'resolved';
+CREATE INDEX ON "incidents" ("created_at");
+CREATE INDEX ON "incidents" ("device_id", "kind", "code", "created_at");
+CREATE INDEX ON "incidents" ("opened_at") WHERE "status" = 'open';
+
+-- Users notified of the incidents of a lift. Level 0 contacts are notified
+-- when an incident opens, each escalation adds the contacts of the next level.
+CREATE TABLE "device_contacts" (
+  "device_id" varchar(64) NOT NULL REFERENCES "devices" ("id"),
+  "user_id" uuid NOT NULL REFERENCES "users" ("id"),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "escalation_level" integer NOT NULL DEFAULT 0,
+  PRIMARY KEY ("device_id", "user_id"),
+  CONSTRAINT "device_contacts_escalation_level_check" CHECK ("escalation_level" >= 0)
+);
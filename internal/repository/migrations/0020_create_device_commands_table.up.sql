@@ -0,0 +1,22 @@
+-- Commands sent to lifts on Lift/<device ID>/commands/<name>. The ID is the
+-- correlation ID of the command; lifts reply on the reply topic of the command
+-- with it. Commands without a final reply by expires_at time out.
+CREATE TABLE "device_commands" (
+  "id" uuid PRIMARY KEY,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz,
+  "device_id" varchar(64) NOT NULL REFERENCES "devices" ("id"),
+  "name" varchar NOT NULL,
+  "args" jsonb NOT NULL DEFAULT '{}',
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "issued_by" uuid NOT NULL REFERENCES "users" ("id"),
+  "expires_at" timestamptz NOT NULL,
+  "acked_at" timestamptz,
+  "completed_at" timestamptz,
+  "result" jsonb,
+  "error" text NOT NULL DEFAULT '',
+  CONSTRAINT "device_commands_status_check" CHECK ("status" IN ('pending', 'acked', 'completed', 'failed', 'timed_out'))
+);
+
+CREATE INDEX ON "device_commands" ("device_id", "created_at");
+CREATE INDEX ON "device_commands" ("expires_at") WHERE "status" IN ('pending', 'acked');
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "users_mobile_key";
+
+ALTER TABLE "users" ADD CONSTRAINT "users_mobile_key" UNIQUE ("mobile");
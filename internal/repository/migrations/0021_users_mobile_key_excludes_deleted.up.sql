@@ -0,0 +1,5 @@
+-- Soft deleted users no longer hold on to their mobile number, so it can be
+-- registered again.
+ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "users_mobile_key";
+
+CREATE UNIQUE INDEX "users_mobile_key" ON "users" ("mobile") WHERE "deleted_at" IS NULL;
@@ -0,0 +1 @@
+COMMENT ON COLUMN "incidents"."escalated_at" IS NULL;
@@ -0,0 +1,3 @@
+-- Documents how escalation is timed now that an incident becoming critical
+-- restarts it.
+COMMENT ON COLUMN "incidents"."escalated_at" IS 'Start of the current escalation step: the last escalation, or the incident becoming critical. Escalation is timed from opened_at while unset.';
@@ -0,0 +1,5 @@
+-- Soft deleted users no longer hold on to their email address either, so it
+-- can be signed up with again.
+ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "users_email_key";
+
+CREATE UNIQUE INDEX "users_email_key" ON "users" ("email") WHERE "deleted_at" IS NULL;
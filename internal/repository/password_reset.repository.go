@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
+)
+
+var (
+	ErrInvalidPasswordReset = errors.New("reset token is invalid, expired or already used")
+)
+
+type (
+	PasswordResetRepository interface {
+		Reset(ctx context.Context, token, hashedPassword string) (uuid.UUID, error)
+	}
+)
+
+type passwordResetRepository struct {
+	store *db.Store
+}
+
+func NewPasswordResetRepository(store *db.Store) PasswordResetRepository {
+	if store == nil {
+		log.Fatalf("[FATAL] store cannot be nil")
+	}
+	return &passwordResetRepository{store: store}
+}
+
+// Reset consumes the reset token and stores the new password hash within one
+// transaction. Every other outstanding reset token of the user is invalidated.
+// It returns the ID of the user whose password was changed.
+func (pr *passwordResetRepository) Reset(ctx context.Context, token, hashedPassword string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := pr.store.ExecTx(ctx, func(q *db.Queries) error {
+		reset, err := q.UsePasswordReset(ctx, helper.HashToken(token))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrInvalidPasswordReset
+			}
+			return err
+		}
+
+		if err := q.UpdateUserPassword(ctx, db.UpdateUserPasswordParams{
+			ID:       reset.UserID,
+			Password: hashedPassword,
+		}); err != nil {
+			return err
+		}
+
+		userID = reset.UserID
+		return q.InvalidatePasswordResets(ctx, reset.UserID)
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return userID, nil
+}
@@ -0,0 +1,52 @@
+-- ============================================
+-- QUERIES FOR TASK BIDS
+-- ============================================
+
+-- name: CreateBid :one
+INSERT INTO bids (
+    id, task_id, bidder_id, amount, currency, message
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetBid :one
+SELECT *
+FROM bids
+WHERE id = $1;
+
+-- name: HasPendingBidsInOtherCurrency :one
+SELECT EXISTS (
+    SELECT 1
+    FROM bids
+    WHERE task_id = sqlc.arg(task_id) AND status = 'pending' AND currency <> sqlc.arg(currency)
+);
+
+-- name: ListTaskBids :many
+SELECT *
+FROM bids
+WHERE task_id = sqlc.arg(task_id)
+    AND (sqlc.narg(bidder_id)::uuid IS NULL OR bidder_id = sqlc.narg(bidder_id))
+ORDER BY created_at, id;
+
+-- name: SetBidStatus :one
+UPDATE bids
+SET
+    status = sqlc.arg(to_status),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND status = sqlc.arg(from_status)
+RETURNING *;
+
+-- name: RejectPendingBids :execrows
+UPDATE bids
+SET
+    status = 'rejected',
+    updated_at = now()
+WHERE task_id = sqlc.arg(task_id) AND status = 'pending' AND id <> sqlc.arg(accepted_id);
+
+-- name: AcceptBidderPendingBid :one
+UPDATE bids
+SET
+    status = 'accepted',
+    updated_at = now()
+WHERE task_id = sqlc.arg(task_id) AND bidder_id = sqlc.arg(bidder_id) AND status = 'pending'
+RETURNING *;
@@ -0,0 +1,54 @@
+-- ============================================
+-- QUERIES FOR THE LIFT DEVICE REGISTRY
+-- ============================================
+
+-- name: CreateDevice :one
+INSERT INTO devices (
+    id, building, address, model, install_date, owner_org
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (id) DO UPDATE
+SET
+    created_at = now(),
+    updated_at = NULL,
+    deleted_at = NULL,
+    building = EXCLUDED.building,
+    address = EXCLUDED.address,
+    model = EXCLUDED.model,
+    install_date = EXCLUDED.install_date,
+    owner_org = EXCLUDED.owner_org
+WHERE devices.deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: GetDevice :one
+SELECT *
+FROM devices
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: ListDevices :many
+SELECT *
+FROM devices
+WHERE deleted_at IS NULL
+    AND (sqlc.narg(building)::varchar IS NULL OR building = sqlc.narg(building))
+    AND (sqlc.narg(owner_org)::varchar IS NULL OR owner_org = sqlc.narg(owner_org))
+    AND (sqlc.narg(cursor_id)::varchar IS NULL OR id > sqlc.narg(cursor_id))
+ORDER BY id
+LIMIT sqlc.arg(page_size);
+
+-- name: UpdateDevice :one
+UPDATE devices
+SET
+    building = COALESCE(sqlc.narg(building), building),
+    address = COALESCE(sqlc.narg(address), address),
+    model = COALESCE(sqlc.narg(model), model),
+    install_date = COALESCE(sqlc.narg(install_date), install_date),
+    owner_org = COALESCE(sqlc.narg(owner_org), owner_org),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SoftDeleteDevice :execrows
+UPDATE devices
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL;
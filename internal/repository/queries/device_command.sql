@@ -0,0 +1,62 @@
+-- ============================================
+-- QUERIES FOR LIFT COMMANDS
+-- ============================================
+
+-- name: CreateDeviceCommand :one
+INSERT INTO device_commands (
+    id, device_id, name, args, issued_by, expires_at
+)
+SELECT sqlc.arg(id), id, sqlc.arg(name), sqlc.arg(args), sqlc.arg(issued_by), sqlc.arg(expires_at)
+FROM devices
+WHERE id = sqlc.arg(device_id) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: GetDeviceCommand :one
+SELECT *
+FROM device_commands
+WHERE id = sqlc.arg(id) AND device_id = sqlc.arg(device_id);
+
+-- name: ListDeviceCommands :many
+SELECT *
+FROM device_commands
+WHERE device_id = sqlc.arg(device_id)
+    AND (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status))
+    AND (
+        sqlc.narg(cursor_created_at)::timestamptz IS NULL
+        OR (created_at, id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid)
+    )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: AckDeviceCommand :one
+UPDATE device_commands
+SET
+    status = 'acked',
+    acked_at = now(),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND device_id = sqlc.arg(device_id) AND name = sqlc.arg(name)
+    AND status = 'pending'
+    AND expires_at > now()
+RETURNING *;
+
+-- name: FinishDeviceCommand :one
+UPDATE device_commands
+SET
+    status = sqlc.arg(status),
+    result = sqlc.arg(result),
+    error = sqlc.arg(error),
+    completed_at = now(),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND device_id = sqlc.arg(device_id) AND name = sqlc.arg(name)
+    AND status IN ('pending', 'acked')
+    AND expires_at > now()
+RETURNING *;
+
+-- name: TimeOutDeviceCommands :many
+UPDATE device_commands
+SET
+    status = 'timed_out',
+    completed_at = now(),
+    updated_at = now()
+WHERE status IN ('pending', 'acked') AND expires_at <= now()
+RETURNING *;
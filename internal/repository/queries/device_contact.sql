@@ -0,0 +1,32 @@
+-- ============================================
+-- QUERIES FOR LIFT CONTACTS
+-- ============================================
+
+-- name: UpsertDeviceContact :one
+INSERT INTO device_contacts (
+    device_id, user_id, escalation_level
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (device_id, user_id) DO UPDATE
+SET escalation_level = EXCLUDED.escalation_level
+RETURNING *;
+
+-- name: ListDeviceContacts :many
+SELECT *
+FROM device_contacts
+WHERE device_id = $1
+ORDER BY escalation_level, created_at;
+
+-- name: DeleteDeviceContact :execrows
+DELETE FROM device_contacts
+WHERE device_id = $1 AND user_id = $2;
+
+-- name: ListIncidentRecipients :many
+SELECT u.email
+FROM device_contacts c
+JOIN users u ON u.id = c.user_id
+WHERE c.device_id = sqlc.arg(device_id)
+    AND c.escalation_level BETWEEN sqlc.arg(min_level) AND sqlc.arg(max_level)
+    AND u.deleted_at IS NULL
+ORDER BY u.email;
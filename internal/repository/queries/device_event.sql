@@ -0,0 +1,24 @@
+-- ============================================
+-- QUERIES FOR LIFT EVENTS
+-- ============================================
+
+-- name: CreateDeviceEvent :one
+INSERT INTO device_events (
+    id, device_id, kind, occurred_at, details
+)
+SELECT sqlc.arg(id), id, sqlc.arg(kind), sqlc.arg(occurred_at), sqlc.arg(details)
+FROM devices
+WHERE id = sqlc.arg(device_id) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: ListDeviceEvents :many
+SELECT *
+FROM device_events
+WHERE device_id = sqlc.arg(device_id)
+    AND (sqlc.narg(kind)::varchar IS NULL OR kind = sqlc.narg(kind))
+    AND (
+        sqlc.narg(cursor_created_at)::timestamptz IS NULL
+        OR (created_at, id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid)
+    )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
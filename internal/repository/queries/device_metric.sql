@@ -0,0 +1,27 @@
+-- ============================================
+-- QUERIES FOR LIFT TELEMETRY
+-- ============================================
+
+-- name: CreateDeviceMetrics :exec
+INSERT INTO device_metrics (
+    device_id, recorded_at, name, value
+)
+SELECT sqlc.arg(device_id)::varchar, sqlc.arg(recorded_at)::timestamptz, unnest(sqlc.arg(names)::varchar[]), unnest(sqlc.arg(values)::float8[]);
+
+-- name: ListDeviceMetricBuckets :many
+SELECT
+    name,
+    date_bin(sqlc.arg(step)::interval, recorded_at, sqlc.arg(from_time)::timestamptz)::timestamptz AS bucket,
+    avg(value)::float8 AS avg_value,
+    min(value)::float8 AS min_value,
+    max(value)::float8 AS max_value,
+    count(*) AS samples
+FROM device_metrics
+WHERE device_id = sqlc.arg(device_id)
+    AND recorded_at >= sqlc.arg(from_time) AND recorded_at < sqlc.arg(to_time)
+    AND (sqlc.narg(name)::varchar IS NULL OR name = sqlc.narg(name))
+GROUP BY name, bucket
+ORDER BY name, bucket;
+
+-- name: CreateDeviceMetricsPartition :exec
+SELECT create_device_metrics_partition(sqlc.arg(day)::date);
@@ -0,0 +1,38 @@
+-- ============================================
+-- QUERIES FOR THE LIVE STATE OF DEVICES
+-- ============================================
+
+-- name: RecordDeviceHeartbeat :one
+WITH previous AS (
+    SELECT state
+    FROM device_status
+    WHERE device_id = sqlc.arg(device_id)
+)
+INSERT INTO device_status (
+    device_id, state, state_changed_at, last_seen_at, reported_at, firmware, metrics
+)
+SELECT id, 'online', now(), now(), sqlc.narg(reported_at), sqlc.arg(firmware), sqlc.arg(metrics)
+FROM devices
+WHERE id = sqlc.arg(device_id) AND deleted_at IS NULL
+ON CONFLICT (device_id) DO UPDATE
+SET
+    state = 'online',
+    state_changed_at = CASE WHEN device_status.state = 'online' THEN device_status.state_changed_at ELSE now() END,
+    last_seen_at = now(),
+    reported_at = EXCLUDED.reported_at,
+    firmware = COALESCE(NULLIF(EXCLUDED.firmware, ''), device_status.firmware),
+    metrics = EXCLUDED.metrics
+RETURNING *, COALESCE((SELECT state FROM previous), '')::varchar AS previous_state;
+
+-- name: GetDeviceStatus :one
+SELECT *
+FROM device_status
+WHERE device_id = $1;
+
+-- name: MarkStaleDevicesOffline :many
+UPDATE device_status
+SET
+    state = 'offline',
+    state_changed_at = now()
+WHERE state = 'online' AND last_seen_at < sqlc.arg(seen_before)
+RETURNING device_id;
@@ -0,0 +1,94 @@
+-- ============================================
+-- QUERIES FOR LIFT INCIDENTS
+-- ============================================
+
+-- name: CreateIncident :one
+INSERT INTO incidents (
+    id, device_id, kind, code, severity, summary
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetIncident :one
+SELECT *
+FROM incidents
+WHERE id = $1;
+
+-- name: GetRecurringIncident :one
+SELECT *
+FROM incidents
+WHERE device_id = sqlc.arg(device_id) AND kind = sqlc.arg(kind) AND code = sqlc.arg(code)
+    AND (status <> 'resolved' OR resolved_at > sqlc.arg(resolved_after)::timestamptz)
+ORDER BY created_at DESC
+LIMIT 1
+FOR UPDATE;
+
+-- name: RecurIncident :one
+UPDATE incidents
+SET
+    occurrences = occurrences + 1,
+    last_seen_at = now(),
+    severity = CASE WHEN sqlc.arg(severity)::varchar = 'critical' THEN 'critical' ELSE severity END,
+    summary = sqlc.arg(summary),
+    status = CASE WHEN status = 'resolved' THEN 'open' ELSE status END,
+    opened_at = CASE WHEN status = 'resolved' THEN now() ELSE opened_at END,
+    escalation_level = CASE WHEN status = 'resolved' THEN 0 ELSE escalation_level END,
+    escalated_at = CASE
+        WHEN status = 'resolved' THEN NULL
+        WHEN severity <> 'critical' AND sqlc.arg(severity)::varchar = 'critical' THEN now()
+        ELSE escalated_at
+    END,
+    acknowledged_by = CASE WHEN status = 'resolved' THEN NULL ELSE acknowledged_by END,
+    acknowledged_at = CASE WHEN status = 'resolved' THEN NULL ELSE acknowledged_at END,
+    resolved_by = NULL,
+    resolved_at = NULL,
+    updated_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListIncidents :many
+SELECT *
+FROM incidents
+WHERE (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status))
+    AND (sqlc.narg(device_id)::varchar IS NULL OR device_id = sqlc.narg(device_id))
+    AND (sqlc.narg(severity)::varchar IS NULL OR severity = sqlc.narg(severity))
+    AND (
+        sqlc.narg(cursor_created_at)::timestamptz IS NULL
+        OR (created_at, id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid)
+    )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: AcknowledgeIncident :one
+UPDATE incidents
+SET
+    status = 'acknowledged',
+    acknowledged_by = sqlc.arg(user_id)::uuid,
+    acknowledged_at = now(),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND status = 'open'
+RETURNING *;
+
+-- name: ResolveIncident :one
+UPDATE incidents
+SET
+    status = 'resolved',
+    resolved_by = sqlc.arg(user_id)::uuid,
+    resolved_at = now(),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND status <> 'resolved'
+RETURNING *;
+
+-- name: EscalateIncidents :many
+UPDATE incidents
+SET
+    escalation_level = escalation_level + 1,
+    escalated_at = now(),
+    updated_at = now()
+WHERE status = 'open'
+    AND escalation_level < sqlc.arg(max_level)
+    AND COALESCE(escalated_at, opened_at) < CASE severity
+        WHEN 'critical' THEN sqlc.arg(critical_before)::timestamptz
+        ELSE sqlc.arg(warning_before)::timestamptz
+    END
+RETURNING *;
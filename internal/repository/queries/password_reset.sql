@@ -0,0 +1,23 @@
+-- ============================================
+-- QUERIES FOR PASSWORD RESET
+-- ============================================
+
+-- name: CreatePasswordReset :one
+INSERT INTO password_resets (
+    user_id, token_hash
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: UsePasswordReset :one
+UPDATE password_resets
+SET is_used = TRUE
+WHERE token_hash = $1
+    AND is_used = FALSE
+    AND expired_at > now()
+RETURNING *;
+
+-- name: InvalidatePasswordResets :exec
+UPDATE password_resets
+SET is_used = TRUE
+WHERE user_id = $1 AND is_used = FALSE;
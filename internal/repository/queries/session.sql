@@ -0,0 +1,31 @@
+-- ============================================
+-- QUERIES FOR SESSION MANAGEMENT
+-- ============================================
+
+-- name: CreateSession :one
+INSERT INTO sessions (
+    id, family_id, user_id, refresh_token_hash, user_agent, client_ip, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetSession :one
+SELECT *
+FROM sessions
+WHERE id = $1;
+
+-- name: RotateSession :one
+UPDATE sessions
+SET revoked_at = now(), replaced_by = sqlc.arg(replaced_by)::uuid
+WHERE id = sqlc.arg(id) AND revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeSessionFamily :exec
+UPDATE sessions
+SET revoked_at = now()
+WHERE family_id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeUserSessions :exec
+UPDATE sessions
+SET revoked_at = now()
+WHERE user_id = $1 AND revoked_at IS NULL;
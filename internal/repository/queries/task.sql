@@ -0,0 +1,102 @@
+-- ============================================
+-- QUERIES FOR TASK MANAGEMENT
+-- ============================================
+
+-- name: CreateTask :one
+INSERT INTO tasks (
+    id, owner_id, public, title, description, category, location, address, deadline,
+    budget_amount, budget_currency, latitude, longitude
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+) RETURNING *;
+
+-- name: GetTask :one
+SELECT *
+FROM tasks
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: ListTasks :many
+SELECT *
+FROM tasks
+WHERE deleted_at IS NULL
+    AND ((public AND status <> 'draft') OR owner_id = sqlc.arg(viewer_id) OR assignee_id = sqlc.arg(viewer_id) OR sqlc.arg(read_all)::bool)
+    AND (sqlc.narg(owner_id)::uuid IS NULL OR owner_id = sqlc.narg(owner_id))
+    AND (
+        sqlc.narg(cursor_created_at)::timestamptz IS NULL
+        OR (created_at, id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid)
+    )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: UpdateTask :one
+UPDATE tasks
+SET
+    public = COALESCE(sqlc.narg(public), public),
+    title = COALESCE(sqlc.narg(title), title),
+    description = COALESCE(sqlc.narg(description), description),
+    category = COALESCE(sqlc.narg(category), category),
+    location = COALESCE(sqlc.narg(location), location),
+    address = COALESCE(sqlc.narg(address), address),
+    deadline = COALESCE(sqlc.narg(deadline), deadline),
+    budget_amount = COALESCE(sqlc.narg(budget_amount), budget_amount),
+    budget_currency = COALESCE(sqlc.narg(budget_currency)::varchar, budget_currency),
+    latitude = COALESCE(sqlc.narg(latitude), latitude),
+    longitude = COALESCE(sqlc.narg(longitude), longitude),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SearchTasks :many
+SELECT *
+FROM tasks
+WHERE deleted_at IS NULL
+    AND ((public AND status <> 'draft') OR owner_id = sqlc.arg(viewer_id) OR assignee_id = sqlc.arg(viewer_id) OR sqlc.arg(read_all)::bool)
+    AND (
+        sqlc.narg(query)::text IS NULL
+        OR to_tsvector('simple', title || ' ' || description) @@ websearch_to_tsquery('simple', sqlc.narg(query))
+    )
+    AND (sqlc.narg(category)::varchar IS NULL OR category = sqlc.narg(category))
+    AND (sqlc.narg(budget_currency)::varchar IS NULL OR budget_currency = sqlc.narg(budget_currency))
+    AND (sqlc.narg(budget_min)::bigint IS NULL OR budget_amount >= sqlc.narg(budget_min))
+    AND (sqlc.narg(budget_max)::bigint IS NULL OR budget_amount <= sqlc.narg(budget_max))
+    AND (sqlc.narg(deadline_from)::timestamptz IS NULL OR deadline >= sqlc.narg(deadline_from))
+    AND (sqlc.narg(deadline_to)::timestamptz IS NULL OR deadline <= sqlc.narg(deadline_to))
+    AND (
+        sqlc.narg(center_lat)::double precision IS NULL
+        OR (
+            latitude BETWEEN sqlc.narg(min_lat) AND sqlc.narg(max_lat)
+            AND longitude BETWEEN sqlc.narg(min_lng) AND sqlc.narg(max_lng)
+            AND 2 * 6371 * asin(least(1, sqrt(
+                power(sin(radians(latitude - sqlc.narg(center_lat)) / 2), 2)
+                + cos(radians(sqlc.narg(center_lat))) * cos(radians(latitude))
+                * power(sin(radians(longitude - sqlc.narg(center_lng)::double precision) / 2), 2)
+            ))) <= sqlc.narg(radius_km)::double precision
+        )
+    )
+    AND (
+        sqlc.narg(cursor_created_at)::timestamptz IS NULL
+        OR (created_at, id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid)
+    )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: SoftDeleteTask :execrows
+UPDATE tasks
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: TransitionTaskStatus :one
+UPDATE tasks
+SET
+    status = sqlc.arg(to_status),
+    assignee_id = sqlc.narg(assignee_id),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND status = sqlc.arg(from_status) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: CreateTaskStatusHistory :one
+INSERT INTO task_status_history (
+    task_id, from_status, to_status, actor_id, reason
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
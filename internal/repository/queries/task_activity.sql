@@ -0,0 +1,28 @@
+-- ============================================
+-- QUERIES FOR THE TASK ACTIVITY FEED
+-- ============================================
+
+-- name: CreateTaskActivity :exec
+INSERT INTO task_activity (
+    id, task_id, actor_id, kind, subject_id, details
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: ListTaskActivity :many
+SELECT *
+FROM task_activity
+WHERE task_id = sqlc.arg(task_id)
+    AND deleted_at IS NULL
+    AND (sqlc.arg(all_bids)::bool OR kind NOT IN ('bid_placed', 'bid_withdrawn') OR actor_id = sqlc.arg(viewer_id))
+    AND (
+        sqlc.narg(cursor_created_at)::timestamptz IS NULL
+        OR (created_at, id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid)
+    )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
+
+-- name: SoftDeleteTaskActivity :exec
+UPDATE task_activity
+SET deleted_at = now()
+WHERE subject_id = $1 AND deleted_at IS NULL;
@@ -0,0 +1,21 @@
+-- ============================================
+-- QUERIES FOR TASK ATTACHMENTS
+-- ============================================
+
+-- name: CreateTaskAttachment :one
+INSERT INTO task_attachments (
+    id, task_id, uploader_id, name, content_type, size, sha256, storage_key
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: ListTaskAttachments :many
+SELECT *
+FROM task_attachments
+WHERE task_id = $1 AND deleted_at IS NULL
+ORDER BY created_at, id;
+
+-- name: GetTaskAttachment :one
+SELECT *
+FROM task_attachments
+WHERE id = $1 AND deleted_at IS NULL;
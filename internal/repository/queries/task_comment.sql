@@ -0,0 +1,31 @@
+-- ============================================
+-- QUERIES FOR TASK COMMENTS
+-- ============================================
+
+-- name: CreateTaskComment :one
+INSERT INTO task_comments (
+    id, task_id, author_id, parent_id, body
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetTaskComment :one
+SELECT *
+FROM task_comments
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: ListTaskComments :many
+SELECT *
+FROM task_comments
+WHERE task_id = sqlc.arg(task_id)
+    AND (
+        sqlc.narg(cursor_created_at)::timestamptz IS NULL
+        OR (created_at, id) > (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid)
+    )
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_size);
+
+-- name: SoftDeleteTaskComment :execrows
+UPDATE task_comments
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL;
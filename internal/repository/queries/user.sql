@@ -4,12 +4,70 @@
 
 -- name: CreateUser :one
 INSERT INTO users (
-    id, first_name, last_name, email, password, email_verified
+    id, first_name, last_name, email, password, role, email_verified, mobile
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7, $8
 ) RETURNING *;
 
 -- name: Read :one
 SELECT *
 FROM users
 WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: GetUserByEmail :one
+SELECT *
+FROM users
+WHERE email = $1 AND deleted_at IS NULL;
+
+-- name: VerifyUserEmail :one
+UPDATE users
+SET email_verified = TRUE, updated_at = now()
+WHERE id = $1 AND email = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET password = $2, updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: UpdateUser :one
+UPDATE users
+SET
+    first_name = COALESCE(sqlc.narg(first_name), first_name),
+    last_name = COALESCE(sqlc.narg(last_name), last_name),
+    email = COALESCE(sqlc.narg(email), email),
+    email_verified = CASE
+        WHEN sqlc.narg(email)::varchar IS NULL OR sqlc.narg(email) = email THEN email_verified
+        ELSE FALSE
+    END,
+    password = COALESCE(sqlc.narg(password), password),
+    role = COALESCE(sqlc.narg(role), role),
+    mobile = COALESCE(sqlc.narg(mobile), mobile),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: ListUsers :many
+SELECT *
+FROM users
+WHERE
+    (sqlc.narg(role)::varchar IS NULL OR role = sqlc.narg(role))
+    AND (sqlc.narg(email_verified)::bool IS NULL OR email_verified = sqlc.narg(email_verified))
+    AND (sqlc.narg(deleted)::bool IS NULL OR (deleted_at IS NOT NULL) = sqlc.narg(deleted))
+    AND (
+        sqlc.narg(search)::varchar IS NULL
+        OR email ILIKE '%' || sqlc.narg(search) || '%'
+        OR first_name ILIKE '%' || sqlc.narg(search) || '%'
+        OR last_name ILIKE '%' || sqlc.narg(search) || '%'
+    )
+    AND (
+        sqlc.narg(cursor_created_at)::timestamptz IS NULL
+        OR (created_at, id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::uuid)
+    )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_size);
@@ -0,0 +1,19 @@
+-- ============================================
+-- QUERIES FOR EMAIL VERIFICATION
+-- ============================================
+
+-- name: CreateVerifyEmail :one
+INSERT INTO verify_emails (
+    user_id, email, secret_code_hash
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: UseVerifyEmail :one
+UPDATE verify_emails
+SET is_used = TRUE
+WHERE id = sqlc.arg(id)
+    AND secret_code_hash = sqlc.arg(secret_code_hash)
+    AND is_used = FALSE
+    AND expired_at > now()
+RETURNING *;
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionRevoked  = errors.New("session already revoked")
+)
+
+type (
+	SessionRepository interface {
+		Create(ctx context.Context, session domain.Session) (*domain.Session, error)
+		Read(ctx context.Context, id uuid.UUID) (*domain.Session, error)
+		Replace(ctx context.Context, previousID uuid.UUID, session domain.Session) (*domain.Session, error)
+		RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+		RevokeUser(ctx context.Context, userID uuid.UUID) error
+	}
+)
+
+type sessionRepository struct {
+	store *db.Store
+}
+
+func NewSessionRepository(store *db.Store) SessionRepository {
+	if store == nil {
+		log.Fatalf("[FATAL] store cannot be nil")
+	}
+	return &sessionRepository{store: store}
+}
+
+func (sr *sessionRepository) Create(ctx context.Context, session domain.Session) (*domain.Session, error) {
+	return createSession(ctx, sr.store.Queries, session)
+}
+
+// createSession stores a session using the given queries, which may belong
+// to a running transaction.
+func createSession(ctx context.Context, q *db.Queries, session domain.Session) (*domain.Session, error) {
+	dbSession, err := q.CreateSession(ctx, db.CreateSessionParams{
+		ID:               session.ID,
+		FamilyID:         session.FamilyID,
+		UserID:           session.UserID,
+		RefreshTokenHash: session.RefreshTokenHash,
+		UserAgent:        session.UserAgent,
+		ClientIp:         session.ClientIP,
+		ExpiresAt:        session.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dbToDomainSession(dbSession), nil
+}
+
+func (sr *sessionRepository) Read(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
+	dbSession, err := sr.store.GetSession(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return dbToDomainSession(dbSession), nil
+}
+
+// Replace revokes the previous session, records its successor and stores the
+// successor within one transaction, so a failure leaves the previous refresh
+// token usable. It fails with ErrSessionRevoked if the previous session was
+// already revoked, which happens when two requests race to rotate the same
+// refresh token.
+func (sr *sessionRepository) Replace(ctx context.Context, previousID uuid.UUID, session domain.Session) (*domain.Session, error) {
+	var createdSession *domain.Session
+	err := sr.store.ExecTx(ctx, func(q *db.Queries) error {
+		_, err := q.RotateSession(ctx, db.RotateSessionParams{
+			ID:         previousID,
+			ReplacedBy: session.ID,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrSessionRevoked
+			}
+			return err
+		}
+
+		createdSession, err = createSession(ctx, q, session)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return createdSession, nil
+}
+
+func (sr *sessionRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	return sr.store.RevokeSessionFamily(ctx, familyID)
+}
+
+func (sr *sessionRepository) RevokeUser(ctx context.Context, userID uuid.UUID) error {
+	return sr.store.RevokeUserSessions(ctx, userID)
+}
+
+func dbToDomainSession(s db.Session) *domain.Session {
+	return &domain.Session{
+		ID:        s.ID,
+		CreatedAt: s.CreatedAt,
+		ExpiresAt: s.ExpiresAt,
+		RevokedAt: s.RevokedAt.Time,
+
+		FamilyID:         s.FamilyID,
+		UserID:           s.UserID,
+		RefreshTokenHash: s.RefreshTokenHash,
+		UserAgent:        s.UserAgent,
+		ClientIP:         s.ClientIp,
+		ReplacedBy:       uuid.UUID(s.ReplacedBy.Bytes),
+	}
+}
@@ -9,8 +9,190 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/vgrigalashvili/veemon/pkg/money"
 )
 
+type Bid struct {
+	ID        uuid.UUID          `json:"id"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	TaskID    uuid.UUID          `json:"task_id"`
+	BidderID  uuid.UUID          `json:"bidder_id"`
+	Amount    int64              `json:"amount"`
+	Currency  money.Currency     `json:"currency"`
+	Message   string             `json:"message"`
+	Status    string             `json:"status"`
+}
+
+type Device struct {
+	ID          string             `json:"id"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt   pgtype.Timestamptz `json:"deleted_at"`
+	Building    string             `json:"building"`
+	Address     string             `json:"address"`
+	Model       string             `json:"model"`
+	InstallDate pgtype.Date        `json:"install_date"`
+	OwnerOrg    string             `json:"owner_org"`
+}
+
+type DeviceCommand struct {
+	ID          uuid.UUID          `json:"id"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	DeviceID    string             `json:"device_id"`
+	Name        string             `json:"name"`
+	Args        []byte             `json:"args"`
+	Status      string             `json:"status"`
+	IssuedBy    uuid.UUID          `json:"issued_by"`
+	ExpiresAt   time.Time          `json:"expires_at"`
+	AckedAt     pgtype.Timestamptz `json:"acked_at"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+	Result      []byte             `json:"result"`
+	Error       string             `json:"error"`
+}
+
+type DeviceContact struct {
+	DeviceID        string    `json:"device_id"`
+	UserID          uuid.UUID `json:"user_id"`
+	CreatedAt       time.Time `json:"created_at"`
+	EscalationLevel int32     `json:"escalation_level"`
+}
+
+type DeviceEvent struct {
+	ID         uuid.UUID `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	DeviceID   string    `json:"device_id"`
+	Kind       string    `json:"kind"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Details    []byte    `json:"details"`
+}
+
+type DeviceMetric struct {
+	DeviceID   string    `json:"device_id"`
+	RecordedAt time.Time `json:"recorded_at"`
+	Name       string    `json:"name"`
+	Value      float64   `json:"value"`
+}
+
+type DeviceStatus struct {
+	DeviceID       string             `json:"device_id"`
+	State          string             `json:"state"`
+	StateChangedAt time.Time          `json:"state_changed_at"`
+	LastSeenAt     time.Time          `json:"last_seen_at"`
+	ReportedAt     pgtype.Timestamptz `json:"reported_at"`
+	Firmware       string             `json:"firmware"`
+	Metrics        []byte             `json:"metrics"`
+}
+
+type Incident struct {
+	ID              uuid.UUID          `json:"id"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	DeviceID        string             `json:"device_id"`
+	Kind            string             `json:"kind"`
+	Code            string             `json:"code"`
+	Severity        string             `json:"severity"`
+	Status          string             `json:"status"`
+	Summary         string             `json:"summary"`
+	Occurrences     int32              `json:"occurrences"`
+	OpenedAt        time.Time          `json:"opened_at"`
+	LastSeenAt      time.Time          `json:"last_seen_at"`
+	EscalationLevel int32              `json:"escalation_level"`
+	EscalatedAt     pgtype.Timestamptz `json:"escalated_at"`
+	AcknowledgedBy  pgtype.UUID        `json:"acknowledged_by"`
+	AcknowledgedAt  pgtype.Timestamptz `json:"acknowledged_at"`
+	ResolvedBy      pgtype.UUID        `json:"resolved_by"`
+	ResolvedAt      pgtype.Timestamptz `json:"resolved_at"`
+}
+
+type PasswordReset struct {
+	ID        int64     `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	TokenHash string    `json:"token_hash"`
+	IsUsed    bool      `json:"is_used"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+type Session struct {
+	ID               uuid.UUID          `json:"id"`
+	FamilyID         uuid.UUID          `json:"family_id"`
+	UserID           uuid.UUID          `json:"user_id"`
+	RefreshTokenHash string             `json:"refresh_token_hash"`
+	UserAgent        string             `json:"user_agent"`
+	ClientIp         string             `json:"client_ip"`
+	ReplacedBy       pgtype.UUID        `json:"replaced_by"`
+	RevokedAt        pgtype.Timestamptz `json:"revoked_at"`
+	ExpiresAt        time.Time          `json:"expires_at"`
+	CreatedAt        time.Time          `json:"created_at"`
+}
+
+type Task struct {
+	ID             uuid.UUID          `json:"id"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	DeletedAt      pgtype.Timestamptz `json:"deleted_at"`
+	OwnerID        uuid.UUID          `json:"owner_id"`
+	Public         bool               `json:"public"`
+	Title          string             `json:"title"`
+	Description    string             `json:"description"`
+	Category       string             `json:"category"`
+	Location       string             `json:"location"`
+	Address        string             `json:"address"`
+	Deadline       pgtype.Timestamptz `json:"deadline"`
+	BudgetAmount   int64              `json:"budget_amount"`
+	Status         string             `json:"status"`
+	AssigneeID     pgtype.UUID        `json:"assignee_id"`
+	Latitude       *float64           `json:"latitude"`
+	Longitude      *float64           `json:"longitude"`
+	BudgetCurrency money.Currency     `json:"budget_currency"`
+}
+
+type TaskActivity struct {
+	ID        uuid.UUID          `json:"id"`
+	CreatedAt time.Time          `json:"created_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	TaskID    uuid.UUID          `json:"task_id"`
+	ActorID   uuid.UUID          `json:"actor_id"`
+	Kind      string             `json:"kind"`
+	SubjectID pgtype.UUID        `json:"subject_id"`
+	Details   []byte             `json:"details"`
+}
+
+type TaskAttachment struct {
+	ID          uuid.UUID          `json:"id"`
+	CreatedAt   time.Time          `json:"created_at"`
+	DeletedAt   pgtype.Timestamptz `json:"deleted_at"`
+	TaskID      uuid.UUID          `json:"task_id"`
+	UploaderID  uuid.UUID          `json:"uploader_id"`
+	Name        string             `json:"name"`
+	ContentType string             `json:"content_type"`
+	Size        int64              `json:"size"`
+	Sha256      string             `json:"sha256"`
+	StorageKey  string             `json:"storage_key"`
+}
+
+type TaskComment struct {
+	ID        uuid.UUID          `json:"id"`
+	CreatedAt time.Time          `json:"created_at"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	TaskID    uuid.UUID          `json:"task_id"`
+	AuthorID  uuid.UUID          `json:"author_id"`
+	ParentID  pgtype.UUID        `json:"parent_id"`
+	Body      string             `json:"body"`
+}
+
+type TaskStatusHistory struct {
+	ID         int64     `json:"id"`
+	TaskID     uuid.UUID `json:"task_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	ActorID    uuid.UUID `json:"actor_id"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
 type User struct {
 	ID            uuid.UUID          `json:"id"`
 	CreatedAt     time.Time          `json:"created_at"`
@@ -22,4 +204,15 @@ type User struct {
 	Password      string             `json:"password"`
 	Role          string             `json:"role"`
 	EmailVerified bool               `json:"email_verified"`
+	Mobile        *string            `json:"mobile"`
+}
+
+type VerifyEmail struct {
+	ID             int64     `json:"id"`
+	UserID         uuid.UUID `json:"user_id"`
+	Email          string    `json:"email"`
+	SecretCodeHash string    `json:"secret_code_hash"`
+	IsUsed         bool      `json:"is_used"`
+	CreatedAt      time.Time `json:"created_at"`
+	ExpiredAt      time.Time `json:"expired_at"`
 }
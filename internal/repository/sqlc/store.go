@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Store provides all functions to execute db queries and transactions.
+type Store struct {
+	*Queries
+	connPool *pgxpool.Pool
+}
+
+// NewStore creates a new Store backed by a connection pool.
+func NewStore(connPool *pgxpool.Pool) *Store {
+	return &Store{
+		Queries:  New(connPool),
+		connPool: connPool,
+	}
+}
+
+// ExecTx executes fn within a database transaction. The transaction is rolled
+// back if fn returns an error and committed otherwise.
+func (store *Store) ExecTx(ctx context.Context, fn func(*Queries) error) error {
+	tx, err := store.connPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	q := New(tx)
+	err = fn(q)
+	if err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Ping checks that the database is reachable.
+func (store *Store) Ping(ctx context.Context) error {
+	return store.connPool.Ping(ctx)
+}
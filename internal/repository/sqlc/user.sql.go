@@ -9,15 +9,16 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createUser = `-- name: CreateUser :one
 
 INSERT INTO users (
-    id, first_name, last_name, email, password, email_verified
+    id, first_name, last_name, email, password, role, email_verified, mobile
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
-) RETURNING id, created_at, updated_at, deleted_at, first_name, last_name, email, password, role, email_verified
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING id, created_at, updated_at, deleted_at, first_name, last_name, email, password, role, email_verified, mobile
 `
 
 type CreateUserParams struct {
@@ -26,7 +27,9 @@ type CreateUserParams struct {
 	LastName      *string   `json:"last_name"`
 	Email         string    `json:"email"`
 	Password      string    `json:"password"`
+	Role          string    `json:"role"`
 	EmailVerified bool      `json:"email_verified"`
+	Mobile        *string   `json:"mobile"`
 }
 
 // ============================================
@@ -39,7 +42,9 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.LastName,
 		arg.Email,
 		arg.Password,
+		arg.Role,
 		arg.EmailVerified,
+		arg.Mobile,
 	)
 	var i User
 	err := row.Scan(
@@ -53,12 +58,109 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Password,
 		&i.Role,
 		&i.EmailVerified,
+		&i.Mobile,
 	)
 	return i, err
 }
 
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, created_at, updated_at, deleted_at, first_name, last_name, email, password, role, email_verified, mobile
+FROM users
+WHERE email = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.Password,
+		&i.Role,
+		&i.EmailVerified,
+		&i.Mobile,
+	)
+	return i, err
+}
+
+const listUsers = `-- name: ListUsers :many
+SELECT id, created_at, updated_at, deleted_at, first_name, last_name, email, password, role, email_verified, mobile
+FROM users
+WHERE
+    ($1::varchar IS NULL OR role = $1)
+    AND ($2::bool IS NULL OR email_verified = $2)
+    AND ($3::bool IS NULL OR (deleted_at IS NOT NULL) = $3)
+    AND (
+        $4::varchar IS NULL
+        OR email ILIKE '%' || $4 || '%'
+        OR first_name ILIKE '%' || $4 || '%'
+        OR last_name ILIKE '%' || $4 || '%'
+    )
+    AND (
+        $5::timestamptz IS NULL
+        OR (created_at, id) < ($5, $6::uuid)
+    )
+ORDER BY created_at DESC, id DESC
+LIMIT $7
+`
+
+type ListUsersParams struct {
+	Role            *string            `json:"role"`
+	EmailVerified   *bool              `json:"email_verified"`
+	Deleted         *bool              `json:"deleted"`
+	Search          *string            `json:"search"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.UUID        `json:"cursor_id"`
+	PageSize        int32              `json:"page_size"`
+}
+
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsers,
+		arg.Role,
+		arg.EmailVerified,
+		arg.Deleted,
+		arg.Search,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.FirstName,
+			&i.LastName,
+			&i.Email,
+			&i.Password,
+			&i.Role,
+			&i.EmailVerified,
+			&i.Mobile,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const read = `-- name: Read :one
-SELECT id, created_at, updated_at, deleted_at, first_name, last_name, email, password, role, email_verified
+SELECT id, created_at, updated_at, deleted_at, first_name, last_name, email, password, role, email_verified, mobile
 FROM users
 WHERE id = $1 AND deleted_at IS NULL
 `
@@ -77,6 +179,123 @@ func (q *Queries) Read(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.Password,
 		&i.Role,
 		&i.EmailVerified,
+		&i.Mobile,
+	)
+	return i, err
+}
+
+const softDeleteUser = `-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) SoftDeleteUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateUser = `-- name: UpdateUser :one
+UPDATE users
+SET
+    first_name = COALESCE($1, first_name),
+    last_name = COALESCE($2, last_name),
+    email = COALESCE($3, email),
+    email_verified = CASE
+        WHEN $3::varchar IS NULL OR $3 = email THEN email_verified
+        ELSE FALSE
+    END,
+    password = COALESCE($4, password),
+    role = COALESCE($5, role),
+    mobile = COALESCE($6, mobile),
+    updated_at = now()
+WHERE id = $7 AND deleted_at IS NULL
+RETURNING id, created_at, updated_at, deleted_at, first_name, last_name, email, password, role, email_verified, mobile
+`
+
+type UpdateUserParams struct {
+	FirstName *string   `json:"first_name"`
+	LastName  *string   `json:"last_name"`
+	Email     *string   `json:"email"`
+	Password  *string   `json:"password"`
+	Role      *string   `json:"role"`
+	Mobile    *string   `json:"mobile"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUser,
+		arg.FirstName,
+		arg.LastName,
+		arg.Email,
+		arg.Password,
+		arg.Role,
+		arg.Mobile,
+		arg.ID,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.Password,
+		&i.Role,
+		&i.EmailVerified,
+		&i.Mobile,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users
+SET password = $2, updated_at = now()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type UpdateUserPasswordParams struct {
+	ID       uuid.UUID `json:"id"`
+	Password string    `json:"password"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.Exec(ctx, updateUserPassword, arg.ID, arg.Password)
+	return err
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :one
+UPDATE users
+SET email_verified = TRUE, updated_at = now()
+WHERE id = $1 AND email = $2 AND deleted_at IS NULL
+RETURNING id, created_at, updated_at, deleted_at, first_name, last_name, email, password, role, email_verified, mobile
+`
+
+type VerifyUserEmailParams struct {
+	ID    uuid.UUID `json:"id"`
+	Email string    `json:"email"`
+}
+
+func (q *Queries) VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, verifyUserEmail, arg.ID, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.Password,
+		&i.Role,
+		&i.EmailVerified,
+		&i.Mobile,
 	)
 	return i, err
 }
@@ -0,0 +1,371 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
+	"github.com/vgrigalashvili/veemon/pkg/money"
+)
+
+var (
+	ErrTaskNotFound       = errors.New("task not found")
+	ErrTaskStatusConflict = errors.New("task status was changed concurrently")
+	ErrAssigneeNotFound   = errors.New("assignee not found")
+	ErrPendingBidCurrency = errors.New("budget currency cannot change while bids in another currency are pending")
+)
+
+type (
+	TaskRepository interface {
+		Create(ctx context.Context, task domain.Task) (*domain.Task, error)
+		Read(ctx context.Context, id uuid.UUID) (*domain.Task, error)
+		List(ctx context.Context, viewer domain.Actor, filter domain.TaskFilter) ([]*domain.Task, error)
+		Search(ctx context.Context, viewer domain.Actor, search domain.TaskSearch) ([]*domain.Task, error)
+		Update(ctx context.Context, id uuid.UUID, update domain.TaskUpdate) (*domain.Task, error)
+		Delete(ctx context.Context, id uuid.UUID) error
+		Transition(ctx context.Context, transition domain.TaskTransition) (*domain.Task, error)
+	}
+)
+
+type taskRepository struct {
+	store *db.Store
+}
+
+func NewTaskRepository(store *db.Store) TaskRepository {
+	if store == nil {
+		log.Fatalf("[FATAL] store cannot be nil")
+	}
+	return &taskRepository{store: store}
+}
+
+func (tr *taskRepository) Create(ctx context.Context, task domain.Task) (*domain.Task, error) {
+	params := db.CreateTaskParams{
+		ID:          task.ID,
+		OwnerID:     task.OwnerID,
+		Public:      task.Public,
+		Title:       task.Title,
+		Description: task.Description,
+		Category:    task.Category,
+		Location:    task.Location,
+		Address:     task.Address,
+		Latitude:    task.Latitude,
+		Longitude:   task.Longitude,
+
+		BudgetAmount:   task.Budget.Amount,
+		BudgetCurrency: task.Budget.Currency,
+	}
+	if !task.Deadline.IsZero() {
+		params.Deadline = pgtype.Timestamptz{Time: task.Deadline, Valid: true}
+	}
+
+	dbTask, err := tr.store.CreateTask(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return dbToDomainTask(dbTask), nil
+}
+
+func (tr *taskRepository) Read(ctx context.Context, id uuid.UUID) (*domain.Task, error) {
+	dbTask, err := tr.store.GetTask(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTaskNotFound
+		}
+		return nil, err
+	}
+	return dbToDomainTask(dbTask), nil
+}
+
+// List returns the tasks matching the filter that the viewer may see, newest
+// first: published public tasks, tasks the viewer owns or is assigned to, and
+// every task if the viewer may read private tasks.
+func (tr *taskRepository) List(ctx context.Context, viewer domain.Actor, filter domain.TaskFilter) ([]*domain.Task, error) {
+	params := db.ListTasksParams{
+		ViewerID: viewer.ID,
+		ReadAll:  viewer.Can(domain.PermissionTasksRead),
+		PageSize: int32(filter.Limit),
+	}
+	if filter.OwnerID != nil {
+		params.OwnerID = pgtype.UUID{Bytes: *filter.OwnerID, Valid: true}
+	}
+	if filter.CursorCreatedAt != nil {
+		params.CursorCreatedAt = pgtype.Timestamptz{Time: *filter.CursorCreatedAt, Valid: true}
+		params.CursorID = pgtype.UUID{Bytes: filter.CursorID, Valid: true}
+	}
+
+	dbTasks, err := tr.store.ListTasks(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*domain.Task, 0, len(dbTasks))
+	for _, t := range dbTasks {
+		tasks = append(tasks, dbToDomainTask(t))
+	}
+	return tasks, nil
+}
+
+// Search returns the tasks matching the search that the viewer may see,
+// newest first. Tasks near a point are found with a bounding box pre-filter
+// followed by an exact haversine distance check.
+func (tr *taskRepository) Search(ctx context.Context, viewer domain.Actor, search domain.TaskSearch) ([]*domain.Task, error) {
+	params := db.SearchTasksParams{
+		ViewerID: viewer.ID,
+		ReadAll:  viewer.Can(domain.PermissionTasksRead),
+		PageSize: int32(search.Limit),
+	}
+	if search.Query != "" {
+		params.Query = &search.Query
+	}
+	if search.Category != "" {
+		params.Category = &search.Category
+	}
+	if search.BudgetCurrency != "" {
+		currency := string(search.BudgetCurrency)
+		params.BudgetCurrency = &currency
+	}
+	params.BudgetMin = search.BudgetMin
+	params.BudgetMax = search.BudgetMax
+	if search.DeadlineFrom != nil {
+		params.DeadlineFrom = pgtype.Timestamptz{Time: *search.DeadlineFrom, Valid: true}
+	}
+	if search.DeadlineTo != nil {
+		params.DeadlineTo = pgtype.Timestamptz{Time: *search.DeadlineTo, Valid: true}
+	}
+	if near := search.Near; near != nil {
+		minLat, maxLat, minLng, maxLng := helper.BoundingBox(near.Latitude, near.Longitude, near.RadiusKm)
+		params.CenterLat = &near.Latitude
+		params.CenterLng = &near.Longitude
+		params.RadiusKm = &near.RadiusKm
+		params.MinLat = &minLat
+		params.MaxLat = &maxLat
+		params.MinLng = &minLng
+		params.MaxLng = &maxLng
+	}
+	if search.CursorCreatedAt != nil {
+		params.CursorCreatedAt = pgtype.Timestamptz{Time: *search.CursorCreatedAt, Valid: true}
+		params.CursorID = pgtype.UUID{Bytes: search.CursorID, Valid: true}
+	}
+
+	dbTasks, err := tr.store.SearchTasks(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*domain.Task, 0, len(dbTasks))
+	for _, t := range dbTasks {
+		tasks = append(tasks, dbToDomainTask(t))
+	}
+	return tasks, nil
+}
+
+// Update applies a partial update to the task. A budget in another currency
+// than the pending bids on the task fails with ErrPendingBidCurrency.
+func (tr *taskRepository) Update(ctx context.Context, id uuid.UUID, update domain.TaskUpdate) (*domain.Task, error) {
+	params := db.UpdateTaskParams{
+		ID:          id,
+		Public:      update.Public,
+		Title:       update.Title,
+		Description: update.Description,
+		Category:    update.Category,
+		Location:    update.Location,
+		Address:     update.Address,
+		Latitude:    update.Latitude,
+		Longitude:   update.Longitude,
+	}
+	if update.Deadline != nil {
+		params.Deadline = pgtype.Timestamptz{Time: *update.Deadline, Valid: true}
+	}
+	if update.Budget != nil {
+		currency := string(update.Budget.Currency)
+		params.BudgetAmount = &update.Budget.Amount
+		params.BudgetCurrency = &currency
+	}
+
+	var dbTask db.Task
+	err := tr.store.ExecTx(ctx, func(q *db.Queries) error {
+		if update.Budget != nil {
+			pending, err := q.HasPendingBidsInOtherCurrency(ctx, db.HasPendingBidsInOtherCurrencyParams{
+				TaskID:   id,
+				Currency: update.Budget.Currency,
+			})
+			if err != nil {
+				return err
+			}
+			if pending {
+				return ErrPendingBidCurrency
+			}
+		}
+
+		var err error
+		dbTask, err = q.UpdateTask(ctx, params)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTaskNotFound
+		}
+		return nil, err
+	}
+	return dbToDomainTask(dbTask), nil
+}
+
+func (tr *taskRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	rows, err := tr.store.SoftDeleteTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrTaskNotFound
+	}
+	return nil
+}
+
+// Transition moves the task from transition.From to transition.To and records
+// the change in the status history within one transaction. It fails with
+// ErrTaskStatusConflict if the task is no longer in transition.From. A task
+// leaving open settles its pending bids in the same transaction: a pending bid
+// of the new assignee is accepted and all others are rejected.
+func (tr *taskRepository) Transition(ctx context.Context, transition domain.TaskTransition) (*domain.Task, error) {
+	var task *domain.Task
+	err := tr.store.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		task, err = transitionTask(ctx, q, transition)
+		if err != nil {
+			return err
+		}
+		if transition.From != domain.TaskStatusOpen {
+			return nil
+		}
+		return settlePendingBids(ctx, q, transition)
+	})
+	if err != nil {
+		if isForeignKeyViolation(err, "tasks_assignee_id_fkey") {
+			return nil, ErrAssigneeNotFound
+		}
+		return nil, err
+	}
+	return task, nil
+}
+
+// transitionTask changes the task status and records it in the status history
+// and the activity feed using the queries of a running transaction.
+func transitionTask(ctx context.Context, q *db.Queries, transition domain.TaskTransition) (*domain.Task, error) {
+	params := db.TransitionTaskStatusParams{
+		ID:         transition.TaskID,
+		FromStatus: transition.From,
+		ToStatus:   transition.To,
+	}
+	if transition.AssigneeID != uuid.Nil {
+		params.AssigneeID = pgtype.UUID{Bytes: transition.AssigneeID, Valid: true}
+	}
+
+	dbTask, err := q.TransitionTaskStatus(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTaskStatusConflict
+		}
+		return nil, err
+	}
+
+	_, err = q.CreateTaskStatusHistory(ctx, db.CreateTaskStatusHistoryParams{
+		TaskID:     transition.TaskID,
+		FromStatus: transition.From,
+		ToStatus:   transition.To,
+		ActorID:    transition.ActorID,
+		Reason:     transition.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	details := map[string]any{"from": transition.From, "to": transition.To}
+	if transition.Reason != "" {
+		details["reason"] = transition.Reason
+	}
+	if transition.AssigneeID != uuid.Nil {
+		details["assignee_id"] = transition.AssigneeID
+	}
+	err = recordActivity(ctx, q, domain.Activity{
+		TaskID:  transition.TaskID,
+		ActorID: transition.ActorID,
+		Kind:    domain.ActivityStatusChanged,
+		Details: details,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dbToDomainTask(dbTask), nil
+}
+
+// settlePendingBids accepts the pending bid of the assignee, if any, and
+// rejects the other pending bids of a task that is no longer open.
+func settlePendingBids(ctx context.Context, q *db.Queries, transition domain.TaskTransition) error {
+	acceptedID := uuid.Nil
+	if transition.AssigneeID != uuid.Nil {
+		dbBid, err := q.AcceptBidderPendingBid(ctx, db.AcceptBidderPendingBidParams{
+			TaskID:   transition.TaskID,
+			BidderID: transition.AssigneeID,
+		})
+		switch {
+		case err == nil:
+			acceptedID = dbBid.ID
+			err = recordActivity(ctx, q, domain.Activity{
+				TaskID:    dbBid.TaskID,
+				ActorID:   transition.ActorID,
+				Kind:      domain.ActivityBidAccepted,
+				SubjectID: dbBid.ID,
+				Details:   map[string]any{"bidder_id": dbBid.BidderID},
+			})
+			if err != nil {
+				return err
+			}
+		case !errors.Is(err, pgx.ErrNoRows):
+			return err
+		}
+	}
+
+	_, err := q.RejectPendingBids(ctx, db.RejectPendingBidsParams{
+		TaskID:     transition.TaskID,
+		AcceptedID: acceptedID,
+	})
+	return err
+}
+
+func dbToDomainTask(t db.Task) *domain.Task {
+	task := &domain.Task{
+		ID:        t.ID,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt.Time,
+		DeletedAt: t.DeletedAt.Time,
+
+		OwnerID:     t.OwnerID,
+		Public:      t.Public,
+		Title:       t.Title,
+		Description: t.Description,
+		Category:    t.Category,
+		Location:    t.Location,
+		Address:     t.Address,
+		Status:      t.Status,
+
+		Deadline: t.Deadline.Time,
+		Budget: money.Money{
+			Amount:   t.BudgetAmount,
+			Currency: t.BudgetCurrency,
+		},
+
+		Latitude:  t.Latitude,
+		Longitude: t.Longitude,
+	}
+	if t.AssigneeID.Valid {
+		task.AssigneeID = t.AssigneeID.Bytes
+	}
+	return task
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
+)
+
+type (
+	ActivityRepository interface {
+		ListByTask(ctx context.Context, taskID uuid.UUID, filter domain.ActivityFilter) ([]*domain.Activity, error)
+	}
+)
+
+type activityRepository struct {
+	store *db.Store
+}
+
+func NewActivityRepository(store *db.Store) ActivityRepository {
+	if store == nil {
+		log.Fatalf("[FATAL] store cannot be nil")
+	}
+	return &activityRepository{store: store}
+}
+
+// ListByTask returns a page of the activity of a task, newest first.
+func (ar *activityRepository) ListByTask(ctx context.Context, taskID uuid.UUID, filter domain.ActivityFilter) ([]*domain.Activity, error) {
+	params := db.ListTaskActivityParams{
+		TaskID:   taskID,
+		AllBids:  filter.AllBids,
+		ViewerID: filter.ViewerID,
+		PageSize: int32(filter.Limit),
+	}
+	if filter.CursorCreatedAt != nil {
+		params.CursorCreatedAt = pgtype.Timestamptz{Time: *filter.CursorCreatedAt, Valid: true}
+		params.CursorID = pgtype.UUID{Bytes: filter.CursorID, Valid: true}
+	}
+
+	dbActivity, err := ar.store.ListTaskActivity(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := make([]*domain.Activity, 0, len(dbActivity))
+	for _, a := range dbActivity {
+		entry, err := dbToDomainActivity(a)
+		if err != nil {
+			return nil, err
+		}
+		activity = append(activity, entry)
+	}
+	return activity, nil
+}
+
+// recordActivity adds an entry to the activity feed of a task. It takes the
+// queries of the transaction that makes the change, so the feed never shows
+// changes that were rolled back.
+func recordActivity(ctx context.Context, q *db.Queries, activity domain.Activity) error {
+	details, err := json.Marshal(activity.Details)
+	if err != nil {
+		return err
+	}
+	if activity.Details == nil {
+		details = []byte("{}")
+	}
+
+	params := db.CreateTaskActivityParams{
+		ID:      uuid.New(),
+		TaskID:  activity.TaskID,
+		ActorID: activity.ActorID,
+		Kind:    activity.Kind,
+		Details: details,
+	}
+	if activity.SubjectID != uuid.Nil {
+		params.SubjectID = pgtype.UUID{Bytes: activity.SubjectID, Valid: true}
+	}
+	return q.CreateTaskActivity(ctx, params)
+}
+
+func dbToDomainActivity(a db.TaskActivity) (*domain.Activity, error) {
+	activity := &domain.Activity{
+		ID:        a.ID,
+		CreatedAt: a.CreatedAt,
+		TaskID:    a.TaskID,
+		ActorID:   a.ActorID,
+		Kind:      a.Kind,
+	}
+	if a.SubjectID.Valid {
+		activity.SubjectID = a.SubjectID.Bytes
+	}
+	if err := json.Unmarshal(a.Details, &activity.Details); err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
+)
+
+var (
+	ErrAttachmentNotFound = errors.New("attachment not found")
+)
+
+type (
+	AttachmentRepository interface {
+		Create(ctx context.Context, attachment domain.Attachment) (*domain.Attachment, error)
+		Read(ctx context.Context, id uuid.UUID) (*domain.Attachment, error)
+		ListByTask(ctx context.Context, taskID uuid.UUID) ([]*domain.Attachment, error)
+	}
+)
+
+type attachmentRepository struct {
+	store *db.Store
+}
+
+func NewAttachmentRepository(store *db.Store) AttachmentRepository {
+	if store == nil {
+		log.Fatalf("[FATAL] store cannot be nil")
+	}
+	return &attachmentRepository{store: store}
+}
+
+// Create adds an attachment and its entry in the task activity feed.
+func (ar *attachmentRepository) Create(ctx context.Context, attachment domain.Attachment) (*domain.Attachment, error) {
+	var createdAttachment *domain.Attachment
+	err := ar.store.ExecTx(ctx, func(q *db.Queries) error {
+		dbAttachment, err := q.CreateTaskAttachment(ctx, db.CreateTaskAttachmentParams{
+			ID:          attachment.ID,
+			TaskID:      attachment.TaskID,
+			UploaderID:  attachment.UploaderID,
+			Name:        attachment.Name,
+			ContentType: attachment.ContentType,
+			Size:        attachment.Size,
+			Sha256:      attachment.Checksum,
+			StorageKey:  attachment.StorageKey,
+		})
+		if err != nil {
+			return err
+		}
+		createdAttachment = dbToDomainAttachment(dbAttachment)
+
+		return recordActivity(ctx, q, domain.Activity{
+			TaskID:    createdAttachment.TaskID,
+			ActorID:   createdAttachment.UploaderID,
+			Kind:      domain.ActivityAttachmentAdded,
+			SubjectID: createdAttachment.ID,
+			Details: map[string]any{
+				"name":         createdAttachment.Name,
+				"content_type": createdAttachment.ContentType,
+				"size":         createdAttachment.Size,
+			},
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return createdAttachment, nil
+}
+
+func (ar *attachmentRepository) Read(ctx context.Context, id uuid.UUID) (*domain.Attachment, error) {
+	dbAttachment, err := ar.store.GetTaskAttachment(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+	return dbToDomainAttachment(dbAttachment), nil
+}
+
+func (ar *attachmentRepository) ListByTask(ctx context.Context, taskID uuid.UUID) ([]*domain.Attachment, error) {
+	dbAttachments, err := ar.store.ListTaskAttachments(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments := make([]*domain.Attachment, 0, len(dbAttachments))
+	for _, a := range dbAttachments {
+		attachments = append(attachments, dbToDomainAttachment(a))
+	}
+	return attachments, nil
+}
+
+func dbToDomainAttachment(a db.TaskAttachment) *domain.Attachment {
+	return &domain.Attachment{
+		ID:          a.ID,
+		CreatedAt:   a.CreatedAt,
+		DeletedAt:   a.DeletedAt.Time,
+		TaskID:      a.TaskID,
+		UploaderID:  a.UploaderID,
+		Name:        a.Name,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Checksum:    a.Sha256,
+		StorageKey:  a.StorageKey,
+	}
+}
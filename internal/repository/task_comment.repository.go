@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
+)
+
+var (
+	ErrCommentNotFound = errors.New("comment not found")
+)
+
+type (
+	CommentRepository interface {
+		Create(ctx context.Context, comment domain.Comment) (*domain.Comment, error)
+		Read(ctx context.Context, id uuid.UUID) (*domain.Comment, error)
+		ListByTask(ctx context.Context, taskID uuid.UUID, filter domain.CommentFilter) ([]*domain.Comment, error)
+		Delete(ctx context.Context, id uuid.UUID) error
+	}
+)
+
+type commentRepository struct {
+	store *db.Store
+}
+
+func NewCommentRepository(store *db.Store) CommentRepository {
+	if store == nil {
+		log.Fatalf("[FATAL] store cannot be nil")
+	}
+	return &commentRepository{store: store}
+}
+
+// Create adds a comment and its entry in the task activity feed.
+func (cr *commentRepository) Create(ctx context.Context, comment domain.Comment) (*domain.Comment, error) {
+	params := db.CreateTaskCommentParams{
+		ID:       comment.ID,
+		TaskID:   comment.TaskID,
+		AuthorID: comment.AuthorID,
+		Body:     comment.Body,
+	}
+	if comment.ParentID != uuid.Nil {
+		params.ParentID = pgtype.UUID{Bytes: comment.ParentID, Valid: true}
+	}
+
+	var createdComment *domain.Comment
+	err := cr.store.ExecTx(ctx, func(q *db.Queries) error {
+		dbComment, err := q.CreateTaskComment(ctx, params)
+		if err != nil {
+			return err
+		}
+		createdComment = dbToDomainComment(dbComment)
+
+		details := map[string]any{"body": createdComment.Body}
+		if createdComment.ParentID != uuid.Nil {
+			details["parent_id"] = createdComment.ParentID
+		}
+		return recordActivity(ctx, q, domain.Activity{
+			TaskID:    createdComment.TaskID,
+			ActorID:   createdComment.AuthorID,
+			Kind:      domain.ActivityCommentAdded,
+			SubjectID: createdComment.ID,
+			Details:   details,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return createdComment, nil
+}
+
+// Read returns a comment that was not deleted.
+func (cr *commentRepository) Read(ctx context.Context, id uuid.UUID) (*domain.Comment, error) {
+	dbComment, err := cr.store.GetTaskComment(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	return dbToDomainComment(dbComment), nil
+}
+
+// ListByTask returns a page of the comments on a task, oldest first.
+// Deleted comments are included so replies to them keep their thread.
+func (cr *commentRepository) ListByTask(ctx context.Context, taskID uuid.UUID, filter domain.CommentFilter) ([]*domain.Comment, error) {
+	params := db.ListTaskCommentsParams{
+		TaskID:   taskID,
+		PageSize: int32(filter.Limit),
+	}
+	if filter.CursorCreatedAt != nil {
+		params.CursorCreatedAt = pgtype.Timestamptz{Time: *filter.CursorCreatedAt, Valid: true}
+		params.CursorID = pgtype.UUID{Bytes: filter.CursorID, Valid: true}
+	}
+
+	dbComments, err := cr.store.ListTaskComments(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]*domain.Comment, 0, len(dbComments))
+	for _, c := range dbComments {
+		comments = append(comments, dbToDomainComment(c))
+	}
+	return comments, nil
+}
+
+// Delete soft deletes a comment and removes it from the task activity feed.
+func (cr *commentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return cr.store.ExecTx(ctx, func(q *db.Queries) error {
+		rows, err := q.SoftDeleteTaskComment(ctx, id)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrCommentNotFound
+		}
+		return q.SoftDeleteTaskActivity(ctx, pgtype.UUID{Bytes: id, Valid: true})
+	})
+}
+
+func dbToDomainComment(c db.TaskComment) *domain.Comment {
+	comment := &domain.Comment{
+		ID:        c.ID,
+		CreatedAt: c.CreatedAt,
+		DeletedAt: c.DeletedAt.Time,
+		TaskID:    c.TaskID,
+		AuthorID:  c.AuthorID,
+		Body:      c.Body,
+	}
+	if c.ParentID.Valid {
+		comment.ParentID = c.ParentID.Bytes
+	}
+	return comment
+}
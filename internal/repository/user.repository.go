@@ -6,9 +6,13 @@ import (
 	"log"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/vgrigalashvili/veemon/internal/domain"
 	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
 )
 
 var (
@@ -28,58 +32,197 @@ var (
 type (
 	UserRepository interface {
 		Create(ctx context.Context, user domain.User) (*domain.User, error)
+		CreateTx(ctx context.Context, user domain.User, afterCreate func(user *domain.User) error) (*domain.User, error)
 		Read(ctx context.Context, id uuid.UUID) (*domain.User, error)
-		// Update(ctx context.Context, user domain.User) (*domain.User, error)
-		// Delete(ctx context.Context, id uuid.UUID) error
+		ReadByEmail(ctx context.Context, email string) (*domain.User, error)
+		Update(ctx context.Context, id uuid.UUID, update domain.UserUpdate, revokeSessions bool) (*domain.User, error)
+		Delete(ctx context.Context, id uuid.UUID) error
+		List(ctx context.Context, filter domain.UserFilter) ([]*domain.User, error)
 	}
 )
 
 type userRepository struct {
-	queries *db.Queries
+	store *db.Store
 }
 
-func NewUserRepository(q *db.Queries) UserRepository {
-	if q == nil {
-		log.Fatalf("[FATAL] queries cannot be nil")
+func NewUserRepository(store *db.Store) UserRepository {
+	if store == nil {
+		log.Fatalf("[FATAL] store cannot be nil")
 	}
-	return &userRepository{queries: q}
+	return &userRepository{store: store}
 }
 
 func (ur *userRepository) Create(ctx context.Context, user domain.User) (*domain.User, error) {
 	// Use the SQLC generated method instead of recursive call
-	dbUser, err := ur.queries.CreateUser(ctx, domainToDBUser(user))
+	dbUser, err := ur.store.CreateUser(ctx, domainToDBUser(user))
 	if err != nil {
+		if isUniqueViolation(err, "users_email_key") {
+			return nil, ErrEmailAlreadyExists
+		}
+		if isUniqueViolation(err, "users_mobile_key") {
+			return nil, ErrUserAlreadyExists
+		}
 		return nil, err
 	}
 	return dbToDomainUser(dbUser), nil
 }
 
+// CreateTx creates the user and calls afterCreate within the same
+// transaction. The user is not stored if afterCreate returns an error.
+func (ur *userRepository) CreateTx(ctx context.Context, user domain.User, afterCreate func(user *domain.User) error) (*domain.User, error) {
+	var createdUser *domain.User
+	err := ur.store.ExecTx(ctx, func(q *db.Queries) error {
+		dbUser, err := q.CreateUser(ctx, domainToDBUser(user))
+		if err != nil {
+			return err
+		}
+		createdUser = dbToDomainUser(dbUser)
+		return afterCreate(createdUser)
+	})
+	if err != nil {
+		if isUniqueViolation(err, "users_email_key") {
+			return nil, ErrEmailAlreadyExists
+		}
+		if isUniqueViolation(err, "users_mobile_key") {
+			return nil, ErrUserAlreadyExists
+		}
+		return nil, err
+	}
+	return createdUser, nil
+}
+
 func (ur *userRepository) Read(ctx context.Context, id uuid.UUID) (*domain.User, error) {
-	user, err := ur.queries.Read(ctx, id)
+	user, err := ur.store.Read(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return dbToDomainUser(user), nil
+}
+
+func (ur *userRepository) ReadByEmail(ctx context.Context, email string) (*domain.User, error) {
+	user, err := ur.store.GetUserByEmail(ctx, email)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return dbToDomainUser(user), nil
+}
+
+// Update applies a partial update to the user. With revokeSessions, all
+// sessions of the user are revoked within the same transaction.
+func (ur *userRepository) Update(ctx context.Context, id uuid.UUID, update domain.UserUpdate, revokeSessions bool) (*domain.User, error) {
+	var user db.User
+	err := ur.store.ExecTx(ctx, func(q *db.Queries) error {
+		var err error
+		user, err = q.UpdateUser(ctx, db.UpdateUserParams{
+			ID:        id,
+			FirstName: update.FirstName,
+			LastName:  update.LastName,
+			Email:     update.Email,
+			Password:  update.Password,
+			Role:      update.Role,
+			Mobile:    update.Mobile,
+		})
+		if err != nil {
+			return err
+		}
+		if revokeSessions {
+			return q.RevokeUserSessions(ctx, id)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		if isUniqueViolation(err, "users_email_key") {
+			return nil, ErrEmailAlreadyExists
+		}
+		if isUniqueViolation(err, "users_mobile_key") {
+			return nil, ErrUserAlreadyExists
+		}
+		return nil, err
+	}
+	return dbToDomainUser(user), nil
+}
 
+// Delete soft deletes the user and revokes all of its sessions within one transaction.
+func (ur *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return ur.store.ExecTx(ctx, func(q *db.Queries) error {
+		rows, err := q.SoftDeleteUser(ctx, id)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrUserNotFound
+		}
+		return q.RevokeUserSessions(ctx, id)
+	})
+}
+
+// List returns the users matching the filter, newest first.
+func (ur *userRepository) List(ctx context.Context, filter domain.UserFilter) ([]*domain.User, error) {
+	params := db.ListUsersParams{
+		Role:          filter.Role,
+		EmailVerified: filter.EmailVerified,
+		Deleted:       filter.Deleted,
+		PageSize:      int32(filter.Limit),
+	}
+	if filter.Search != "" {
+		search := helper.EscapeLike(filter.Search)
+		params.Search = &search
+	}
+	if filter.CursorCreatedAt != nil {
+		params.CursorCreatedAt = pgtype.Timestamptz{Time: *filter.CursorCreatedAt, Valid: true}
+		params.CursorID = pgtype.UUID{Bytes: filter.CursorID, Valid: true}
+	}
+
+	dbUsers, err := ur.store.ListUsers(ctx, params)
+	if err != nil {
 		return nil, err
 	}
-	return &domain.User{
-		ID:             user.ID,
-		FirstName:      *user.FirstName,
-		LastName:       *user.LastName,
-		Email:          user.Email,
-		Email_verified: user.EmailVerified,
-	}, nil
+
+	users := make([]*domain.User, 0, len(dbUsers))
+	for _, u := range dbUsers {
+		users = append(users, dbToDomainUser(u))
+	}
+	return users, nil
 }
+
+// isUniqueViolation reports whether err is a unique constraint violation of the named constraint.
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == constraint
+}
+
+// isForeignKeyViolation reports whether err is a foreign key violation of the named constraint.
+func isForeignKeyViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503" && pgErr.ConstraintName == constraint
+}
+
 func domainToDBUser(u domain.User) db.CreateUserParams {
-	return db.CreateUserParams{
+	params := db.CreateUserParams{
 		ID:        u.ID,
 		FirstName: &u.FirstName,
 		LastName:  &u.LastName,
 		Email:     u.Email,
 		Password:  u.Password,
+		Role:      u.Role,
+	}
+	if u.Mobile != "" {
+		params.Mobile = &u.Mobile
 	}
+	return params
 }
 
 func dbToDomainUser(u db.User) *domain.User {
-	return &domain.User{
+	user := &domain.User{
 		ID:        u.ID,
 		FirstName: *u.FirstName,
 		LastName:  *u.LastName,
@@ -88,5 +231,12 @@ func dbToDomainUser(u db.User) *domain.User {
 		Role:      u.Role,
 		CreatedAt: u.CreatedAt,
 		UpdatedAt: u.UpdatedAt.Time,
+		DeletedAt: u.DeletedAt.Time,
+
+		Email_verified: u.EmailVerified,
+	}
+	if u.Mobile != nil {
+		user.Mobile = *u.Mobile
 	}
+	return user
 }
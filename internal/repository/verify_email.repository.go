@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
+)
+
+var (
+	ErrInvalidVerifyEmail = errors.New("verification link is invalid, expired or already used")
+)
+
+type (
+	VerifyEmailRepository interface {
+		Verify(ctx context.Context, id int64, secretCode string) (*domain.User, error)
+	}
+)
+
+type verifyEmailRepository struct {
+	store *db.Store
+}
+
+func NewVerifyEmailRepository(store *db.Store) VerifyEmailRepository {
+	if store == nil {
+		log.Fatalf("[FATAL] store cannot be nil")
+	}
+	return &verifyEmailRepository{store: store}
+}
+
+// Verify marks the verification record as used and the user's email as
+// verified within one transaction.
+func (vr *verifyEmailRepository) Verify(ctx context.Context, id int64, secretCode string) (*domain.User, error) {
+	var verifiedUser *domain.User
+	err := vr.store.ExecTx(ctx, func(q *db.Queries) error {
+		verifyEmail, err := q.UseVerifyEmail(ctx, db.UseVerifyEmailParams{
+			ID:             id,
+			SecretCodeHash: helper.HashToken(secretCode),
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrInvalidVerifyEmail
+			}
+			return err
+		}
+
+		// The email must still be the one the link was sent to.
+		user, err := q.VerifyUserEmail(ctx, db.VerifyUserEmailParams{
+			ID:    verifyEmail.UserID,
+			Email: verifyEmail.Email,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrInvalidVerifyEmail
+			}
+			return err
+		}
+
+		verifiedUser = dbToDomainUser(user)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return verifiedUser, nil
+}
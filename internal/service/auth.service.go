@@ -1,13 +1,20 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"log"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
 	"github.com/vgrigalashvili/veemon/internal/domain"
 	"github.com/vgrigalashvili/veemon/internal/dto"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
 	"github.com/vgrigalashvili/veemon/pkg/token"
+	"github.com/vgrigalashvili/veemon/pkg/worker"
 )
 
 var (
@@ -16,17 +23,38 @@ var (
 	ErrInvalidPassword   = errors.New("invalid password")
 	ErrExpiredToken      = errors.New("expired token")
 	ErrInvalidToken      = errors.New("invalid token")
+
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrRefreshTokenReused = errors.New("refresh token reused")
 )
 
 type AuthService struct {
 	Token       token.Maker
+	Denylist    token.Denylist
 	UserService *UserService
+	SessionRepo repository.SessionRepository
+	VerifyRepo  repository.VerifyEmailRepository
+	ResetRepo   repository.PasswordResetRepository
+
+	TaskDistributor worker.TaskDistributor
+
+	AccessTokenDuration  time.Duration
+	RefreshTokenDuration time.Duration
 }
 
-func NewAuthService(token token.Maker, userService *UserService) *AuthService {
+func NewAuthService(token token.Maker, denylist token.Denylist, userService *UserService, sessionRepo repository.SessionRepository, verifyRepo repository.VerifyEmailRepository, resetRepo repository.PasswordResetRepository, taskDistributor worker.TaskDistributor, accessTokenDuration, refreshTokenDuration time.Duration) *AuthService {
 	return &AuthService{
 		Token:       token,
+		Denylist:    denylist,
 		UserService: userService,
+		SessionRepo: sessionRepo,
+		VerifyRepo:  verifyRepo,
+		ResetRepo:   resetRepo,
+
+		TaskDistributor: taskDistributor,
+
+		AccessTokenDuration:  accessTokenDuration,
+		RefreshTokenDuration: refreshTokenDuration,
 	}
 }
 func (as *AuthService) HandleSignUpProcesses(ctx *fiber.Ctx, args dto.AuthSignUp) (string, error) {
@@ -35,10 +63,24 @@ func (as *AuthService) HandleSignUpProcesses(ctx *fiber.Ctx, args dto.AuthSignUp
 	}
 
 	newUser := domain.User{
-		Email: args.Email,
+		Email:    args.Email,
+		Password: args.Password,
 	}
 
-	userID, err := as.UserService.Create(newUser)
+	// The verification email is enqueued inside the user transaction, so a
+	// user is never stored without a verification email on its way.
+	userID, err := as.UserService.CreateWithHook(newUser, func(user *domain.User) error {
+		payload := &worker.PayloadSendVerifyEmail{
+			UserID: user.ID,
+			Email:  user.Email,
+		}
+		opts := []asynq.Option{
+			asynq.MaxRetry(10),
+			asynq.ProcessIn(10 * time.Second),
+			asynq.Queue(worker.QueueCritical),
+		}
+		return as.TaskDistributor.DistributeTaskSendVerifyEmail(ctx.Context(), payload, opts...)
+	})
 	if err != nil {
 		log.Printf("[ERROR] failed to add user: %v", err)
 		return "", err
@@ -47,32 +89,237 @@ func (as *AuthService) HandleSignUpProcesses(ctx *fiber.Ctx, args dto.AuthSignUp
 	return userID, nil
 }
 
-// func (as *AuthService) SignIn(args dto.AuthSignIn) (*token.Payload, error) {
-// 	existedUser, err := as.UserService.GetUserByMobile(args.Mobile)
-// 	if err != nil {
-// 		log.Printf("[ERROR] sign-in failed for mobile %s: user not found or database error: %v", args.Mobile, err)
-// 		return &token.Payload{}, err
-// 	}
+// VerifyEmail checks the secret code of a verification link and marks the
+// user's email as verified.
+func (as *AuthService) VerifyEmail(emailID int64, secretCode string) (*domain.User, error) {
+	user, err := as.VerifyRepo.Verify(context.Background(), emailID, secretCode)
+	if err != nil {
+		log.Printf("[ERROR] failed to verify email %d: %v", emailID, err)
+		return nil, err
+	}
+	return user, nil
+}
 
-// 	if err := helper.CheckPassword(existedUser.Password, args.Password); err != nil {
-// 		log.Printf("[ERROR] invalid password attempt for user ID %s", existedUser.ID)
-// 		return &token.Payload{}, err
-// 	}
+func (as *AuthService) SignIn(args dto.AuthSignIn, userAgent, clientIP string) (*dto.AuthTokens, error) {
+	// The email is not logged; a failed sign-in is often a password typed
+	// into the email field.
+	existedUser, err := as.UserService.GetByEmail(args.Email)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			log.Printf("[WARN] sign-in failed: no user with the given email")
+			return nil, ErrInvalidCredentials
+		}
+		log.Printf("[ERROR] sign-in failed: %v", err)
+		return nil, err
+	}
 
-// 	duration := 24 * time.Hour
+	if err := helper.CheckPassword(existedUser.Password, args.Password); err != nil {
+		log.Printf("[ERROR] invalid password attempt for user ID %s", existedUser.ID)
+		return nil, ErrInvalidCredentials
+	}
 
-// 	tokenPayload, err := as.Token.CreateToken(existedUser.ID, existedUser.Email, existedUser.Role, duration)
-// 	if err != nil {
-// 		log.Printf("[ERROR] failed to create token for user ID %s: %v", existedUser.ID, err)
-// 		return &token.Payload{}, err
-// 	}
+	// A sign-in starts a new session family; every refresh token rotated from
+	// it shares the family ID so reuse can revoke all of them at once.
+	return as.issueTokens(existedUser, uuid.New(), userAgent, clientIP, nil)
+}
 
-// 	return tokenPayload, nil
-// }
+// Refresh exchanges a refresh token for a new access and refresh token pair.
+// The presented refresh token is rotated and cannot be used again; presenting
+// it a second time revokes every session in its family.
+func (as *AuthService) Refresh(args dto.AuthRefresh, userAgent, clientIP string) (*dto.AuthTokens, error) {
+	payload, err := as.Token.VerifyRefreshToken(args.RefreshToken)
+	if err != nil {
+		log.Printf("[ERROR] refresh token verification failed: %v", err)
+		return nil, ErrInvalidToken
+	}
 
-// func (as *AuthService) ForgotPassword() (string, error) {
-// 	return "", nil
-// }
+	session, err := as.SessionRepo.Read(context.Background(), payload.TokenID)
+	if err != nil {
+		log.Printf("[ERROR] failed to read session %s: %v", payload.TokenID, err)
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if !helper.CheckTokenHash(session.RefreshTokenHash, args.RefreshToken) || session.UserID != payload.UserID {
+		log.Printf("[WARN] refresh token does not match session %s", session.ID)
+		return nil, ErrInvalidToken
+	}
+
+	if session.Revoked() {
+		return nil, as.revokeReusedFamily(session)
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		return nil, ErrExpiredToken
+	}
+
+	user, err := as.UserService.GetBID(session.UserID)
+	if err != nil {
+		log.Printf("[ERROR] failed to load user %s for refresh: %v", session.UserID, err)
+		return nil, ErrInvalidToken
+	}
+
+	return as.issueTokens(user, session.FamilyID, userAgent, clientIP, session)
+}
+
+// Logout revokes the access token of the current request. When a refresh
+// token is given, its session family is revoked too so it cannot be refreshed.
+func (as *AuthService) Logout(payload *token.Payload, args dto.AuthLogout) error {
+	ctx := context.Background()
+
+	if args.RefreshToken != "" {
+		refreshPayload, err := as.Token.VerifyRefreshToken(args.RefreshToken)
+		if err != nil {
+			log.Printf("[ERROR] refresh token verification failed: %v", err)
+			return ErrInvalidToken
+		}
+
+		session, err := as.SessionRepo.Read(ctx, refreshPayload.TokenID)
+		if err != nil {
+			log.Printf("[ERROR] failed to read session %s: %v", refreshPayload.TokenID, err)
+			if errors.Is(err, repository.ErrSessionNotFound) {
+				return ErrInvalidToken
+			}
+			return err
+		}
+		if session.UserID != payload.UserID || !helper.CheckTokenHash(session.RefreshTokenHash, args.RefreshToken) {
+			log.Printf("[WARN] user %s tried to log out session %s of another user", payload.UserID, session.ID)
+			return ErrInvalidToken
+		}
+
+		if err := as.SessionRepo.RevokeFamily(ctx, session.FamilyID); err != nil {
+			log.Printf("[ERROR] failed to revoke session family %s: %v", session.FamilyID, err)
+			return err
+		}
+	}
+
+	return as.Denylist.RevokeToken(ctx, payload)
+}
+
+// LogoutEverywhere revokes every access token and session of the user.
+func (as *AuthService) LogoutEverywhere(userID uuid.UUID) error {
+	ctx := context.Background()
+
+	if err := as.SessionRepo.RevokeUser(ctx, userID); err != nil {
+		log.Printf("[ERROR] failed to revoke sessions of user %s: %v", userID, err)
+		return err
+	}
+
+	return as.Denylist.RevokeUser(ctx, userID)
+}
+
+// issueTokens creates an access token and a refresh token backed by a new
+// session in the given family. When previous is set, it is rotated to the new
+// session in the same transaction.
+func (as *AuthService) issueTokens(user *domain.User, familyID uuid.UUID, userAgent, clientIP string, previous *domain.Session) (*dto.AuthTokens, error) {
+	ctx := context.Background()
+
+	refreshPayload, err := as.Token.CreateRefreshToken(user.ID, as.RefreshTokenDuration)
+	if err != nil {
+		log.Printf("[ERROR] failed to create refresh token for user ID %s: %v", user.ID, err)
+		return nil, err
+	}
+
+	newSession := domain.Session{
+		ID:               refreshPayload.TokenID,
+		FamilyID:         familyID,
+		UserID:           user.ID,
+		RefreshTokenHash: helper.HashToken(refreshPayload.Token),
+		UserAgent:        userAgent,
+		ClientIP:         clientIP,
+		ExpiresAt:        refreshPayload.ExpiredAt,
+	}
+
+	var session *domain.Session
+	if previous != nil {
+		session, err = as.SessionRepo.Replace(ctx, previous.ID, newSession)
+		if err != nil {
+			if errors.Is(err, repository.ErrSessionRevoked) {
+				return nil, as.revokeReusedFamily(previous)
+			}
+			log.Printf("[ERROR] failed to rotate session %s: %v", previous.ID, err)
+			return nil, err
+		}
+	} else {
+		session, err = as.SessionRepo.Create(ctx, newSession)
+		if err != nil {
+			log.Printf("[ERROR] failed to create session for user ID %s: %v", user.ID, err)
+			return nil, err
+		}
+	}
+
+	accessPayload, err := as.Token.CreateToken(user.ID, user.Email, user.Role, as.AccessTokenDuration)
+	if err != nil {
+		log.Printf("[ERROR] failed to create token for user ID %s: %v", user.ID, err)
+		return nil, err
+	}
+
+	return &dto.AuthTokens{
+		SessionID:             session.ID,
+		AccessToken:           accessPayload.Token,
+		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
+		RefreshToken:          refreshPayload.Token,
+		RefreshTokenExpiresAt: refreshPayload.ExpiredAt,
+	}, nil
+}
+
+// revokeReusedFamily handles a refresh token that was already rotated by
+// revoking its whole session family, since either the legitimate client or an
+// attacker now holds a stolen token.
+func (as *AuthService) revokeReusedFamily(session *domain.Session) error {
+	log.Printf("[WARN] refresh token reuse detected for session %s, revoking family %s", session.ID, session.FamilyID)
+	if err := as.SessionRepo.RevokeFamily(context.Background(), session.FamilyID); err != nil {
+		log.Printf("[ERROR] failed to revoke session family %s: %v", session.FamilyID, err)
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// ForgotPassword enqueues a password reset email for the user with the given
+// email. Unknown emails are ignored so the endpoint does not reveal which
+// emails are registered.
+func (as *AuthService) ForgotPassword(args dto.AuthForgotPassword) error {
+	user, err := as.UserService.GetByEmail(args.Email)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	payload := &worker.PayloadSendResetPassword{
+		UserID: user.ID,
+		Email:  user.Email,
+	}
+	opts := []asynq.Option{
+		asynq.MaxRetry(10),
+		asynq.Queue(worker.QueueCritical),
+	}
+	if err := as.TaskDistributor.DistributeTaskSendResetPassword(context.Background(), payload, opts...); err != nil {
+		log.Printf("[ERROR] failed to enqueue password reset for user ID %s: %v", user.ID, err)
+		return err
+	}
+	return nil
+}
+
+// ResetPassword stores a new password for the owner of a reset token and
+// signs the user out everywhere.
+func (as *AuthService) ResetPassword(args dto.AuthResetPassword) error {
+	hashedPassword, err := as.UserService.HashNewPassword(args.Password)
+	if err != nil {
+		return err
+	}
+
+	userID, err := as.ResetRepo.Reset(context.Background(), args.Token, hashedPassword)
+	if err != nil {
+		log.Printf("[ERROR] failed to reset password: %v", err)
+		return err
+	}
+
+	return as.LogoutEverywhere(userID)
+}
 
 // func (as *AuthService) CreateVerifyEmail(ctx context.Context, email, secretCode string) (string, error) {
 // 	return "", nil
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/dto"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/pkg/token"
+)
+
+// fakeSessionRepo keeps sessions in memory and rotates them like the
+// repository does.
+type fakeSessionRepo struct {
+	sessions map[uuid.UUID]*domain.Session
+}
+
+func (r *fakeSessionRepo) Create(ctx context.Context, session domain.Session) (*domain.Session, error) {
+	session.CreatedAt = time.Now()
+	r.sessions[session.ID] = &session
+	copied := session
+	return &copied, nil
+}
+
+func (r *fakeSessionRepo) Read(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, repository.ErrSessionNotFound
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (r *fakeSessionRepo) Replace(ctx context.Context, previousID uuid.UUID, session domain.Session) (*domain.Session, error) {
+	previous, ok := r.sessions[previousID]
+	if !ok {
+		return nil, repository.ErrSessionNotFound
+	}
+	if previous.Revoked() {
+		return nil, repository.ErrSessionRevoked
+	}
+	previous.RevokedAt = time.Now()
+	previous.ReplacedBy = session.ID
+	return r.Create(ctx, session)
+}
+
+func (r *fakeSessionRepo) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	for _, session := range r.sessions {
+		if session.FamilyID == familyID && !session.Revoked() {
+			session.RevokedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (r *fakeSessionRepo) RevokeUser(ctx context.Context, userID uuid.UUID) error {
+	for _, session := range r.sessions {
+		if session.UserID == userID && !session.Revoked() {
+			session.RevokedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+// revoked reports whether the session with the given ID is revoked.
+func (r *fakeSessionRepo) revoked(t *testing.T, id uuid.UUID) bool {
+	t.Helper()
+	session, ok := r.sessions[id]
+	if !ok {
+		t.Fatalf("session %s not found", id)
+	}
+	return session.Revoked()
+}
+
+// newTestAuthService returns an AuthService backed by in-memory fakes and a
+// signed-in user.
+func newTestAuthService(t *testing.T) (*AuthService, *fakeSessionRepo, *fakeDenylist, *domain.User) {
+	t.Helper()
+	maker, err := token.NewPasetoMaker("12345678901234567890123456789012")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := &domain.User{ID: uuid.New(), Email: "nino@example.com", Role: domain.RoleUser}
+	sessions := &fakeSessionRepo{sessions: map[uuid.UUID]*domain.Session{}}
+	denylist := &fakeDenylist{}
+	as := &AuthService{
+		Token:       maker,
+		Denylist:    denylist,
+		UserService: &UserService{UserRepo: &fakeUserRepo{users: map[uuid.UUID]*domain.User{user.ID: user}}},
+		SessionRepo: sessions,
+
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenDuration: 24 * time.Hour,
+	}
+	return as, sessions, denylist, user
+}
+
+// signIn issues the first tokens of a new session family for the user.
+func signIn(t *testing.T, as *AuthService, user *domain.User) *dto.AuthTokens {
+	t.Helper()
+	tokens, err := as.issueTokens(user, uuid.New(), "test", "127.0.0.1", nil)
+	if err != nil {
+		t.Fatalf("issueTokens() error = %v", err)
+	}
+	return tokens
+}
+
+func TestRefreshRotatesSession(t *testing.T) {
+	as, sessions, _, user := newTestAuthService(t)
+	first := signIn(t, as, user)
+
+	second, err := as.Refresh(dto.AuthRefresh{RefreshToken: first.RefreshToken}, "test", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if second.SessionID == first.SessionID || second.RefreshToken == first.RefreshToken {
+		t.Fatal("Refresh() did not issue a new session")
+	}
+
+	previous := sessions.sessions[first.SessionID]
+	if !previous.Revoked() || previous.ReplacedBy != second.SessionID {
+		t.Errorf("previous session revoked = %v, replaced by %s, want revoked and replaced by %s", previous.Revoked(), previous.ReplacedBy, second.SessionID)
+	}
+	if current := sessions.sessions[second.SessionID]; current.Revoked() || current.FamilyID != previous.FamilyID {
+		t.Errorf("new session revoked = %v in family %s, want active in family %s", current.Revoked(), current.FamilyID, previous.FamilyID)
+	}
+
+	if _, err := as.Refresh(dto.AuthRefresh{RefreshToken: second.RefreshToken}, "test", "127.0.0.1"); err != nil {
+		t.Errorf("Refresh() with the rotated token error = %v", err)
+	}
+}
+
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	as, sessions, _, user := newTestAuthService(t)
+	first := signIn(t, as, user)
+	other := signIn(t, as, user)
+
+	second, err := as.Refresh(dto.AuthRefresh{RefreshToken: first.RefreshToken}, "test", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	_, err = as.Refresh(dto.AuthRefresh{RefreshToken: first.RefreshToken}, "test", "127.0.0.1")
+	if !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("Refresh() with a rotated token error = %v, want %v", err, ErrRefreshTokenReused)
+	}
+	if !sessions.revoked(t, second.SessionID) {
+		t.Error("session rotated from the reused token is still active")
+	}
+	if sessions.revoked(t, other.SessionID) {
+		t.Error("session of another family was revoked")
+	}
+
+	_, err = as.Refresh(dto.AuthRefresh{RefreshToken: second.RefreshToken}, "test", "127.0.0.1")
+	if !errors.Is(err, ErrRefreshTokenReused) {
+		t.Errorf("Refresh() after reuse error = %v, want %v", err, ErrRefreshTokenReused)
+	}
+}
+
+func TestRefreshRejectsInvalidTokens(t *testing.T) {
+	as, sessions, _, user := newTestAuthService(t)
+	tokens := signIn(t, as, user)
+
+	// A session whose stored hash does not belong to the presented token.
+	sessions.sessions[tokens.SessionID].RefreshTokenHash = "other"
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"malformed", "not-a-token"},
+		{"unknown session", signInToken(t, as, user, sessions)},
+		{"hash mismatch", tokens.RefreshToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := as.Refresh(dto.AuthRefresh{RefreshToken: tt.token}, "test", "127.0.0.1")
+			if !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Refresh() error = %v, want %v", err, ErrInvalidToken)
+			}
+		})
+	}
+}
+
+// signInToken returns a refresh token whose session is no longer stored.
+func signInToken(t *testing.T, as *AuthService, user *domain.User, sessions *fakeSessionRepo) string {
+	t.Helper()
+	tokens := signIn(t, as, user)
+	delete(sessions.sessions, tokens.SessionID)
+	return tokens.RefreshToken
+}
+
+func TestLogout(t *testing.T) {
+	as, sessions, denylist, user := newTestAuthService(t)
+	first := signIn(t, as, user)
+	second, err := as.Refresh(dto.AuthRefresh{RefreshToken: first.RefreshToken}, "test", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	other := signIn(t, as, user)
+
+	access, err := as.Token.VerifyToken(second.AccessToken)
+	if err != nil {
+		t.Fatalf("VerifyToken() error = %v", err)
+	}
+
+	if err := as.Logout(access, dto.AuthLogout{RefreshToken: second.RefreshToken}); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+
+	if revoked, _ := denylist.IsRevoked(context.Background(), access); !revoked {
+		t.Error("access token is not revoked")
+	}
+	if !sessions.revoked(t, second.SessionID) {
+		t.Error("session is still active")
+	}
+	if sessions.revoked(t, other.SessionID) {
+		t.Error("session of another sign-in was revoked")
+	}
+	if _, err := as.Refresh(dto.AuthRefresh{RefreshToken: second.RefreshToken}, "test", "127.0.0.1"); err == nil {
+		t.Error("Refresh() after logout succeeded")
+	}
+}
+
+func TestLogoutRejectsSessionOfAnotherUser(t *testing.T) {
+	as, sessions, denylist, user := newTestAuthService(t)
+	victim := &domain.User{ID: uuid.New(), Email: "eka@example.com", Role: domain.RoleUser}
+	as.UserService.UserRepo.(*fakeUserRepo).users[victim.ID] = victim
+
+	own := signIn(t, as, user)
+	theirs := signIn(t, as, victim)
+	access, err := as.Token.VerifyToken(own.AccessToken)
+	if err != nil {
+		t.Fatalf("VerifyToken() error = %v", err)
+	}
+
+	err = as.Logout(access, dto.AuthLogout{RefreshToken: theirs.RefreshToken})
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Logout() error = %v, want %v", err, ErrInvalidToken)
+	}
+	if sessions.revoked(t, theirs.SessionID) {
+		t.Error("session of another user was revoked")
+	}
+	if len(denylist.tokens) != 0 {
+		t.Errorf("revoked tokens %v, want none", denylist.tokens)
+	}
+}
+
+func TestLogoutEverywhere(t *testing.T) {
+	as, sessions, denylist, user := newTestAuthService(t)
+	other := &domain.User{ID: uuid.New(), Email: "eka@example.com", Role: domain.RoleUser}
+
+	first := signIn(t, as, user)
+	second := signIn(t, as, user)
+	theirs := signIn(t, as, other)
+
+	if err := as.LogoutEverywhere(user.ID); err != nil {
+		t.Fatalf("LogoutEverywhere() error = %v", err)
+	}
+
+	if !sessions.revoked(t, first.SessionID) || !sessions.revoked(t, second.SessionID) {
+		t.Error("a session of the user is still active")
+	}
+	if sessions.revoked(t, theirs.SessionID) {
+		t.Error("session of another user was revoked")
+	}
+	if len(denylist.users) != 1 || denylist.users[0] != user.ID {
+		t.Errorf("revoked users %v, want [%s]", denylist.users, user.ID)
+	}
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/pkg/money"
+)
+
+var (
+	ErrTaskNotOpen  = errors.New("task is not open for bids")
+	ErrOwnTaskBid   = errors.New("task owner cannot bid on their own task")
+	ErrBidForbidden = errors.New("not allowed to change this bid")
+)
+
+type BidService struct {
+	TaskService *TaskService
+	BidRepo     repository.BidRepository
+}
+
+func NewBidService(taskService *TaskService, bidRepo repository.BidRepository) *BidService {
+	if bidRepo == nil {
+		log.Fatalf("[FATAL] BidRepository cannot be nil")
+	}
+	return &BidService{
+		TaskService: taskService,
+		BidRepo:     bidRepo,
+	}
+}
+
+// Create places a bid of the actor on an open task. Bids are in the currency
+// of the task budget, and a user has at most one pending bid per task.
+func (bs *BidService) Create(actor domain.Actor, taskID uuid.UUID, amount money.Money, message string) (*domain.Bid, error) {
+	task, err := bs.TaskService.Get(actor, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != domain.TaskStatusOpen {
+		return nil, ErrTaskNotOpen
+	}
+	if task.OwnerID == actor.ID {
+		return nil, ErrOwnTaskBid
+	}
+
+	if err := amount.Validate(); err != nil {
+		return nil, err
+	}
+	if amount.Currency != task.Budget.Currency {
+		return nil, money.ErrCurrencyMismatch
+	}
+
+	bid, err := bs.BidRepo.Create(context.Background(), domain.Bid{
+		ID:       uuid.New(),
+		TaskID:   task.ID,
+		BidderID: actor.ID,
+		Amount:   amount,
+		Message:  message,
+	})
+	if err != nil {
+		log.Printf("[ERROR] failed to create bid of user %s on task %s: %v", actor.ID, task.ID, err)
+		return nil, err
+	}
+	return bid, nil
+}
+
+// List returns the bids on a task the actor may see, oldest first. The owner
+// and staff see every bid; other users only see their own.
+func (bs *BidService) List(actor domain.Actor, taskID uuid.UUID) ([]*domain.Bid, error) {
+	task, err := bs.TaskService.Get(actor, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	bidderID := actor.ID
+	if task.Parties(actor)&(domain.TaskPartyOwner|domain.TaskPartyStaff) != 0 {
+		bidderID = uuid.Nil
+	}
+
+	bids, err := bs.BidRepo.ListByTask(context.Background(), task.ID, bidderID)
+	if err != nil {
+		log.Printf("[ERROR] failed to list bids on task %s: %v", task.ID, err)
+		return nil, err
+	}
+	return bids, nil
+}
+
+// Withdraw takes back a pending bid. Only the bidder may withdraw it.
+func (bs *BidService) Withdraw(actor domain.Actor, taskID, bidID uuid.UUID) (*domain.Bid, error) {
+	bid, err := bs.readTaskBid(taskID, bidID)
+	if err != nil {
+		return nil, err
+	}
+	if bid.BidderID != actor.ID {
+		return nil, ErrBidForbidden
+	}
+
+	withdrawnBid, err := bs.BidRepo.Withdraw(context.Background(), bid.ID)
+	if err != nil {
+		log.Printf("[ERROR] failed to withdraw bid %s: %v", bid.ID, err)
+		return nil, err
+	}
+	return withdrawnBid, nil
+}
+
+// Accept accepts a pending bid on an open task. The bidder becomes the task
+// assignee and every other pending bid on the task is rejected. Only the task
+// owner may accept a bid; staff cannot pick a contractor on the owner's behalf.
+func (bs *BidService) Accept(actor domain.Actor, taskID, bidID uuid.UUID) (*domain.Bid, *domain.Task, error) {
+	task, err := bs.TaskService.Get(actor, taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	bid, err := bs.readTaskBid(task.ID, bidID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if task.Parties(actor)&domain.TaskPartyOwner == 0 {
+		return nil, nil, ErrTaskForbidden
+	}
+	if task.Status != domain.TaskStatusOpen {
+		return nil, nil, ErrTaskNotOpen
+	}
+	if bid.Status != domain.BidStatusPending {
+		return nil, nil, repository.ErrBidNotPending
+	}
+
+	acceptedBid, assignedTask, err := bs.BidRepo.Accept(context.Background(), bid.ID, domain.TaskTransition{
+		TaskID:     task.ID,
+		From:       domain.TaskStatusOpen,
+		To:         domain.TaskStatusAssigned,
+		ActorID:    actor.ID,
+		AssigneeID: bid.BidderID,
+		Reason:     "bid accepted",
+	})
+	if err != nil {
+		log.Printf("[ERROR] failed to accept bid %s on task %s: %v", bid.ID, task.ID, err)
+		return nil, nil, err
+	}
+	return acceptedBid, assignedTask, nil
+}
+
+// readTaskBid returns a bid, reporting bids on other tasks as not found.
+func (bs *BidService) readTaskBid(taskID, bidID uuid.UUID) (*domain.Bid, error) {
+	bid, err := bs.BidRepo.Read(context.Background(), bidID)
+	if err != nil {
+		return nil, err
+	}
+	if bid.TaskID != taskID {
+		return nil, repository.ErrBidNotFound
+	}
+	return bid, nil
+}
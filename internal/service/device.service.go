@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+)
+
+const (
+	defaultDevicePageSize = 50
+	maxDevicePageSize     = 500
+
+	defaultMetricSpan    = 24 * time.Hour
+	defaultMetricBuckets = 200  // Buckets per series when the query has no step.
+	maxMetricBuckets     = 1000 // More buckets per series than a chart can show.
+	maxMetricNameLength  = 64
+	maxClockSkew         = 5 * time.Minute // How far ahead of the server a lift's clock may run.
+	maxMetricAge         = 24 * time.Hour  // How far back metrics may be dated; their metric partition always exists.
+)
+
+var (
+	ErrInvalidDeviceID  = errors.New("device ID must be 1 to 64 letters, digits, dashes or underscores")
+	ErrInvalidMetrics   = errors.New("invalid metrics report")
+	ErrInvalidTimeRange = errors.New("from must be before to")
+	ErrInvalidStep      = errors.New("step must be at least one second")
+	ErrTooManyBuckets   = errors.New("time range holds too many steps, use a larger step")
+)
+
+// deviceIDPattern keeps device IDs usable as a single MQTT topic level.
+var deviceIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+type DeviceService struct {
+	DeviceRepo        repository.DeviceRepository
+	MetricRepo        repository.MetricRepository
+	IncidentService   *IncidentService
+	HeartbeatInterval time.Duration // How often lifts publish a heartbeat.
+	MissedLimit       int           // Missed heartbeats after which a lift is offline.
+}
+
+func NewDeviceService(deviceRepo repository.DeviceRepository, metricRepo repository.MetricRepository, incidentService *IncidentService, heartbeatInterval time.Duration, missedLimit int) *DeviceService {
+	if deviceRepo == nil {
+		log.Fatalf("[FATAL] DeviceRepository cannot be nil")
+	}
+	if metricRepo == nil {
+		log.Fatalf("[FATAL] MetricRepository cannot be nil")
+	}
+	if incidentService == nil {
+		log.Fatalf("[FATAL] IncidentService cannot be nil")
+	}
+	if heartbeatInterval <= 0 || missedLimit <= 0 {
+		log.Fatalf("[FATAL] heartbeat interval and missed limit must be positive")
+	}
+	return &DeviceService{
+		DeviceRepo:        deviceRepo,
+		MetricRepo:        metricRepo,
+		IncidentService:   incidentService,
+		HeartbeatInterval: heartbeatInterval,
+		MissedLimit:       missedLimit,
+	}
+}
+
+// Create registers a device under the ID it publishes its MQTT messages with.
+func (ds *DeviceService) Create(device domain.Device) (*domain.Device, error) {
+	if !deviceIDPattern.MatchString(device.ID) {
+		return nil, ErrInvalidDeviceID
+	}
+
+	createdDevice, err := ds.DeviceRepo.Create(context.Background(), device)
+	if err != nil {
+		log.Printf("[ERROR] failed to register device %s: %v", device.ID, err)
+		return nil, err
+	}
+	return createdDevice, nil
+}
+
+func (ds *DeviceService) Get(id string) (*domain.Device, error) {
+	return ds.DeviceRepo.Read(context.Background(), id)
+}
+
+// List returns a page of the registered devices and the cursor of the next
+// page, which is empty when there are no more devices.
+func (ds *DeviceService) List(filter domain.DeviceFilter) ([]*domain.Device, string, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultDevicePageSize
+	}
+	if filter.Limit > maxDevicePageSize {
+		filter.Limit = maxDevicePageSize
+	}
+	pageSize := filter.Limit
+
+	// One extra row tells whether another page follows.
+	filter.Limit++
+	devices, err := ds.DeviceRepo.List(context.Background(), filter)
+	if err != nil {
+		log.Printf("[ERROR] failed to list devices: %v", err)
+		return nil, "", err
+	}
+
+	if len(devices) <= pageSize {
+		return devices, "", nil
+	}
+	devices = devices[:pageSize]
+	return devices, devices[pageSize-1].ID, nil
+}
+
+func (ds *DeviceService) Update(id string, update domain.DeviceUpdate) (*domain.Device, error) {
+	device, err := ds.DeviceRepo.Update(context.Background(), id, update)
+	if err != nil {
+		log.Printf("[ERROR] failed to update device %s: %v", id, err)
+		return nil, err
+	}
+	return device, nil
+}
+
+func (ds *DeviceService) Delete(id string) error {
+	if err := ds.DeviceRepo.Delete(context.Background(), id); err != nil {
+		log.Printf("[ERROR] failed to delete device %s: %v", id, err)
+		return err
+	}
+	return nil
+}
+
+// AcceptHeartbeat records a heartbeat received over MQTT and marks the device
+// online. Liveness does not depend on the lift's clock or its metrics: invalid
+// metrics are dropped, metrics with a timestamp out of range are stored at the
+// time the heartbeat arrived, and a failure to store them is logged without
+// rejecting the heartbeat. Heartbeats of devices missing from the registry are
+// rejected with ErrDeviceNotFound.
+func (ds *DeviceService) AcceptHeartbeat(deviceID string, heartbeat domain.Heartbeat) error {
+	if err := validateMetrics(heartbeat.Metrics); err != nil {
+		log.Printf("[WARN] dropping the metrics of a heartbeat of device %s: %v", deviceID, err)
+		heartbeat.Metrics = nil
+	}
+
+	_, previousState, err := ds.DeviceRepo.RecordHeartbeat(context.Background(), deviceID, heartbeat)
+	if err != nil {
+		return err
+	}
+	if previousState != domain.DeviceStateOnline {
+		log.Printf("[INFO] device %s is online, was %s", deviceID, previousState)
+	}
+	if len(heartbeat.Metrics) == 0 {
+		return nil
+	}
+
+	recordedAt, err := metricsTime(heartbeat.Timestamp)
+	if err != nil {
+		log.Printf("[WARN] device %s: %v, storing heartbeat metrics at the time received", deviceID, err)
+		recordedAt = time.Now()
+	}
+	if err := ds.MetricRepo.Record(context.Background(), deviceID, recordedAt, heartbeat.Metrics); err != nil {
+		log.Printf("[ERROR] failed to record heartbeat metrics of device %s: %v", deviceID, err)
+	}
+	return nil
+}
+
+// AcceptMetrics records a metrics report received over MQTT. Reports of
+// devices missing from the registry are rejected with ErrDeviceNotFound,
+// invalid ones with ErrInvalidMetrics.
+func (ds *DeviceService) AcceptMetrics(deviceID string, report domain.MetricsReport) error {
+	if err := validateMetrics(report.Metrics); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMetrics, err)
+	}
+	recordedAt, err := metricsTime(report.Timestamp)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMetrics, err)
+	}
+
+	if _, err := ds.DeviceRepo.Read(context.Background(), deviceID); err != nil {
+		return err
+	}
+
+	if err := ds.MetricRepo.Record(context.Background(), deviceID, recordedAt, report.Metrics); err != nil {
+		log.Printf("[ERROR] failed to record metrics of device %s: %v", deviceID, err)
+		return err
+	}
+	return nil
+}
+
+// Metrics returns the metrics of a registered device downsampled per step.
+// The time range defaults to the day before To, and To to now. Without a step
+// the range is split into about defaultMetricBuckets buckets.
+func (ds *DeviceService) Metrics(id string, query domain.MetricQuery) ([]domain.MetricSeries, domain.MetricQuery, error) {
+	if query.To.IsZero() {
+		query.To = time.Now()
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-defaultMetricSpan)
+	}
+	if !query.From.Before(query.To) {
+		return nil, query, ErrInvalidTimeRange
+	}
+
+	span := query.To.Sub(query.From)
+	if query.Step == 0 {
+		query.Step = max((span / defaultMetricBuckets).Truncate(time.Second), time.Second)
+	}
+	if query.Step < time.Second {
+		return nil, query, ErrInvalidStep
+	}
+	if (span+query.Step-1)/query.Step > maxMetricBuckets {
+		return nil, query, ErrTooManyBuckets
+	}
+
+	if _, err := ds.DeviceRepo.Read(context.Background(), id); err != nil {
+		return nil, query, err
+	}
+
+	series, err := ds.MetricRepo.Series(context.Background(), id, query)
+	if err != nil {
+		log.Printf("[ERROR] failed to query metrics of device %s: %v", id, err)
+		return nil, query, err
+	}
+	return series, query, nil
+}
+
+// Status returns the live state of a registered device.
+func (ds *DeviceService) Status(id string) (*domain.DeviceStatus, error) {
+	if _, err := ds.DeviceRepo.Read(context.Background(), id); err != nil {
+		return nil, err
+	}
+
+	status, err := ds.DeviceRepo.Status(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrDeviceNeverSeen) {
+			return &domain.DeviceStatus{DeviceID: id, State: domain.DeviceStateUnknown}, nil
+		}
+		return nil, err
+	}
+
+	// The watcher only runs once per interval, so a silent lift can still be
+	// stored as online for a while after it should count as offline.
+	if status.State == domain.DeviceStateOnline && time.Since(status.LastSeenAt) > ds.offlineAfter() {
+		status.State = domain.DeviceStateOffline
+		status.StateChangedAt = status.LastSeenAt.Add(ds.offlineAfter())
+	}
+	return status, nil
+}
+
+// WatchOffline marks lifts offline once they missed MissedLimit heartbeats in
+// a row, checking once per heartbeat interval until ctx is done, and opens an
+// offline incident for each. Lifts come back online with their next heartbeat;
+// their incident stays open until someone resolves it.
+func (ds *DeviceService) WatchOffline(ctx context.Context) {
+	ticker := time.NewTicker(ds.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deviceIDs, err := ds.DeviceRepo.MarkOffline(ctx, time.Now().Add(-ds.offlineAfter()))
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("[ERROR] failed to mark silent devices offline: %v", err)
+				}
+				continue
+			}
+			for _, deviceID := range deviceIDs {
+				log.Printf("[WARN] device %s is offline, no heartbeat for %s", deviceID, ds.offlineAfter())
+				// Other failures are logged by the incident service; the
+				// next lift going offline is still reported.
+				err := ds.IncidentService.ReportOffline(deviceID, ds.offlineAfter())
+				if errors.Is(err, repository.ErrDeviceNotFound) {
+					log.Printf("[ERROR] failed to report device %s offline: %v", deviceID, err)
+				}
+			}
+		}
+	}
+}
+
+// validateMetrics checks the names and values of the metrics a lift reported.
+func validateMetrics(metrics map[string]float64) error {
+	for name, value := range metrics {
+		if name == "" || len(name) > maxMetricNameLength {
+			return fmt.Errorf("metric name %q must be 1 to %d characters", name, maxMetricNameLength)
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return fmt.Errorf("metric %s is not a finite number", name)
+		}
+	}
+	return nil
+}
+
+// metricsTime returns the time metrics reported at timestamp were taken.
+// Metrics older than maxMetricAge are rejected.
+func metricsTime(timestamp time.Time) (time.Time, error) {
+	recordedAt, err := reportedTime(timestamp)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if recordedAt.Before(time.Now().Add(-maxMetricAge)) {
+		return time.Time{}, fmt.Errorf("timestamp %s is more than %s old", recordedAt.Format(time.RFC3339), maxMetricAge)
+	}
+	return recordedAt, nil
+}
+
+// reportedTime returns the time a lift reported something at, which is now
+// when the lift sent no timestamp. Timestamps further ahead than the clock of
+// a lift may run are rejected.
+func reportedTime(timestamp time.Time) (time.Time, error) {
+	now := time.Now()
+	if timestamp.IsZero() {
+		return now, nil
+	}
+	if timestamp.After(now.Add(maxClockSkew)) {
+		return time.Time{}, fmt.Errorf("timestamp %s is in the future", timestamp.Format(time.RFC3339))
+	}
+	return timestamp, nil
+}
+
+// offlineAfter is how long a lift may stay silent before it counts as offline.
+func (ds *DeviceService) offlineAfter() time.Duration {
+	return ds.HeartbeatInterval * time.Duration(ds.MissedLimit)
+}
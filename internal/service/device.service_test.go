@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+)
+
+// fakeDeviceRepo records heartbeats of the devices in known.
+type fakeDeviceRepo struct {
+	repository.DeviceRepository
+	known      map[string]bool
+	heartbeats []domain.Heartbeat
+}
+
+func (r *fakeDeviceRepo) RecordHeartbeat(ctx context.Context, id string, heartbeat domain.Heartbeat) (*domain.DeviceStatus, string, error) {
+	if !r.known[id] {
+		return nil, "", repository.ErrDeviceNotFound
+	}
+	r.heartbeats = append(r.heartbeats, heartbeat)
+	return &domain.DeviceStatus{DeviceID: id, State: domain.DeviceStateOnline, LastSeenAt: time.Now()}, domain.DeviceStateOffline, nil
+}
+
+type recordedMetrics struct {
+	recordedAt time.Time
+	metrics    map[string]float64
+}
+
+// fakeMetricRepo records metrics, or fails with err when set.
+type fakeMetricRepo struct {
+	repository.MetricRepository
+	err      error
+	recorded []recordedMetrics
+}
+
+func (r *fakeMetricRepo) Record(ctx context.Context, deviceID string, recordedAt time.Time, metrics map[string]float64) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.recorded = append(r.recorded, recordedMetrics{recordedAt: recordedAt, metrics: metrics})
+	return nil
+}
+
+func TestAcceptHeartbeat(t *testing.T) {
+	now := time.Now()
+	metrics := map[string]float64{"load_kg": 320}
+
+	tests := []struct {
+		name          string
+		heartbeat     domain.Heartbeat
+		metricErr     error
+		wantMetrics   bool
+		wantRestamped bool
+	}{
+		{"current clock", domain.Heartbeat{Timestamp: now.Add(-time.Second), Metrics: metrics}, nil, true, false},
+		{"no timestamp", domain.Heartbeat{Metrics: metrics}, nil, true, true},
+		{"no metrics", domain.Heartbeat{Timestamp: now}, nil, false, false},
+		{"stale clock still counts as alive", domain.Heartbeat{Timestamp: now.AddDate(-5, 0, 0), Metrics: metrics}, nil, true, true},
+		{"clock ahead", domain.Heartbeat{Timestamp: now.Add(time.Hour), Metrics: metrics}, nil, true, true},
+		{"stale clock without metrics", domain.Heartbeat{Timestamp: now.AddDate(-5, 0, 0)}, nil, false, false},
+		{"invalid metrics", domain.Heartbeat{Timestamp: now, Metrics: map[string]float64{"load_kg": math.NaN()}}, nil, false, false},
+		{"metric store fails", domain.Heartbeat{Timestamp: now, Metrics: metrics}, errors.New("no partition"), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			devices := &fakeDeviceRepo{known: map[string]bool{"L-17": true}}
+			metricRepo := &fakeMetricRepo{err: tt.metricErr}
+			ds := &DeviceService{DeviceRepo: devices, MetricRepo: metricRepo, HeartbeatInterval: 30 * time.Second, MissedLimit: 3}
+
+			before := time.Now()
+			if err := ds.AcceptHeartbeat("L-17", tt.heartbeat); err != nil {
+				t.Fatalf("AcceptHeartbeat() error = %v", err)
+			}
+
+			// The heartbeat counts as alive whatever the lift's clock says.
+			if len(devices.heartbeats) != 1 {
+				t.Fatalf("recorded %d heartbeats, want 1", len(devices.heartbeats))
+			}
+			if got := devices.heartbeats[0].Metrics; !tt.wantMetrics && tt.metricErr == nil && len(got) != 0 {
+				t.Errorf("heartbeat recorded with metrics %v, want none", got)
+			}
+
+			if !tt.wantMetrics {
+				if len(metricRepo.recorded) != 0 {
+					t.Errorf("recorded metrics %v, want none", metricRepo.recorded)
+				}
+				return
+			}
+			if len(metricRepo.recorded) != 1 {
+				t.Fatalf("recorded metrics %d times, want once", len(metricRepo.recorded))
+			}
+			recordedAt := metricRepo.recorded[0].recordedAt
+			if tt.wantRestamped {
+				if recordedAt.Before(before) || recordedAt.After(time.Now()) {
+					t.Errorf("metrics recorded at %s, want the time received", recordedAt)
+				}
+			} else if !recordedAt.Equal(tt.heartbeat.Timestamp) {
+				t.Errorf("metrics recorded at %s, want %s", recordedAt, tt.heartbeat.Timestamp)
+			}
+		})
+	}
+}
+
+func TestAcceptHeartbeatUnknownDevice(t *testing.T) {
+	ds := &DeviceService{DeviceRepo: &fakeDeviceRepo{}, MetricRepo: &fakeMetricRepo{}}
+
+	err := ds.AcceptHeartbeat("L-404", domain.Heartbeat{Timestamp: time.Now()})
+	if !errors.Is(err, repository.ErrDeviceNotFound) {
+		t.Errorf("AcceptHeartbeat() error = %v, want %v", err, repository.ErrDeviceNotFound)
+	}
+}
+
+func TestAcceptMetricsRejectsOutOfRangeTimestamps(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		wantErr   error
+	}{
+		{"current", time.Now().Add(-time.Minute), nil},
+		{"older than the retained partitions", time.Now().Add(-maxMetricAge - time.Minute), ErrInvalidMetrics},
+		{"in the future", time.Now().Add(time.Hour), ErrInvalidMetrics},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			devices := &fakeDeviceRepo{known: map[string]bool{"L-17": true}}
+			ds := &DeviceService{DeviceRepo: readableDevices{devices}, MetricRepo: &fakeMetricRepo{}}
+
+			err := ds.AcceptMetrics("L-17", domain.MetricsReport{Timestamp: tt.timestamp, Metrics: map[string]float64{"load_kg": 1}})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AcceptMetrics() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// readableDevices lets the devices of a fakeDeviceRepo be read.
+type readableDevices struct {
+	*fakeDeviceRepo
+}
+
+func (r readableDevices) Read(ctx context.Context, id string) (*domain.Device, error) {
+	if !r.known[id] {
+		return nil, repository.ErrDeviceNotFound
+	}
+	return &domain.Device{ID: id}, nil
+}
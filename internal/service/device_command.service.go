@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
+	"github.com/vgrigalashvili/veemon/pkg/mqtt"
+)
+
+const (
+	maxCommandTimeout           = time.Hour
+	commandPublishTimeout       = 10 * time.Second
+	commandTimeoutCheckInterval = 5 * time.Second // Bounds how late a command times out.
+)
+
+var (
+	ErrUnknownCommand        = errors.New("unknown device command")
+	ErrInvalidCommandArgs    = errors.New("invalid device command arguments")
+	ErrInvalidCommandTimeout = errors.New("command timeout must be positive and at most one hour")
+	ErrCommandNotDelivered   = errors.New("device command could not be delivered to the broker")
+)
+
+type DeviceCommandService struct {
+	DeviceRepo     repository.DeviceRepository
+	CommandRepo    repository.DeviceCommandRepository
+	MQTT           mqtt.Client
+	DefaultTimeout time.Duration // How long lifts get to complete a command by default.
+}
+
+func NewDeviceCommandService(deviceRepo repository.DeviceRepository, commandRepo repository.DeviceCommandRepository, mqttClient mqtt.Client, defaultTimeout time.Duration) *DeviceCommandService {
+	if deviceRepo == nil {
+		log.Fatalf("[FATAL] DeviceRepository cannot be nil")
+	}
+	if commandRepo == nil {
+		log.Fatalf("[FATAL] DeviceCommandRepository cannot be nil")
+	}
+	if mqttClient == nil {
+		log.Fatalf("[FATAL] MQTT client cannot be nil")
+	}
+	if defaultTimeout <= 0 || defaultTimeout > maxCommandTimeout {
+		log.Fatalf("[FATAL] default command timeout must be positive and at most %s", maxCommandTimeout)
+	}
+	return &DeviceCommandService{
+		DeviceRepo:     deviceRepo,
+		CommandRepo:    commandRepo,
+		MQTT:           mqttClient,
+		DefaultTimeout: defaultTimeout,
+	}
+}
+
+// Send stores a command for a registered device and publishes it on
+// Lift/<device ID>/commands/<name> with its ID as the correlation ID. A zero
+// timeout takes the default timeout. A command the broker did not take is
+// marked failed and reported with ErrCommandNotDelivered.
+func (cs *DeviceCommandService) Send(issuedBy uuid.UUID, deviceID, name string, args map[string]any, timeout time.Duration) (*domain.DeviceCommand, error) {
+	if err := validateCommand(name, args); err != nil {
+		return nil, err
+	}
+	if timeout == 0 {
+		timeout = cs.DefaultTimeout
+	}
+	if timeout < 0 || timeout > maxCommandTimeout {
+		return nil, ErrInvalidCommandTimeout
+	}
+
+	command, err := cs.CommandRepo.Create(context.Background(), domain.DeviceCommand{
+		ID:        uuid.New(),
+		DeviceID:  deviceID,
+		Name:      name,
+		Args:      args,
+		IssuedBy:  issuedBy,
+		ExpiresAt: time.Now().Add(timeout),
+	})
+	if err != nil {
+		if !errors.Is(err, repository.ErrDeviceNotFound) {
+			log.Printf("[ERROR] failed to create %s command of device %s: %v", name, deviceID, err)
+		}
+		return nil, err
+	}
+
+	if err := cs.publish(command); err != nil {
+		log.Printf("[ERROR] failed to publish command %s to device %s: %v", command.ID, deviceID, err)
+		_, finishErr := cs.CommandRepo.Finish(context.Background(), deviceID, name, command.ID, domain.CommandFailed, nil, ErrCommandNotDelivered.Error())
+		if finishErr != nil {
+			log.Printf("[ERROR] failed to mark undelivered command %s failed: %v", command.ID, finishErr)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrCommandNotDelivered, err)
+	}
+
+	log.Printf("[INFO] sent %s command %s to device %s", name, command.ID, deviceID)
+	return command, nil
+}
+
+func (cs *DeviceCommandService) Get(deviceID string, id uuid.UUID) (*domain.DeviceCommand, error) {
+	return cs.CommandRepo.Read(context.Background(), deviceID, id)
+}
+
+// List returns a page of the commands of a registered device, newest first,
+// and the cursor of the next page.
+func (cs *DeviceCommandService) List(deviceID string, filter domain.DeviceCommandFilter) ([]*domain.DeviceCommand, string, error) {
+	if _, err := cs.DeviceRepo.Read(context.Background(), deviceID); err != nil {
+		return nil, "", err
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultDevicePageSize
+	}
+	if filter.Limit > maxDevicePageSize {
+		filter.Limit = maxDevicePageSize
+	}
+	pageSize := filter.Limit
+
+	// One extra row tells whether another page follows.
+	filter.Limit++
+	commands, err := cs.CommandRepo.ListByDevice(context.Background(), deviceID, filter)
+	if err != nil {
+		log.Printf("[ERROR] failed to list commands of device %s: %v", deviceID, err)
+		return nil, "", err
+	}
+
+	if len(commands) <= pageSize {
+		return commands, "", nil
+	}
+	commands = commands[:pageSize]
+	last := commands[pageSize-1]
+	return commands, helper.EncodeCursor(last.CreatedAt, last.ID), nil
+}
+
+// AcceptReply applies a reply of a lift received over MQTT to the command it
+// correlates to. Replies to commands of another device or name, or to
+// commands that already finished or expired, are rejected with
+// repository.ErrCommandNotAwaiting.
+func (cs *DeviceCommandService) AcceptReply(deviceID, name string, reply domain.CommandReply) error {
+	var command *domain.DeviceCommand
+	var err error
+	if reply.Status == domain.CommandAcked {
+		command, err = cs.CommandRepo.Ack(context.Background(), deviceID, name, reply.CorrelationID)
+	} else {
+		command, err = cs.CommandRepo.Finish(context.Background(), deviceID, name, reply.CorrelationID, reply.Status, reply.Result, reply.Error)
+	}
+	if err != nil {
+		if !errors.Is(err, repository.ErrCommandNotAwaiting) {
+			log.Printf("[ERROR] failed to apply %s reply to command %s of device %s: %v", reply.Status, reply.CorrelationID, deviceID, err)
+		}
+		return err
+	}
+
+	if command.Status == domain.CommandFailed {
+		log.Printf("[WARN] %s command %s of device %s failed: %s", name, command.ID, deviceID, command.Error)
+	}
+	return nil
+}
+
+// WatchTimeouts marks commands that got no final reply before they expired as
+// timed out, checking every commandTimeoutCheckInterval until ctx is done.
+func (cs *DeviceCommandService) WatchTimeouts(ctx context.Context) {
+	ticker := time.NewTicker(commandTimeoutCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cs.timeOut(ctx)
+		}
+	}
+}
+
+// timeOut marks the commands that expired without a final reply as timed out.
+func (cs *DeviceCommandService) timeOut(ctx context.Context) {
+	commands, err := cs.CommandRepo.TimeOut(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] failed to time out device commands: %v", err)
+		}
+		return
+	}
+	for _, command := range commands {
+		log.Printf("[WARN] %s command %s of device %s timed out", command.Name, command.ID, command.DeviceID)
+	}
+}
+
+func (cs *DeviceCommandService) publish(command *domain.DeviceCommand) error {
+	payload, err := json.Marshal(domain.CommandMessage{
+		CorrelationID: command.ID,
+		Args:          command.Args,
+		IssuedAt:      command.CreatedAt,
+		ExpiresAt:     command.ExpiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandPublishTimeout)
+	defer cancel()
+	// Registered device IDs are valid single topic levels, as are the names
+	// of known commands.
+	topic := fmt.Sprintf("Lift/%s/commands/%s", command.DeviceID, command.Name)
+	return cs.MQTT.Publish(ctx, topic, payload)
+}
+
+// validateCommand checks that name is a command lifts accept and args are the
+// arguments it takes.
+func validateCommand(name string, args map[string]any) error {
+	switch name {
+	case domain.DeviceCommandReboot, domain.DeviceCommandDiagnostics:
+		if len(args) != 0 {
+			return fmt.Errorf("%w: %s takes no arguments", ErrInvalidCommandArgs, name)
+		}
+		return nil
+	case domain.DeviceCommandMaintenanceMode:
+		if _, ok := args["enabled"].(bool); !ok || len(args) != 1 {
+			return fmt.Errorf("%w: %s takes a boolean enabled argument only", ErrInvalidCommandArgs, name)
+		}
+		return nil
+	}
+	return ErrUnknownCommand
+}
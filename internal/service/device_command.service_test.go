@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/pkg/mqtt"
+)
+
+// fakeCommandRepo keeps the commands of the devices in known in memory and
+// changes their status like the queries of the repository do.
+type fakeCommandRepo struct {
+	repository.DeviceCommandRepository
+	known    map[string]bool
+	commands map[uuid.UUID]*domain.DeviceCommand
+}
+
+func newFakeCommandRepo(deviceIDs ...string) *fakeCommandRepo {
+	r := &fakeCommandRepo{known: map[string]bool{}, commands: map[uuid.UUID]*domain.DeviceCommand{}}
+	for _, id := range deviceIDs {
+		r.known[id] = true
+	}
+	return r
+}
+
+func (r *fakeCommandRepo) Create(ctx context.Context, command domain.DeviceCommand) (*domain.DeviceCommand, error) {
+	if !r.known[command.DeviceID] {
+		return nil, repository.ErrDeviceNotFound
+	}
+	command.CreatedAt = time.Now()
+	command.Status = domain.CommandPending
+	r.commands[command.ID] = &command
+	copied := command
+	return &copied, nil
+}
+
+// awaiting returns the command if it can still take a reply.
+func (r *fakeCommandRepo) awaiting(deviceID, name string, id uuid.UUID, statuses ...string) (*domain.DeviceCommand, error) {
+	command, ok := r.commands[id]
+	if !ok || command.DeviceID != deviceID || command.Name != name || !command.ExpiresAt.After(time.Now()) {
+		return nil, repository.ErrCommandNotAwaiting
+	}
+	for _, status := range statuses {
+		if command.Status == status {
+			return command, nil
+		}
+	}
+	return nil, repository.ErrCommandNotAwaiting
+}
+
+func (r *fakeCommandRepo) Ack(ctx context.Context, deviceID, name string, id uuid.UUID) (*domain.DeviceCommand, error) {
+	command, err := r.awaiting(deviceID, name, id, domain.CommandPending)
+	if err != nil {
+		return nil, err
+	}
+	command.Status = domain.CommandAcked
+	command.AckedAt = time.Now()
+	copied := *command
+	return &copied, nil
+}
+
+func (r *fakeCommandRepo) Finish(ctx context.Context, deviceID, name string, id uuid.UUID, status string, result map[string]any, reason string) (*domain.DeviceCommand, error) {
+	command, err := r.awaiting(deviceID, name, id, domain.CommandPending, domain.CommandAcked)
+	if err != nil {
+		return nil, err
+	}
+	command.Status = status
+	command.Result = result
+	command.Error = reason
+	command.CompletedAt = time.Now()
+	copied := *command
+	return &copied, nil
+}
+
+func (r *fakeCommandRepo) TimeOut(ctx context.Context) ([]*domain.DeviceCommand, error) {
+	var timedOut []*domain.DeviceCommand
+	for _, command := range r.commands {
+		if (command.Status != domain.CommandPending && command.Status != domain.CommandAcked) || command.ExpiresAt.After(time.Now()) {
+			continue
+		}
+		command.Status = domain.CommandTimedOut
+		command.CompletedAt = time.Now()
+		copied := *command
+		timedOut = append(timedOut, &copied)
+	}
+	return timedOut, nil
+}
+
+type publishedMessage struct {
+	topic   string
+	payload []byte
+}
+
+// fakeMQTTClient records what is published, or fails with err when set.
+type fakeMQTTClient struct {
+	mqtt.Client
+	err       error
+	published []publishedMessage
+}
+
+func (c *fakeMQTTClient) Publish(ctx context.Context, topic string, payload []byte) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.published = append(c.published, publishedMessage{topic: topic, payload: payload})
+	return nil
+}
+
+func newTestCommandService(publishErr error) (*DeviceCommandService, *fakeCommandRepo, *fakeMQTTClient) {
+	commands := newFakeCommandRepo("L-17", "L-18")
+	client := &fakeMQTTClient{err: publishErr}
+	cs := &DeviceCommandService{
+		CommandRepo:    commands,
+		MQTT:           client,
+		DefaultTimeout: time.Minute,
+	}
+	return cs, commands, client
+}
+
+func TestSendCommand(t *testing.T) {
+	cs, commands, client := newTestCommandService(nil)
+
+	command, err := cs.Send(uuid.New(), "L-17", domain.DeviceCommandMaintenanceMode, map[string]any{"enabled": true}, 0)
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if command.Status != domain.CommandPending {
+		t.Errorf("command status = %s, want %s", command.Status, domain.CommandPending)
+	}
+	if timeout := time.Until(command.ExpiresAt); timeout <= 0 || timeout > cs.DefaultTimeout {
+		t.Errorf("command expires in %s, want the default timeout %s", timeout, cs.DefaultTimeout)
+	}
+
+	if len(client.published) != 1 {
+		t.Fatalf("published %d messages, want 1", len(client.published))
+	}
+	if want := "Lift/L-17/commands/maintenance_mode"; client.published[0].topic != want {
+		t.Errorf("published on %s, want %s", client.published[0].topic, want)
+	}
+	var message domain.CommandMessage
+	if err := json.Unmarshal(client.published[0].payload, &message); err != nil {
+		t.Fatalf("published payload %s: %v", client.published[0].payload, err)
+	}
+	if message.CorrelationID != command.ID || message.Args["enabled"] != true {
+		t.Errorf("published %+v, want correlation ID %s with enabled", message, command.ID)
+	}
+	if stored := commands.commands[command.ID]; stored.Status != domain.CommandPending {
+		t.Errorf("stored command status = %s, want %s", stored.Status, domain.CommandPending)
+	}
+}
+
+func TestSendCommandRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		deviceID string
+		command  string
+		args     map[string]any
+		timeout  time.Duration
+		wantErr  error
+	}{
+		{"unknown command", "L-17", "self_destruct", nil, 0, ErrUnknownCommand},
+		{"unexpected arguments", "L-17", domain.DeviceCommandReboot, map[string]any{"now": true}, 0, ErrInvalidCommandArgs},
+		{"missing argument", "L-17", domain.DeviceCommandMaintenanceMode, nil, 0, ErrInvalidCommandArgs},
+		{"negative timeout", "L-17", domain.DeviceCommandReboot, nil, -time.Second, ErrInvalidCommandTimeout},
+		{"timeout too long", "L-17", domain.DeviceCommandReboot, nil, maxCommandTimeout + time.Second, ErrInvalidCommandTimeout},
+		{"unknown device", "L-404", domain.DeviceCommandReboot, nil, 0, repository.ErrDeviceNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs, _, client := newTestCommandService(nil)
+
+			_, err := cs.Send(uuid.New(), tt.deviceID, tt.command, tt.args, tt.timeout)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Send() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(client.published) != 0 {
+				t.Errorf("published %d messages, want none", len(client.published))
+			}
+		})
+	}
+}
+
+func TestSendCommandNotDelivered(t *testing.T) {
+	cs, commands, _ := newTestCommandService(mqtt.ErrNotConnected)
+
+	_, err := cs.Send(uuid.New(), "L-17", domain.DeviceCommandReboot, nil, 0)
+	if !errors.Is(err, ErrCommandNotDelivered) {
+		t.Fatalf("Send() error = %v, want %v", err, ErrCommandNotDelivered)
+	}
+
+	if len(commands.commands) != 1 {
+		t.Fatalf("stored %d commands, want 1", len(commands.commands))
+	}
+	for _, command := range commands.commands {
+		if command.Status != domain.CommandFailed || command.Error != ErrCommandNotDelivered.Error() {
+			t.Errorf("undelivered command %s with error %q, want %s with %q", command.Status, command.Error, domain.CommandFailed, ErrCommandNotDelivered)
+		}
+	}
+}
+
+func TestAcceptReply(t *testing.T) {
+	cs, commands, _ := newTestCommandService(nil)
+	command, err := cs.Send(uuid.New(), "L-17", domain.DeviceCommandDiagnostics, nil, 0)
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	stored := commands.commands[command.ID]
+
+	ack := domain.CommandReply{CorrelationID: command.ID, Status: domain.CommandAcked}
+	if err := cs.AcceptReply("L-17", domain.DeviceCommandDiagnostics, ack); err != nil {
+		t.Fatalf("AcceptReply(acked) error = %v", err)
+	}
+	if stored.Status != domain.CommandAcked || stored.AckedAt.IsZero() {
+		t.Errorf("command %s acked at %s, want acked", stored.Status, stored.AckedAt)
+	}
+
+	result := map[string]any{"door_cycles": float64(1200)}
+	done := domain.CommandReply{CorrelationID: command.ID, Status: domain.CommandCompleted, Result: result}
+	if err := cs.AcceptReply("L-17", domain.DeviceCommandDiagnostics, done); err != nil {
+		t.Fatalf("AcceptReply(completed) error = %v", err)
+	}
+	if stored.Status != domain.CommandCompleted || stored.CompletedAt.IsZero() || stored.Result["door_cycles"] != float64(1200) {
+		t.Errorf("command %s with result %v, want completed with %v", stored.Status, stored.Result, result)
+	}
+
+	// The command finished, so neither another final reply nor a late ack
+	// applies.
+	for _, reply := range []domain.CommandReply{done, ack} {
+		if err := cs.AcceptReply("L-17", domain.DeviceCommandDiagnostics, reply); !errors.Is(err, repository.ErrCommandNotAwaiting) {
+			t.Errorf("AcceptReply(%s) after completion error = %v, want %v", reply.Status, err, repository.ErrCommandNotAwaiting)
+		}
+	}
+}
+
+func TestAcceptReplyNotAwaiting(t *testing.T) {
+	tests := []struct {
+		name     string
+		deviceID string
+		command  string
+		expired  bool
+	}{
+		{"expired command", "L-17", domain.DeviceCommandReboot, true},
+		{"command of another device", "L-18", domain.DeviceCommandReboot, false},
+		{"command of another name", "L-17", domain.DeviceCommandDiagnostics, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs, commands, _ := newTestCommandService(nil)
+			command, err := cs.Send(uuid.New(), "L-17", domain.DeviceCommandReboot, nil, 0)
+			if err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+			stored := commands.commands[command.ID]
+			if tt.expired {
+				stored.ExpiresAt = time.Now().Add(-time.Second)
+			}
+
+			for _, status := range []string{domain.CommandAcked, domain.CommandCompleted, domain.CommandFailed} {
+				reply := domain.CommandReply{CorrelationID: command.ID, Status: status}
+				if err := cs.AcceptReply(tt.deviceID, tt.command, reply); !errors.Is(err, repository.ErrCommandNotAwaiting) {
+					t.Errorf("AcceptReply(%s) error = %v, want %v", status, err, repository.ErrCommandNotAwaiting)
+				}
+			}
+			if stored.Status != domain.CommandPending {
+				t.Errorf("command status = %s, want %s", stored.Status, domain.CommandPending)
+			}
+		})
+	}
+}
+
+func TestCommandTimeOut(t *testing.T) {
+	cs, commands, _ := newTestCommandService(nil)
+	send := func() *domain.DeviceCommand {
+		t.Helper()
+		command, err := cs.Send(uuid.New(), "L-17", domain.DeviceCommandReboot, nil, 0)
+		if err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+		return commands.commands[command.ID]
+	}
+
+	pending, acked, completed, current := send(), send(), send(), send()
+	acked.Status = domain.CommandAcked
+	completed.Status = domain.CommandCompleted
+	for _, command := range []*domain.DeviceCommand{pending, acked, completed} {
+		command.ExpiresAt = time.Now().Add(-time.Second)
+	}
+
+	cs.timeOut(context.Background())
+
+	tests := []struct {
+		name    string
+		command *domain.DeviceCommand
+		want    string
+	}{
+		{"expired pending", pending, domain.CommandTimedOut},
+		{"expired acked", acked, domain.CommandTimedOut},
+		{"expired completed", completed, domain.CommandCompleted},
+		{"not expired", current, domain.CommandPending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.command.Status != tt.want {
+				t.Errorf("command status = %s, want %s", tt.command.Status, tt.want)
+			}
+		})
+	}
+
+	reply := domain.CommandReply{CorrelationID: pending.ID, Status: domain.CommandCompleted}
+	if err := cs.AcceptReply("L-17", domain.DeviceCommandReboot, reply); !errors.Is(err, repository.ErrCommandNotAwaiting) {
+		t.Errorf("AcceptReply() to a timed out command error = %v, want %v", err, repository.ErrCommandNotAwaiting)
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
+)
+
+var ErrInvalidDeviceEvent = errors.New("invalid device event")
+
+type DeviceEventService struct {
+	DeviceRepo      repository.DeviceRepository
+	EventRepo       repository.DeviceEventRepository
+	IncidentService *IncidentService
+}
+
+func NewDeviceEventService(deviceRepo repository.DeviceRepository, eventRepo repository.DeviceEventRepository, incidentService *IncidentService) *DeviceEventService {
+	if deviceRepo == nil {
+		log.Fatalf("[FATAL] DeviceRepository cannot be nil")
+	}
+	if eventRepo == nil {
+		log.Fatalf("[FATAL] DeviceEventRepository cannot be nil")
+	}
+	if incidentService == nil {
+		log.Fatalf("[FATAL] IncidentService cannot be nil")
+	}
+	return &DeviceEventService{
+		DeviceRepo:      deviceRepo,
+		EventRepo:       eventRepo,
+		IncidentService: incidentService,
+	}
+}
+
+// AcceptDoor records a door event received over MQTT.
+func (es *DeviceEventService) AcceptDoor(deviceID string, door domain.DoorEvent) error {
+	_, err := es.record(deviceID, domain.DeviceEventDoor, door.Timestamp, map[string]any{
+		"floor":  door.Floor,
+		"action": door.Action,
+	})
+	return err
+}
+
+// AcceptFault records a fault event received over MQTT and opens an incident
+// for warning and critical faults. The event stays recorded when the incident
+// cannot be opened.
+func (es *DeviceEventService) AcceptFault(deviceID string, fault domain.FaultEvent) error {
+	_, err := es.record(deviceID, domain.DeviceEventFault, fault.Timestamp, map[string]any{
+		"code":     fault.Code,
+		"severity": fault.Severity,
+		"message":  fault.Message,
+	})
+	if err != nil {
+		return err
+	}
+	if fault.Severity == domain.FaultCritical {
+		log.Printf("[WARN] device %s reported critical fault %s: %s", deviceID, fault.Code, fault.Message)
+	}
+	return es.IncidentService.ReportFault(deviceID, fault)
+}
+
+// AcceptTrip records a trip event received over MQTT.
+func (es *DeviceEventService) AcceptTrip(deviceID string, trip domain.TripEvent) error {
+	_, err := es.record(deviceID, domain.DeviceEventTrip, trip.Timestamp, map[string]any{
+		"from_floor":       trip.FromFloor,
+		"to_floor":         trip.ToFloor,
+		"duration_seconds": trip.DurationSeconds,
+	})
+	return err
+}
+
+// AcceptAlarm records an alarm event received over MQTT.
+func (es *DeviceEventService) AcceptAlarm(deviceID string, alarm domain.AlarmEvent) error {
+	_, err := es.record(deviceID, domain.DeviceEventAlarm, alarm.Timestamp, map[string]any{
+		"type":   alarm.Type,
+		"active": alarm.Active,
+	})
+	if err != nil {
+		return err
+	}
+	if alarm.Active {
+		log.Printf("[WARN] device %s raised alarm %s", deviceID, alarm.Type)
+	}
+	return nil
+}
+
+// List returns a page of the events of a registered device, newest first, and
+// the cursor of the next page.
+func (es *DeviceEventService) List(deviceID string, filter domain.DeviceEventFilter) ([]*domain.DeviceEvent, string, error) {
+	if _, err := es.DeviceRepo.Read(context.Background(), deviceID); err != nil {
+		return nil, "", err
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultDevicePageSize
+	}
+	if filter.Limit > maxDevicePageSize {
+		filter.Limit = maxDevicePageSize
+	}
+	pageSize := filter.Limit
+
+	// One extra row tells whether another page follows.
+	filter.Limit++
+	events, err := es.EventRepo.ListByDevice(context.Background(), deviceID, filter)
+	if err != nil {
+		log.Printf("[ERROR] failed to list events of device %s: %v", deviceID, err)
+		return nil, "", err
+	}
+
+	if len(events) <= pageSize {
+		return events, "", nil
+	}
+	events = events[:pageSize]
+	last := events[pageSize-1]
+	return events, helper.EncodeCursor(last.CreatedAt, last.ID), nil
+}
+
+// record stores an event of a registered device. Events of devices missing
+// from the registry are rejected with ErrDeviceNotFound.
+func (es *DeviceEventService) record(deviceID, kind string, timestamp time.Time, details map[string]any) (*domain.DeviceEvent, error) {
+	occurredAt, err := reportedTime(timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDeviceEvent, err)
+	}
+
+	event, err := es.EventRepo.Create(context.Background(), domain.DeviceEvent{
+		ID:         uuid.New(),
+		DeviceID:   deviceID,
+		Kind:       kind,
+		OccurredAt: occurredAt,
+		Details:    details,
+	})
+	if err != nil {
+		if !errors.Is(err, repository.ErrDeviceNotFound) {
+			log.Printf("[ERROR] failed to record %s event of device %s: %v", kind, deviceID, err)
+		}
+		return nil, err
+	}
+	return event, nil
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
+	"github.com/vgrigalashvili/veemon/pkg/worker"
+)
+
+// escalationCheckInterval is how often unacknowledged incidents are checked
+// for escalation; it bounds how late an escalation can be.
+const escalationCheckInterval = time.Minute
+
+var (
+	ErrInvalidIncident        = errors.New("invalid incident")
+	ErrInvalidEscalationLevel = errors.New("escalation level exceeds the maximum escalation level")
+)
+
+type IncidentService struct {
+	IncidentRepo    repository.IncidentRepository
+	ContactRepo     repository.DeviceContactRepository
+	DeviceRepo      repository.DeviceRepository
+	TaskDistributor worker.TaskDistributor
+	Policy          domain.IncidentPolicy
+}
+
+func NewIncidentService(incidentRepo repository.IncidentRepository, contactRepo repository.DeviceContactRepository, deviceRepo repository.DeviceRepository, taskDistributor worker.TaskDistributor, policy domain.IncidentPolicy) *IncidentService {
+	if incidentRepo == nil {
+		log.Fatalf("[FATAL] IncidentRepository cannot be nil")
+	}
+	if contactRepo == nil {
+		log.Fatalf("[FATAL] DeviceContactRepository cannot be nil")
+	}
+	if deviceRepo == nil {
+		log.Fatalf("[FATAL] DeviceRepository cannot be nil")
+	}
+	if taskDistributor == nil {
+		log.Fatalf("[FATAL] TaskDistributor cannot be nil")
+	}
+	if policy.EscalateAfterCritical <= 0 || policy.EscalateAfterWarning <= 0 || policy.MaxEscalationLevel < 0 || policy.ReopenWindow < 0 {
+		log.Fatalf("[FATAL] invalid incident policy %+v", policy)
+	}
+	return &IncidentService{
+		IncidentRepo:    incidentRepo,
+		ContactRepo:     contactRepo,
+		DeviceRepo:      deviceRepo,
+		TaskDistributor: taskDistributor,
+		Policy:          policy,
+	}
+}
+
+// ReportFault opens an incident for a warning or critical fault of a lift.
+// Info faults are only recorded as events.
+func (is *IncidentService) ReportFault(deviceID string, fault domain.FaultEvent) error {
+	if fault.Severity == domain.FaultInfo {
+		return nil
+	}
+	summary := fault.Message
+	if summary == "" {
+		summary = fmt.Sprintf("Fault %s", fault.Code)
+	}
+	_, err := is.Report(domain.IncidentOccurrence{
+		DeviceID: deviceID,
+		Kind:     domain.IncidentFault,
+		Code:     fault.Code,
+		Severity: fault.Severity,
+		Summary:  summary,
+	})
+	return err
+}
+
+// ReportOffline opens an incident for a lift that stopped sending heartbeats.
+func (is *IncidentService) ReportOffline(deviceID string, silentFor time.Duration) error {
+	_, err := is.Report(domain.IncidentOccurrence{
+		DeviceID: deviceID,
+		Kind:     domain.IncidentOffline,
+		Code:     domain.IncidentOffline,
+		Severity: domain.IncidentSeverityWarning,
+		Summary:  fmt.Sprintf("No heartbeat for %s", silentFor),
+	})
+	return err
+}
+
+// Report opens an incident for the occurrence of a problem and notifies the
+// level 0 contacts of the lift. A problem that is already open, or was
+// resolved within the reopen window, counts as another occurrence of its
+// incident instead, so a flapping lift does not flood anyone with incidents.
+// Only reopening the incident, or it becoming critical, notifies again.
+func (is *IncidentService) Report(occurrence domain.IncidentOccurrence) (*domain.Incident, error) {
+	if occurrence.Code == "" {
+		return nil, fmt.Errorf("%w: code is required", ErrInvalidIncident)
+	}
+	if occurrence.Kind != domain.IncidentFault && occurrence.Kind != domain.IncidentOffline {
+		return nil, fmt.Errorf("%w: unknown kind %q", ErrInvalidIncident, occurrence.Kind)
+	}
+	if occurrence.Severity != domain.IncidentSeverityWarning && occurrence.Severity != domain.IncidentSeverityCritical {
+		return nil, fmt.Errorf("%w: unknown severity %q", ErrInvalidIncident, occurrence.Severity)
+	}
+
+	incident, change, err := is.IncidentRepo.Open(context.Background(), occurrence, is.Policy.ReopenWindow)
+	if err != nil {
+		if !errors.Is(err, repository.ErrDeviceNotFound) {
+			log.Printf("[ERROR] failed to open %s incident %s of device %s: %v", occurrence.Kind, occurrence.Code, occurrence.DeviceID, err)
+		}
+		return nil, err
+	}
+	switch change {
+	case domain.IncidentOpened:
+		log.Printf("[WARN] device %s: opened %s %s incident %s", incident.DeviceID, incident.Severity, incident.Kind, incident.Code)
+		is.notify(incident, false)
+	case domain.IncidentRaised:
+		log.Printf("[WARN] device %s: %s incident %s became critical", incident.DeviceID, incident.Kind, incident.Code)
+		is.notify(incident, true)
+	}
+	return incident, nil
+}
+
+func (is *IncidentService) Get(id uuid.UUID) (*domain.Incident, error) {
+	return is.IncidentRepo.Read(context.Background(), id)
+}
+
+// List returns a page of incidents, newest first, and the cursor of the next
+// page.
+func (is *IncidentService) List(filter domain.IncidentFilter) ([]*domain.Incident, string, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultDevicePageSize
+	}
+	if filter.Limit > maxDevicePageSize {
+		filter.Limit = maxDevicePageSize
+	}
+	pageSize := filter.Limit
+
+	// One extra row tells whether another page follows.
+	filter.Limit++
+	incidents, err := is.IncidentRepo.List(context.Background(), filter)
+	if err != nil {
+		log.Printf("[ERROR] failed to list incidents: %v", err)
+		return nil, "", err
+	}
+
+	if len(incidents) <= pageSize {
+		return incidents, "", nil
+	}
+	incidents = incidents[:pageSize]
+	last := incidents[pageSize-1]
+	return incidents, helper.EncodeCursor(last.CreatedAt, last.ID), nil
+}
+
+// Acknowledge records that the user is taking care of an open incident, which
+// stops its escalation.
+func (is *IncidentService) Acknowledge(id, userID uuid.UUID) (*domain.Incident, error) {
+	incident, err := is.IncidentRepo.Acknowledge(context.Background(), id, userID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrIncidentNotFound) && !errors.Is(err, repository.ErrIncidentStatusConflict) {
+			log.Printf("[ERROR] failed to acknowledge incident %s: %v", id, err)
+		}
+		return nil, err
+	}
+	return incident, nil
+}
+
+// Resolve closes an open or acknowledged incident. The problem happening again
+// within the reopen window reopens it.
+func (is *IncidentService) Resolve(id, userID uuid.UUID) (*domain.Incident, error) {
+	incident, err := is.IncidentRepo.Resolve(context.Background(), id, userID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrIncidentNotFound) && !errors.Is(err, repository.ErrIncidentStatusConflict) {
+			log.Printf("[ERROR] failed to resolve incident %s: %v", id, err)
+		}
+		return nil, err
+	}
+	return incident, nil
+}
+
+// WatchEscalations escalates incidents nobody acknowledged in time, once per
+// escalationCheckInterval until ctx is done. Each escalation notifies the
+// contacts of the lift at the new level, until the incident reaches the
+// maximum escalation level.
+func (is *IncidentService) WatchEscalations(ctx context.Context) {
+	ticker := time.NewTicker(escalationCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			is.escalate(ctx, time.Now())
+		}
+	}
+}
+
+// escalate escalates the incidents that were due at now and notifies their
+// contacts.
+func (is *IncidentService) escalate(ctx context.Context, now time.Time) {
+	incidents, err := is.IncidentRepo.Escalate(ctx,
+		is.Policy.MaxEscalationLevel,
+		now.Add(-is.Policy.EscalateAfterCritical),
+		now.Add(-is.Policy.EscalateAfterWarning),
+	)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] failed to escalate incidents: %v", err)
+		}
+		return
+	}
+	for _, incident := range incidents {
+		log.Printf("[WARN] incident %s of device %s escalated to level %d", incident.ID, incident.DeviceID, incident.EscalationLevel)
+		is.notify(incident, false)
+	}
+}
+
+// Contacts returns the users notified of the incidents of a registered device.
+func (is *IncidentService) Contacts(deviceID string) ([]*domain.DeviceContact, error) {
+	if _, err := is.DeviceRepo.Read(context.Background(), deviceID); err != nil {
+		return nil, err
+	}
+
+	contacts, err := is.ContactRepo.ListByDevice(context.Background(), deviceID)
+	if err != nil {
+		log.Printf("[ERROR] failed to list contacts of device %s: %v", deviceID, err)
+		return nil, err
+	}
+	return contacts, nil
+}
+
+// SetContact adds a user to the contacts of a registered device, or changes
+// the escalation level the user is notified from.
+func (is *IncidentService) SetContact(contact domain.DeviceContact) (*domain.DeviceContact, error) {
+	if contact.EscalationLevel > is.Policy.MaxEscalationLevel {
+		return nil, ErrInvalidEscalationLevel
+	}
+	if _, err := is.DeviceRepo.Read(context.Background(), contact.DeviceID); err != nil {
+		return nil, err
+	}
+
+	savedContact, err := is.ContactRepo.Upsert(context.Background(), contact)
+	if err != nil {
+		if !errors.Is(err, repository.ErrUserNotFound) {
+			log.Printf("[ERROR] failed to set contact %s of device %s: %v", contact.UserID, contact.DeviceID, err)
+		}
+		return nil, err
+	}
+	return savedContact, nil
+}
+
+func (is *IncidentService) RemoveContact(deviceID string, userID uuid.UUID) error {
+	err := is.ContactRepo.Delete(context.Background(), deviceID, userID)
+	if err != nil && !errors.Is(err, repository.ErrContactNotFound) {
+		log.Printf("[ERROR] failed to remove contact %s of device %s: %v", userID, deviceID, err)
+	}
+	return err
+}
+
+// notify enqueues the notification of the contacts of an incident's lift at
+// its escalation level, or up to it when raised tells that the incident became
+// critical. A failure to enqueue is logged without failing the caller; the
+// incident itself is stored and escalates regardless.
+func (is *IncidentService) notify(incident *domain.Incident, raised bool) {
+	payload := &worker.PayloadSendIncidentNotification{
+		IncidentID: incident.ID,
+		Level:      incident.EscalationLevel,
+		Raised:     raised,
+	}
+	opts := []asynq.Option{
+		asynq.MaxRetry(10),
+		asynq.Queue(worker.QueueCritical),
+	}
+	if err := is.TaskDistributor.DistributeTaskSendIncidentNotification(context.Background(), payload, opts...); err != nil {
+		log.Printf("[ERROR] failed to distribute notification of incident %s: %v", incident.ID, err)
+	}
+}
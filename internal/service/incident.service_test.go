@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/pkg/worker"
+)
+
+// fakeIncidentRepo keeps incidents in memory and opens, recurs and escalates
+// them like the queries of the repository do.
+type fakeIncidentRepo struct {
+	repository.IncidentRepository
+	incidents []*domain.Incident
+}
+
+func (r *fakeIncidentRepo) Open(ctx context.Context, occurrence domain.IncidentOccurrence, reopenWindow time.Duration) (*domain.Incident, domain.IncidentChange, error) {
+	now := time.Now()
+	for i := len(r.incidents) - 1; i >= 0; i-- {
+		incident := r.incidents[i]
+		if incident.DeviceID != occurrence.DeviceID || incident.Kind != occurrence.Kind || incident.Code != occurrence.Code {
+			continue
+		}
+		if incident.Status == domain.IncidentStatusResolved && !incident.ResolvedAt.After(now.Add(-reopenWindow)) {
+			break
+		}
+
+		change := incident.Recurrence(occurrence.Severity)
+		incident.Occurrences++
+		incident.LastSeenAt = now
+		incident.Summary = occurrence.Summary
+		switch change {
+		case domain.IncidentOpened:
+			incident.Status = domain.IncidentStatusOpen
+			incident.OpenedAt = now
+			incident.EscalationLevel = 0
+			incident.EscalatedAt = time.Time{}
+			incident.AcknowledgedBy = uuid.Nil
+			incident.AcknowledgedAt = time.Time{}
+		case domain.IncidentRaised:
+			incident.EscalatedAt = now
+		}
+		if occurrence.Severity == domain.IncidentSeverityCritical {
+			incident.Severity = domain.IncidentSeverityCritical
+		}
+		incident.ResolvedBy = uuid.Nil
+		incident.ResolvedAt = time.Time{}
+		copied := *incident
+		return &copied, change, nil
+	}
+
+	incident := &domain.Incident{
+		ID:          uuid.New(),
+		CreatedAt:   now,
+		DeviceID:    occurrence.DeviceID,
+		Kind:        occurrence.Kind,
+		Code:        occurrence.Code,
+		Severity:    occurrence.Severity,
+		Status:      domain.IncidentStatusOpen,
+		Summary:     occurrence.Summary,
+		Occurrences: 1,
+		OpenedAt:    now,
+		LastSeenAt:  now,
+	}
+	r.incidents = append(r.incidents, incident)
+	copied := *incident
+	return &copied, domain.IncidentOpened, nil
+}
+
+func (r *fakeIncidentRepo) Escalate(ctx context.Context, maxLevel int, criticalBefore, warningBefore time.Time) ([]*domain.Incident, error) {
+	var escalated []*domain.Incident
+	for _, incident := range r.incidents {
+		if incident.Status != domain.IncidentStatusOpen || incident.EscalationLevel >= maxLevel {
+			continue
+		}
+		since := incident.EscalatedAt
+		if since.IsZero() {
+			since = incident.OpenedAt
+		}
+		before := warningBefore
+		if incident.Severity == domain.IncidentSeverityCritical {
+			before = criticalBefore
+		}
+		if !since.Before(before) {
+			continue
+		}
+
+		incident.EscalationLevel++
+		incident.EscalatedAt = time.Now()
+		copied := *incident
+		escalated = append(escalated, &copied)
+	}
+	return escalated, nil
+}
+
+// fakeTaskDistributor records the incident notifications it was asked to send.
+type fakeTaskDistributor struct {
+	worker.TaskDistributor
+	notifications []worker.PayloadSendIncidentNotification
+}
+
+func (d *fakeTaskDistributor) DistributeTaskSendIncidentNotification(ctx context.Context, payload *worker.PayloadSendIncidentNotification, opts ...asynq.Option) error {
+	d.notifications = append(d.notifications, *payload)
+	return nil
+}
+
+var testIncidentPolicy = domain.IncidentPolicy{
+	EscalateAfterCritical: 5 * time.Minute,
+	EscalateAfterWarning:  30 * time.Minute,
+	MaxEscalationLevel:    2,
+	ReopenWindow:          time.Hour,
+}
+
+func newTestIncidentService() (*IncidentService, *fakeIncidentRepo, *fakeTaskDistributor) {
+	incidents := &fakeIncidentRepo{}
+	distributor := &fakeTaskDistributor{}
+	is := &IncidentService{
+		IncidentRepo:    incidents,
+		TaskDistributor: distributor,
+		Policy:          testIncidentPolicy,
+	}
+	return is, incidents, distributor
+}
+
+func faultOccurrence(severity string) domain.IncidentOccurrence {
+	return domain.IncidentOccurrence{
+		DeviceID: "L-17",
+		Kind:     domain.IncidentFault,
+		Code:     "E42",
+		Severity: severity,
+		Summary:  "Door stuck",
+	}
+}
+
+func TestIncidentReport(t *testing.T) {
+	tests := []struct {
+		name string
+		// prepare changes the incident opened by a warning occurrence before
+		// the occurrence under test is reported.
+		prepare        func(incident *domain.Incident)
+		severity       string
+		wantNewID      bool
+		wantStatus     string
+		wantSeverity   string
+		wantOccurrence int
+		wantNotified   bool
+		wantRaised     bool
+	}{
+		{
+			name:           "open incident recurs",
+			prepare:        func(*domain.Incident) {},
+			severity:       domain.IncidentSeverityWarning,
+			wantStatus:     domain.IncidentStatusOpen,
+			wantSeverity:   domain.IncidentSeverityWarning,
+			wantOccurrence: 2,
+		},
+		{
+			name: "acknowledged incident recurs",
+			prepare: func(incident *domain.Incident) {
+				incident.Status = domain.IncidentStatusAcknowledged
+			},
+			severity:       domain.IncidentSeverityWarning,
+			wantStatus:     domain.IncidentStatusAcknowledged,
+			wantSeverity:   domain.IncidentSeverityWarning,
+			wantOccurrence: 2,
+		},
+		{
+			name: "resolved within the window reopens",
+			prepare: func(incident *domain.Incident) {
+				incident.Status = domain.IncidentStatusResolved
+				incident.ResolvedAt = time.Now().Add(-10 * time.Minute)
+				incident.EscalationLevel = 2
+			},
+			severity:       domain.IncidentSeverityWarning,
+			wantStatus:     domain.IncidentStatusOpen,
+			wantSeverity:   domain.IncidentSeverityWarning,
+			wantOccurrence: 2,
+			wantNotified:   true,
+		},
+		{
+			name: "resolved before the window opens a new incident",
+			prepare: func(incident *domain.Incident) {
+				incident.Status = domain.IncidentStatusResolved
+				incident.ResolvedAt = time.Now().Add(-2 * time.Hour)
+			},
+			severity:       domain.IncidentSeverityWarning,
+			wantNewID:      true,
+			wantStatus:     domain.IncidentStatusOpen,
+			wantSeverity:   domain.IncidentSeverityWarning,
+			wantOccurrence: 1,
+			wantNotified:   true,
+		},
+		{
+			name:           "warning raised to critical",
+			prepare:        func(*domain.Incident) {},
+			severity:       domain.IncidentSeverityCritical,
+			wantStatus:     domain.IncidentStatusOpen,
+			wantSeverity:   domain.IncidentSeverityCritical,
+			wantOccurrence: 2,
+			wantNotified:   true,
+			wantRaised:     true,
+		},
+		{
+			name: "critical stays critical",
+			prepare: func(incident *domain.Incident) {
+				incident.Severity = domain.IncidentSeverityCritical
+			},
+			severity:       domain.IncidentSeverityWarning,
+			wantStatus:     domain.IncidentStatusOpen,
+			wantSeverity:   domain.IncidentSeverityCritical,
+			wantOccurrence: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is, incidents, distributor := newTestIncidentService()
+
+			first, err := is.Report(faultOccurrence(domain.IncidentSeverityWarning))
+			if err != nil {
+				t.Fatalf("Report() error = %v", err)
+			}
+			if len(distributor.notifications) != 1 || distributor.notifications[0].Level != 0 || distributor.notifications[0].Raised {
+				t.Fatalf("opening notified %+v, want level 0 once", distributor.notifications)
+			}
+			tt.prepare(incidents.incidents[0])
+			distributor.notifications = nil
+
+			incident, err := is.Report(faultOccurrence(tt.severity))
+			if err != nil {
+				t.Fatalf("Report() error = %v", err)
+			}
+
+			if (incident.ID != first.ID) != tt.wantNewID {
+				t.Errorf("incident ID changed = %v, want %v", incident.ID != first.ID, tt.wantNewID)
+			}
+			if incident.Status != tt.wantStatus || incident.Severity != tt.wantSeverity || incident.Occurrences != tt.wantOccurrence {
+				t.Errorf("incident %s %s with %d occurrences, want %s %s with %d", incident.Status, incident.Severity, incident.Occurrences, tt.wantStatus, tt.wantSeverity, tt.wantOccurrence)
+			}
+			if tt.wantNotified && incident.EscalationLevel != 0 {
+				t.Errorf("escalation level = %d, want 0", incident.EscalationLevel)
+			}
+
+			if !tt.wantNotified {
+				if len(distributor.notifications) != 0 {
+					t.Errorf("notified %+v, want none", distributor.notifications)
+				}
+				return
+			}
+			if len(distributor.notifications) != 1 {
+				t.Fatalf("notified %d times, want once", len(distributor.notifications))
+			}
+			notification := distributor.notifications[0]
+			if notification.IncidentID != incident.ID || notification.Raised != tt.wantRaised {
+				t.Errorf("notification %+v, want incident %s raised %v", notification, incident.ID, tt.wantRaised)
+			}
+		})
+	}
+}
+
+func TestIncidentRaisedRestartsEscalation(t *testing.T) {
+	is, incidents, distributor := newTestIncidentService()
+	if _, err := is.Report(faultOccurrence(domain.IncidentSeverityWarning)); err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	// A warning open for longer than a critical incident escalates after.
+	incidents.incidents[0].OpenedAt = time.Now().Add(-10 * time.Minute)
+
+	if _, err := is.Report(faultOccurrence(domain.IncidentSeverityCritical)); err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	distributor.notifications = nil
+
+	is.escalate(context.Background(), time.Now())
+	if len(distributor.notifications) != 0 {
+		t.Errorf("escalated right after becoming critical: %+v", distributor.notifications)
+	}
+
+	is.escalate(context.Background(), time.Now().Add(testIncidentPolicy.EscalateAfterCritical+time.Second))
+	if len(distributor.notifications) != 1 || distributor.notifications[0].Level != 1 {
+		t.Errorf("notified %+v, want level 1 once", distributor.notifications)
+	}
+}
+
+func TestIncidentEscalation(t *testing.T) {
+	tests := []struct {
+		name       string
+		severity   string
+		status     string
+		openedAgo  time.Duration
+		wantLevels []int
+	}{
+		{"critical escalates to the maximum level", domain.IncidentSeverityCritical, domain.IncidentStatusOpen, 6 * time.Minute, []int{1, 2}},
+		{"warning escalates to the maximum level", domain.IncidentSeverityWarning, domain.IncidentStatusOpen, 31 * time.Minute, []int{1, 2}},
+		{"warning is not due yet", domain.IncidentSeverityWarning, domain.IncidentStatusOpen, 6 * time.Minute, nil},
+		{"acknowledged does not escalate", domain.IncidentSeverityCritical, domain.IncidentStatusAcknowledged, time.Hour, nil},
+		{"resolved does not escalate", domain.IncidentSeverityCritical, domain.IncidentStatusResolved, time.Hour, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			is, incidents, distributor := newTestIncidentService()
+			if _, err := is.Report(faultOccurrence(tt.severity)); err != nil {
+				t.Fatalf("Report() error = %v", err)
+			}
+			incident := incidents.incidents[0]
+			incident.Status = tt.status
+			incident.OpenedAt = time.Now().Add(-tt.openedAgo)
+			distributor.notifications = nil
+
+			// The checks are as far apart as the incident is old, one more
+			// often than there are escalation levels.
+			now := time.Now()
+			for range testIncidentPolicy.MaxEscalationLevel + 1 {
+				is.escalate(context.Background(), now)
+				now = now.Add(tt.openedAgo)
+			}
+
+			var levels []int
+			for _, notification := range distributor.notifications {
+				if notification.IncidentID != incident.ID || notification.Raised {
+					t.Errorf("unexpected notification %+v", notification)
+				}
+				levels = append(levels, notification.Level)
+			}
+			if len(levels) != len(tt.wantLevels) {
+				t.Fatalf("notified levels %v, want %v", levels, tt.wantLevels)
+			}
+			for i := range levels {
+				if levels[i] != tt.wantLevels[i] {
+					t.Errorf("notified levels %v, want %v", levels, tt.wantLevels)
+					break
+				}
+			}
+			if want := len(tt.wantLevels); incident.EscalationLevel != want {
+				t.Errorf("escalation level = %d, want %d", incident.EscalationLevel, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
+)
+
+var (
+	ErrTaskForbidden         = errors.New("not allowed to change this task")
+	ErrInvalidTaskStatus     = errors.New("invalid task status")
+	ErrInvalidTaskTransition = errors.New("task cannot move to this status")
+	ErrAssigneeRequired      = errors.New("assignee is required to assign a task")
+	ErrInvalidAssignee       = errors.New("task owner cannot be its assignee")
+	ErrDeadlineInPast        = errors.New("deadline must be in the future")
+)
+
+const (
+	defaultTaskPageSize = 20
+	maxTaskPageSize     = 100
+)
+
+type TaskService struct {
+	TaskRepo repository.TaskRepository
+}
+
+func NewTaskService(taskRepo repository.TaskRepository) *TaskService {
+	if taskRepo == nil {
+		log.Fatalf("[FATAL] TaskRepository cannot be nil")
+	}
+	return &TaskService{
+		TaskRepo: taskRepo,
+	}
+}
+
+// Create stores a new task owned by the actor.
+func (ts *TaskService) Create(actor domain.Actor, args domain.Task) (*domain.Task, error) {
+	task := args
+	task.ID = uuid.New()
+	task.OwnerID = actor.ID
+
+	if err := task.Budget.Validate(); err != nil {
+		return nil, err
+	}
+	if !task.Deadline.IsZero() && !task.Deadline.After(time.Now()) {
+		return nil, ErrDeadlineInPast
+	}
+
+	createdTask, err := ts.TaskRepo.Create(context.Background(), task)
+	if err != nil {
+		log.Printf("[ERROR] failed to create task for user %s: %v", actor.ID, err)
+		return nil, err
+	}
+	return createdTask, nil
+}
+
+// Get returns a task the actor may see. Private and draft tasks of other
+// users are reported as not found unless the actor may read them.
+func (ts *TaskService) Get(actor domain.Actor, taskID uuid.UUID) (*domain.Task, error) {
+	task, err := ts.TaskRepo.Read(context.Background(), taskID)
+	if err != nil {
+		return nil, err
+	}
+	if !canSeeTask(actor, task) {
+		return nil, repository.ErrTaskNotFound
+	}
+	return task, nil
+}
+
+// List returns a page of the tasks visible to the actor and the cursor of the
+// next page, which is empty when there are no more tasks.
+func (ts *TaskService) List(actor domain.Actor, filter domain.TaskFilter) ([]*domain.Task, string, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultTaskPageSize
+	}
+	if filter.Limit > maxTaskPageSize {
+		filter.Limit = maxTaskPageSize
+	}
+	pageSize := filter.Limit
+
+	// One extra row tells whether another page follows.
+	filter.Limit++
+	tasks, err := ts.TaskRepo.List(context.Background(), actor, filter)
+	if err != nil {
+		log.Printf("[ERROR] failed to list tasks: %v", err)
+		return nil, "", err
+	}
+
+	if len(tasks) <= pageSize {
+		return tasks, "", nil
+	}
+	tasks = tasks[:pageSize]
+	last := tasks[pageSize-1]
+	return tasks, helper.EncodeCursor(last.CreatedAt, last.ID), nil
+}
+
+// Search returns a page of the tasks visible to the actor that match the
+// search, and the cursor of the next page.
+func (ts *TaskService) Search(actor domain.Actor, search domain.TaskSearch) ([]*domain.Task, string, error) {
+	if search.Limit <= 0 {
+		search.Limit = defaultTaskPageSize
+	}
+	if search.Limit > maxTaskPageSize {
+		search.Limit = maxTaskPageSize
+	}
+	pageSize := search.Limit
+
+	// One extra row tells whether another page follows.
+	search.Limit++
+	tasks, err := ts.TaskRepo.Search(context.Background(), actor, search)
+	if err != nil {
+		log.Printf("[ERROR] failed to search tasks: %v", err)
+		return nil, "", err
+	}
+
+	if len(tasks) <= pageSize {
+		return tasks, "", nil
+	}
+	tasks = tasks[:pageSize]
+	last := tasks[pageSize-1]
+	return tasks, helper.EncodeCursor(last.CreatedAt, last.ID), nil
+}
+
+// Update applies a partial update to a task. Only the owner, or an actor
+// allowed to update any task, may change it.
+func (ts *TaskService) Update(actor domain.Actor, taskID uuid.UUID, update domain.TaskUpdate) (*domain.Task, error) {
+	if _, err := ts.authorize(actor, taskID, domain.PermissionTasksUpdate); err != nil {
+		return nil, err
+	}
+
+	if update.Budget != nil {
+		if err := update.Budget.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if update.Deadline != nil && !update.Deadline.After(time.Now()) {
+		return nil, ErrDeadlineInPast
+	}
+
+	task, err := ts.TaskRepo.Update(context.Background(), taskID, update)
+	if err != nil {
+		log.Printf("[ERROR] failed to update task %s: %v", taskID, err)
+		return nil, err
+	}
+	return task, nil
+}
+
+// Delete soft deletes a task. Only the owner, or an actor allowed to delete
+// any task, may delete it.
+func (ts *TaskService) Delete(actor domain.Actor, taskID uuid.UUID) error {
+	if _, err := ts.authorize(actor, taskID, domain.PermissionTasksDelete); err != nil {
+		return err
+	}
+
+	if err := ts.TaskRepo.Delete(context.Background(), taskID); err != nil {
+		log.Printf("[ERROR] failed to delete task %s: %v", taskID, err)
+		return err
+	}
+	return nil
+}
+
+// authorize loads the task and checks that the actor owns it or is granted
+// the permission to act on tasks of other users.
+func (ts *TaskService) authorize(actor domain.Actor, taskID uuid.UUID, permission domain.Permission) (*domain.Task, error) {
+	task, err := ts.Get(actor, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.OwnerID != actor.ID && !actor.Can(permission) {
+		return nil, ErrTaskForbidden
+	}
+	return task, nil
+}
+
+// Transition moves a task to a new status. The move must be allowed by the
+// task lifecycle for one of the parties the actor is to the task. Moving to
+// assigned needs an assignee, and moving back to open unassigns the task. A
+// task leaving open accepts the pending bid of its assignee, if any, and
+// rejects all other pending bids.
+func (ts *TaskService) Transition(actor domain.Actor, taskID uuid.UUID, to string, assigneeID uuid.UUID, reason string) (*domain.Task, error) {
+	if !domain.ValidTaskStatus(to) {
+		return nil, ErrInvalidTaskStatus
+	}
+
+	task, err := ts.Get(actor, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !domain.CanTransitionTask(task.Status, to) {
+		return nil, ErrInvalidTaskTransition
+	}
+	if !domain.TaskTransitionAllowed(task.Status, to, task.Parties(actor)) {
+		return nil, ErrTaskForbidden
+	}
+
+	switch to {
+	case domain.TaskStatusAssigned:
+		if assigneeID == uuid.Nil {
+			return nil, ErrAssigneeRequired
+		}
+		if assigneeID == task.OwnerID {
+			return nil, ErrInvalidAssignee
+		}
+	case domain.TaskStatusOpen, domain.TaskStatusDraft:
+		assigneeID = uuid.Nil
+	default:
+		assigneeID = task.AssigneeID
+	}
+
+	updatedTask, err := ts.TaskRepo.Transition(context.Background(), domain.TaskTransition{
+		TaskID:     task.ID,
+		From:       task.Status,
+		To:         to,
+		ActorID:    actor.ID,
+		AssigneeID: assigneeID,
+		Reason:     reason,
+	})
+	if err != nil {
+		log.Printf("[ERROR] failed to move task %s from %s to %s: %v", task.ID, task.Status, to, err)
+		return nil, err
+	}
+	return updatedTask, nil
+}
+
+func canSeeTask(actor domain.Actor, task *domain.Task) bool {
+	return (task.Public && task.Status != domain.TaskStatusDraft) || task.Parties(actor) != 0 || actor.Can(domain.PermissionTasksRead)
+}
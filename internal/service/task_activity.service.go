@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
+)
+
+type ActivityService struct {
+	TaskService  *TaskService
+	ActivityRepo repository.ActivityRepository
+}
+
+func NewActivityService(taskService *TaskService, activityRepo repository.ActivityRepository) *ActivityService {
+	if activityRepo == nil {
+		log.Fatalf("[FATAL] ActivityRepository cannot be nil")
+	}
+	return &ActivityService{
+		TaskService:  taskService,
+		ActivityRepo: activityRepo,
+	}
+}
+
+// List returns a page of the activity feed of a task the actor may see,
+// newest first, and the cursor of the next page. Like the bid listing, only
+// the owner and staff see the bids of every user.
+func (as *ActivityService) List(actor domain.Actor, taskID uuid.UUID, filter domain.ActivityFilter) ([]*domain.Activity, string, error) {
+	task, err := as.TaskService.Get(actor, taskID)
+	if err != nil {
+		return nil, "", err
+	}
+	filter.ViewerID = actor.ID
+	filter.AllBids = task.Parties(actor)&(domain.TaskPartyOwner|domain.TaskPartyStaff) != 0
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultTaskPageSize
+	}
+	if filter.Limit > maxTaskPageSize {
+		filter.Limit = maxTaskPageSize
+	}
+	pageSize := filter.Limit
+
+	// One extra row tells whether another page follows.
+	filter.Limit++
+	activity, err := as.ActivityRepo.ListByTask(context.Background(), task.ID, filter)
+	if err != nil {
+		log.Printf("[ERROR] failed to list activity of task %s: %v", task.ID, err)
+		return nil, "", err
+	}
+
+	if len(activity) <= pageSize {
+		return activity, "", nil
+	}
+	activity = activity[:pageSize]
+	last := activity[pageSize-1]
+	return activity, helper.EncodeCursor(last.CreatedAt, last.ID), nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/pkg/storage"
+	"github.com/vgrigalashvili/veemon/pkg/token"
+)
+
+var (
+	ErrInvalidAttachmentName = errors.New("attachment needs a file name of at most 255 bytes")
+)
+
+const (
+	maxAttachmentNameLength = 255
+	sniffLength             = 512 // http.DetectContentType looks at no more than this many bytes.
+)
+
+type AttachmentService struct {
+	TaskService    *TaskService
+	AttachmentRepo repository.AttachmentRepository
+	Blob           storage.Blob
+
+	Signer    *token.URLSigner
+	PublicURL string        // Base URL of download links.
+	LinkTTL   time.Duration // Lifetime of download links.
+}
+
+func NewAttachmentService(taskService *TaskService, attachmentRepo repository.AttachmentRepository, blob storage.Blob, signer *token.URLSigner, publicURL string, linkTTL time.Duration) *AttachmentService {
+	if attachmentRepo == nil || blob == nil || signer == nil {
+		log.Fatalf("[FATAL] AttachmentRepository, Blob and URLSigner cannot be nil")
+	}
+	return &AttachmentService{
+		TaskService:    taskService,
+		AttachmentRepo: attachmentRepo,
+		Blob:           blob,
+
+		Signer:    signer,
+		PublicURL: strings.TrimSuffix(publicURL, "/"),
+		LinkTTL:   linkTTL,
+	}
+}
+
+// Upload stores the content as a new attachment of the task. Only the owner,
+// the assignee or staff may attach files. The content type is sniffed from the
+// content, since the one sent by the client cannot be trusted.
+func (as *AttachmentService) Upload(actor domain.Actor, taskID uuid.UUID, name string, content io.Reader) (*domain.Attachment, error) {
+	task, err := as.TaskService.Get(actor, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Parties(actor) == 0 {
+		return nil, ErrTaskForbidden
+	}
+
+	name, err = cleanAttachmentName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// The content is spooled to a temporary file, so its size and checksum
+	// are known before it is handed to the storage backend.
+	spool, err := os.CreateTemp("", "veemon-attachment-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(spool, hash), content)
+	if err != nil {
+		log.Printf("[ERROR] failed to receive attachment for task %s: %v", taskID, err)
+		return nil, err
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := spool.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	attachment := domain.Attachment{
+		ID:          uuid.New(),
+		TaskID:      task.ID,
+		UploaderID:  actor.ID,
+		Name:        name,
+		ContentType: http.DetectContentType(head[:n]),
+		Size:        size,
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+	}
+	attachment.StorageKey = fmt.Sprintf("tasks/%s/%s", task.ID, attachment.ID)
+
+	ctx := context.Background()
+	if err := as.Blob.Put(ctx, attachment.StorageKey, spool, attachment.Size, attachment.ContentType); err != nil {
+		log.Printf("[ERROR] failed to store attachment %s: %v", attachment.StorageKey, err)
+		return nil, err
+	}
+
+	createdAttachment, err := as.AttachmentRepo.Create(ctx, attachment)
+	if err != nil {
+		log.Printf("[ERROR] failed to create attachment for task %s: %v", task.ID, err)
+		if err := as.Blob.Delete(ctx, attachment.StorageKey); err != nil {
+			log.Printf("[ERROR] failed to delete orphaned attachment %s: %v", attachment.StorageKey, err)
+		}
+		return nil, err
+	}
+	return createdAttachment, nil
+}
+
+// List returns the attachments of a task the actor may see, oldest first.
+func (as *AttachmentService) List(actor domain.Actor, taskID uuid.UUID) ([]*domain.Attachment, error) {
+	task, err := as.TaskService.Get(actor, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := as.AttachmentRepo.ListByTask(context.Background(), task.ID)
+	if err != nil {
+		log.Printf("[ERROR] failed to list attachments of task %s: %v", task.ID, err)
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// DownloadLink returns a signed link to download the attachment and the time
+// it expires. Links are only issued to actors that may see the attachment's
+// task; attachments of other tasks are reported as not found.
+func (as *AttachmentService) DownloadLink(actor domain.Actor, attachmentID uuid.UUID) (string, time.Time, error) {
+	attachment, err := as.AttachmentRepo.Read(context.Background(), attachmentID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if _, err := as.TaskService.Get(actor, attachment.TaskID); err != nil {
+		if errors.Is(err, repository.ErrTaskNotFound) {
+			return "", time.Time{}, repository.ErrAttachmentNotFound
+		}
+		return "", time.Time{}, err
+	}
+
+	expiresAt := time.Now().Add(as.LinkTTL).Truncate(time.Second)
+	link := fmt.Sprintf("%s/attachments/%s/download?expires=%d&signature=%s",
+		as.PublicURL, attachment.ID, expiresAt.Unix(), as.Signer.Sign(attachment.ID.String(), expiresAt))
+	return link, expiresAt, nil
+}
+
+// Open checks the signature of a download link and opens the content of its
+// attachment. The caller must close the content.
+func (as *AttachmentService) Open(attachmentID uuid.UUID, expires int64, signature string) (*domain.Attachment, io.ReadCloser, error) {
+	if err := as.Signer.Verify(attachmentID.String(), expires, signature); err != nil {
+		return nil, nil, err
+	}
+
+	ctx := context.Background()
+	attachment, err := as.AttachmentRepo.Read(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := as.Blob.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		log.Printf("[ERROR] failed to open attachment %s: %v", attachment.StorageKey, err)
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// cleanAttachmentName strips any directories from a client supplied file name.
+func cleanAttachmentName(name string) (string, error) {
+	name = strings.TrimSpace(path.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == ".." || name == "/" || len(name) > maxAttachmentNameLength {
+		return "", ErrInvalidAttachmentName
+	}
+	return name, nil
+}
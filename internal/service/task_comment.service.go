@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
+)
+
+var (
+	ErrEmptyComment         = errors.New("comment must not be empty")
+	ErrInvalidParentComment = errors.New("parent comment not found on this task")
+	ErrCommentForbidden     = errors.New("not allowed to delete this comment")
+)
+
+type CommentService struct {
+	TaskService *TaskService
+	CommentRepo repository.CommentRepository
+}
+
+func NewCommentService(taskService *TaskService, commentRepo repository.CommentRepository) *CommentService {
+	if commentRepo == nil {
+		log.Fatalf("[FATAL] CommentRepository cannot be nil")
+	}
+	return &CommentService{
+		TaskService: taskService,
+		CommentRepo: commentRepo,
+	}
+}
+
+// Create adds a comment of the actor to a task the actor may see. A comment
+// with a parent is a reply, and the parent must be a comment on the same task.
+func (cs *CommentService) Create(actor domain.Actor, taskID, parentID uuid.UUID, body string) (*domain.Comment, error) {
+	task, err := cs.TaskService.Get(actor, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, ErrEmptyComment
+	}
+
+	if parentID != uuid.Nil {
+		parent, err := cs.CommentRepo.Read(context.Background(), parentID)
+		if err != nil {
+			if errors.Is(err, repository.ErrCommentNotFound) {
+				return nil, ErrInvalidParentComment
+			}
+			return nil, err
+		}
+		if parent.TaskID != task.ID {
+			return nil, ErrInvalidParentComment
+		}
+	}
+
+	comment, err := cs.CommentRepo.Create(context.Background(), domain.Comment{
+		ID:       uuid.New(),
+		TaskID:   task.ID,
+		AuthorID: actor.ID,
+		ParentID: parentID,
+		Body:     body,
+	})
+	if err != nil {
+		log.Printf("[ERROR] failed to create comment on task %s: %v", task.ID, err)
+		return nil, err
+	}
+	return comment, nil
+}
+
+// List returns a page of the comments on a task the actor may see, oldest
+// first, and the cursor of the next page. Deleted comments keep their place
+// so their replies stay threaded, but lose their body.
+func (cs *CommentService) List(actor domain.Actor, taskID uuid.UUID, filter domain.CommentFilter) ([]*domain.Comment, string, error) {
+	task, err := cs.TaskService.Get(actor, taskID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultTaskPageSize
+	}
+	if filter.Limit > maxTaskPageSize {
+		filter.Limit = maxTaskPageSize
+	}
+	pageSize := filter.Limit
+
+	// One extra row tells whether another page follows.
+	filter.Limit++
+	comments, err := cs.CommentRepo.ListByTask(context.Background(), task.ID, filter)
+	if err != nil {
+		log.Printf("[ERROR] failed to list comments on task %s: %v", task.ID, err)
+		return nil, "", err
+	}
+	for _, comment := range comments {
+		if !comment.DeletedAt.IsZero() {
+			comment.Body = ""
+		}
+	}
+
+	if len(comments) <= pageSize {
+		return comments, "", nil
+	}
+	comments = comments[:pageSize]
+	last := comments[pageSize-1]
+	return comments, helper.EncodeCursor(last.CreatedAt, last.ID), nil
+}
+
+// Delete soft deletes a comment. Only its author or staff may delete it.
+func (cs *CommentService) Delete(actor domain.Actor, taskID, commentID uuid.UUID) error {
+	task, err := cs.TaskService.Get(actor, taskID)
+	if err != nil {
+		return err
+	}
+
+	comment, err := cs.CommentRepo.Read(context.Background(), commentID)
+	if err != nil {
+		return err
+	}
+	if comment.TaskID != task.ID {
+		return repository.ErrCommentNotFound
+	}
+	if comment.AuthorID != actor.ID && task.Parties(actor)&domain.TaskPartyStaff == 0 {
+		return ErrCommentForbidden
+	}
+
+	if err := cs.CommentRepo.Delete(context.Background(), comment.ID); err != nil {
+		log.Printf("[ERROR] failed to delete comment %s: %v", comment.ID, err)
+		return err
+	}
+	return nil
+}
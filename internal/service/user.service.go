@@ -2,55 +2,126 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
 
 	"github.com/vgrigalashvili/veemon/internal/domain"
 	"github.com/vgrigalashvili/veemon/internal/repository"
 	"github.com/vgrigalashvili/veemon/pkg/helper"
+	"github.com/vgrigalashvili/veemon/pkg/token"
+	"github.com/vgrigalashvili/veemon/pkg/worker"
+)
+
+var (
+	ErrInvalidRole             = errors.New("invalid role")
+	ErrCurrentPasswordRequired = errors.New("current password is required to change the email or password")
+	ErrWrongCurrentPassword    = errors.New("current password is incorrect")
+)
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
 )
 
 type UserService struct {
-	UserRepo repository.UserRepository
+	UserRepo        repository.UserRepository
+	PasswordPolicy  helper.PasswordPolicy
+	TaskDistributor worker.TaskDistributor
+	Denylist        token.Denylist
+
+	DefaultCallingCode string
 }
 
-func NewUserService(userRepo repository.UserRepository) *UserService {
+func NewUserService(userRepo repository.UserRepository, passwordPolicy helper.PasswordPolicy, taskDistributor worker.TaskDistributor, denylist token.Denylist, defaultCallingCode string) *UserService {
 	if userRepo == nil {
 		log.Fatalf("[FATAL] UserRepository cannot be nil")
 	}
-	return &UserService{UserRepo: userRepo}
+	return &UserService{
+		UserRepo:        userRepo,
+		PasswordPolicy:  passwordPolicy,
+		TaskDistributor: taskDistributor,
+		Denylist:        denylist,
+
+		DefaultCallingCode: defaultCallingCode,
+	}
 }
+
+// Create creates a user with the password in args. If no password is given,
+// a random one is stored and the user receives a one-time link by email to
+// choose their own.
 func (us *UserService) Create(args domain.User) (string, error) {
-	if us.UserRepo == nil {
-		log.Printf("[ERROR] UserRepo is not initialized")
-		return "", fmt.Errorf("UserRepo is not initialized")
+	if args.Password != "" {
+		return us.CreateWithHook(args, nil)
 	}
 
-	password, err := helper.GeneratePassword()
-	log.Printf("[INFO]: Generating password %v", password)
+	password, err := helper.GeneratePassword(us.PasswordPolicy.MinLength)
 	if err != nil {
 		log.Printf("[ERROR] Failed to generate password: %v", err)
 		return "", fmt.Errorf("failed to generate password: %w", err)
 	}
+	args.Password = password
 
-	// expiry := time.Now().AddDate(0, 1, 0)
-	hashedPassword, err := helper.HashPassword(password)
+	// The generated password is never shown to anyone; the set-password
+	// email is enqueued in the user transaction instead.
+	return us.CreateWithHook(args, func(user *domain.User) error {
+		payload := &worker.PayloadSendResetPassword{
+			UserID:  user.ID,
+			Email:   user.Email,
+			Welcome: true,
+		}
+		opts := []asynq.Option{
+			asynq.MaxRetry(10),
+			asynq.ProcessIn(10 * time.Second),
+			asynq.Queue(worker.QueueCritical),
+		}
+		return us.TaskDistributor.DistributeTaskSendResetPassword(context.Background(), payload, opts...)
+	})
+}
+
+// CreateWithHook creates the user with the password in args and calls
+// afterCreate in the same transaction, so the user is not stored if
+// afterCreate fails.
+func (us *UserService) CreateWithHook(args domain.User, afterCreate func(user *domain.User) error) (string, error) {
+	if us.UserRepo == nil {
+		log.Printf("[ERROR] UserRepo is not initialized")
+		return "", fmt.Errorf("UserRepo is not initialized")
+	}
+
+	hashedPassword, err := us.HashNewPassword(args.Password)
 	if err != nil {
-		log.Printf("[ERROR] Failed to hash password: %v", err)
-		return "", fmt.Errorf("failed to hash the password: %w", err)
+		return "", err
 	}
 
 	user := args
 
+	if user.Mobile != "" {
+		mobile, err := helper.NormalizeMobile(user.Mobile, us.DefaultCallingCode)
+		if err != nil {
+			return "", err
+		}
+		user.Mobile = mobile
+	}
+
 	user.ID = uuid.New()
 	user.Password = hashedPassword
-	user.Role = "backend-developer"
+	if user.Role == "" {
+		user.Role = domain.RoleUser
+	}
+	if !domain.ValidRole(user.Role) {
+		return "", ErrInvalidRole
+	}
 
-	log.Printf("[INFO] %v", user)
-	log.Printf("[DEBUG] User entity: %+v", user)
-	createdUser, err := us.UserRepo.Create(context.Background(), user)
+	var createdUser *domain.User
+	if afterCreate != nil {
+		createdUser, err = us.UserRepo.CreateTx(context.Background(), user, afterCreate)
+	} else {
+		createdUser, err = us.UserRepo.Create(context.Background(), user)
+	}
 	if err != nil {
 		log.Printf("[ERROR - UserService] Failed to add user to the database: %v", err)
 		return "", err
@@ -59,6 +130,21 @@ func (us *UserService) Create(args domain.User) (string, error) {
 	return createdUser.ID.String(), nil
 }
 
+// HashNewPassword checks a new password against the password policy and
+// returns its bcrypt hash.
+func (us *UserService) HashNewPassword(password string) (string, error) {
+	if err := us.PasswordPolicy.Validate(password); err != nil {
+		return "", err
+	}
+
+	hashedPassword, err := helper.HashPassword(password)
+	if err != nil {
+		log.Printf("[ERROR] Failed to hash password: %v", err)
+		return "", fmt.Errorf("failed to hash the password: %w", err)
+	}
+	return hashedPassword, nil
+}
+
 func (us *UserService) GetBID(userID uuid.UUID) (*domain.User, error) {
 	if us.UserRepo == nil {
 		log.Printf("[ERROR] UserRepo is not initialized")
@@ -71,3 +157,138 @@ func (us *UserService) GetBID(userID uuid.UUID) (*domain.User, error) {
 	}
 	return user, nil
 }
+
+func (us *UserService) GetByEmail(email string) (*domain.User, error) {
+	if us.UserRepo == nil {
+		log.Printf("[ERROR] UserRepo is not initialized")
+		return nil, fmt.Errorf("UserRepo is not initialized")
+	}
+	user, err := us.UserRepo.ReadByEmail(context.Background(), email)
+	if err != nil {
+		if !errors.Is(err, repository.ErrUserNotFound) {
+			log.Printf("[ERROR] failed to read user by email: %v", err)
+		}
+		return nil, fmt.Errorf("could not find user by email: %w", err)
+	}
+	return user, nil
+}
+
+// Update applies a partial update to the user. A new password is checked
+// against the password policy, a new mobile is stored in E.164 format, and a
+// new email must be verified again. Users changing their own email or
+// password must confirm their current password. A password or role change
+// signs the user out everywhere, since access tokens carry the role.
+func (us *UserService) Update(actor domain.Actor, userID uuid.UUID, update domain.UserUpdate, currentPassword string) (*domain.User, error) {
+	if update.Role != nil && !domain.ValidRole(*update.Role) {
+		return nil, ErrInvalidRole
+	}
+
+	confirm := actor.ID == userID && (update.Email != nil || update.Password != nil)
+	if confirm && currentPassword == "" {
+		return nil, ErrCurrentPasswordRequired
+	}
+
+	signOut := update.Password != nil
+	if confirm || update.Role != nil {
+		existing, err := us.UserRepo.Read(context.Background(), userID)
+		if err != nil {
+			return nil, err
+		}
+		if confirm {
+			if err := helper.CheckPassword(existing.Password, currentPassword); err != nil {
+				return nil, ErrWrongCurrentPassword
+			}
+		}
+		if update.Role != nil && *update.Role != existing.Role {
+			signOut = true
+		}
+	}
+
+	if update.Email != nil {
+		email := helper.NormalizeEmail(*update.Email)
+		update.Email = &email
+	}
+
+	if update.Mobile != nil {
+		mobile, err := helper.NormalizeMobile(*update.Mobile, us.DefaultCallingCode)
+		if err != nil {
+			return nil, err
+		}
+		update.Mobile = &mobile
+	}
+
+	if update.Password != nil {
+		hashedPassword, err := us.HashNewPassword(*update.Password)
+		if err != nil {
+			return nil, err
+		}
+		update.Password = &hashedPassword
+	}
+
+	user, err := us.UserRepo.Update(context.Background(), userID, update, signOut)
+	if err != nil {
+		log.Printf("[ERROR] failed to update user %s: %v", userID, err)
+		return nil, err
+	}
+
+	// The repository revoked the sessions along with the update.
+	if signOut {
+		if err := us.Denylist.RevokeUser(context.Background(), userID); err != nil {
+			log.Printf("[ERROR] failed to revoke tokens of user %s after a credential or role change: %v", userID, err)
+			return nil, err
+		}
+	}
+
+	if update.Email != nil && !user.Email_verified {
+		payload := &worker.PayloadSendVerifyEmail{
+			UserID: user.ID,
+			Email:  user.Email,
+		}
+		if err := us.TaskDistributor.DistributeTaskSendVerifyEmail(context.Background(), payload, asynq.MaxRetry(10), asynq.Queue(worker.QueueCritical)); err != nil {
+			log.Printf("[ERROR] failed to enqueue verification email for user %s: %v", user.ID, err)
+		}
+	}
+
+	return user, nil
+}
+
+// Delete soft deletes the user and revokes all of its sessions and tokens.
+func (us *UserService) Delete(userID uuid.UUID) error {
+	if err := us.UserRepo.Delete(context.Background(), userID); err != nil {
+		log.Printf("[ERROR] failed to delete user %s: %v", userID, err)
+		return err
+	}
+
+	if err := us.Denylist.RevokeUser(context.Background(), userID); err != nil {
+		log.Printf("[ERROR] failed to revoke tokens of deleted user %s: %v", userID, err)
+		return err
+	}
+	return nil
+}
+
+// List returns a page of users matching the filter and the cursor of the next
+// page, which is empty when there are no more users.
+func (us *UserService) List(filter domain.UserFilter) ([]*domain.User, string, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultUserPageSize
+	}
+	if filter.Limit > maxUserPageSize {
+		filter.Limit = maxUserPageSize
+	}
+	pageSize := filter.Limit
+
+	// One extra row tells whether another page follows.
+	filter.Limit++
+	users, err := us.UserRepo.List(context.Background(), filter)
+	if err != nil {
+		log.Printf("[ERROR] failed to list users: %v", err)
+		return nil, "", err
+	}
+
+	if len(users) <= pageSize {
+		return users, "", nil
+	}
+	users = users[:pageSize]
+	last := users[pageSize-1]
+	return users, helper.EncodeCursor(last.CreatedAt, last.ID), nil
+}
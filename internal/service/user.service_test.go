@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/vgrigalashvili/veemon/internal/domain"
+	"github.com/vgrigalashvili/veemon/internal/repository"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
+	"github.com/vgrigalashvili/veemon/pkg/token"
+)
+
+// fakeUserRepo keeps users in memory and records whether an update revoked
+// the sessions of the user.
+type fakeUserRepo struct {
+	repository.UserRepository
+	users          map[uuid.UUID]*domain.User
+	revokedUserIDs []uuid.UUID
+}
+
+func (r *fakeUserRepo) Read(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeUserRepo) Update(ctx context.Context, id uuid.UUID, update domain.UserUpdate, revokeSessions bool) (*domain.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	if update.FirstName != nil {
+		user.FirstName = *update.FirstName
+	}
+	if update.Password != nil {
+		user.Password = *update.Password
+	}
+	if update.Role != nil {
+		user.Role = *update.Role
+	}
+	if revokeSessions {
+		r.revokedUserIDs = append(r.revokedUserIDs, id)
+	}
+	copied := *user
+	return &copied, nil
+}
+
+// fakeDenylist records the revoked tokens and users.
+type fakeDenylist struct {
+	tokens []uuid.UUID
+	users  []uuid.UUID
+}
+
+func (d *fakeDenylist) RevokeToken(ctx context.Context, payload *token.Payload) error {
+	d.tokens = append(d.tokens, payload.TokenID)
+	return nil
+}
+
+func (d *fakeDenylist) RevokeUser(ctx context.Context, userID uuid.UUID) error {
+	d.users = append(d.users, userID)
+	return nil
+}
+
+func (d *fakeDenylist) IsRevoked(ctx context.Context, payload *token.Payload) (bool, error) {
+	for _, id := range d.tokens {
+		if id == payload.TokenID {
+			return true, nil
+		}
+	}
+	for _, id := range d.users {
+		if id == payload.UserID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestUserServiceUpdateSignsOut(t *testing.T) {
+	const currentPassword = "Current-Passw0rd!"
+	hashedPassword, err := helper.HashPassword(currentPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adminID := uuid.New()
+	admin := domain.Actor{ID: adminID, Role: domain.RoleAdmin}
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name            string
+		actor           func(userID uuid.UUID) domain.Actor
+		role            string
+		update          domain.UserUpdate
+		currentPassword string
+		wantErr         error
+		wantSignOut     bool
+	}{
+		{
+			name:        "demoted admin is signed out",
+			actor:       func(uuid.UUID) domain.Actor { return admin },
+			role:        domain.RoleAdmin,
+			update:      domain.UserUpdate{Role: str(domain.RoleUser)},
+			wantSignOut: true,
+		},
+		{
+			name:        "promoted user is signed out",
+			actor:       func(uuid.UUID) domain.Actor { return admin },
+			role:        domain.RoleUser,
+			update:      domain.UserUpdate{Role: str(domain.RoleOperator)},
+			wantSignOut: true,
+		},
+		{
+			name:   "unchanged role keeps sessions",
+			actor:  func(uuid.UUID) domain.Actor { return admin },
+			role:   domain.RoleOperator,
+			update: domain.UserUpdate{Role: str(domain.RoleOperator)},
+		},
+		{
+			name:   "name change keeps sessions",
+			actor:  func(id uuid.UUID) domain.Actor { return domain.Actor{ID: id, Role: domain.RoleUser} },
+			role:   domain.RoleUser,
+			update: domain.UserUpdate{FirstName: str("Nino")},
+		},
+		{
+			name:            "own password change signs out",
+			actor:           func(id uuid.UUID) domain.Actor { return domain.Actor{ID: id, Role: domain.RoleUser} },
+			role:            domain.RoleUser,
+			update:          domain.UserUpdate{Password: str("New-Passw0rd-2024")},
+			currentPassword: currentPassword,
+			wantSignOut:     true,
+		},
+		{
+			name:    "own password change needs the current password",
+			actor:   func(id uuid.UUID) domain.Actor { return domain.Actor{ID: id, Role: domain.RoleUser} },
+			role:    domain.RoleUser,
+			update:  domain.UserUpdate{Password: str("New-Passw0rd-2024")},
+			wantErr: ErrCurrentPasswordRequired,
+		},
+		{
+			name:            "own password change with a wrong current password",
+			actor:           func(id uuid.UUID) domain.Actor { return domain.Actor{ID: id, Role: domain.RoleUser} },
+			role:            domain.RoleUser,
+			update:          domain.UserUpdate{Password: str("New-Passw0rd-2024")},
+			currentPassword: "wrong",
+			wantErr:         ErrWrongCurrentPassword,
+		},
+		{
+			name:    "invalid role",
+			actor:   func(uuid.UUID) domain.Actor { return admin },
+			role:    domain.RoleUser,
+			update:  domain.UserUpdate{Role: str("root")},
+			wantErr: ErrInvalidRole,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			users := &fakeUserRepo{users: map[uuid.UUID]*domain.User{
+				userID: {ID: userID, Email: "nino@example.com", Password: hashedPassword, Role: tt.role},
+			}}
+			denylist := &fakeDenylist{}
+			us := &UserService{
+				UserRepo:       users,
+				PasswordPolicy: helper.PasswordPolicy{MinLength: 10, MinCharClasses: 3},
+				Denylist:       denylist,
+			}
+
+			_, err := us.Update(tt.actor(userID), userID, tt.update, tt.currentPassword)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Update() error = %v, want %v", err, tt.wantErr)
+			}
+
+			sessionsRevoked := len(users.revokedUserIDs) == 1 && users.revokedUserIDs[0] == userID
+			tokensRevoked := len(denylist.users) == 1 && denylist.users[0] == userID
+			if sessionsRevoked != tt.wantSignOut {
+				t.Errorf("sessions revoked = %v, want %v", sessionsRevoked, tt.wantSignOut)
+			}
+			if tokensRevoked != tt.wantSignOut {
+				t.Errorf("tokens revoked = %v, want %v", tokensRevoked, tt.wantSignOut)
+			}
+		})
+	}
+}
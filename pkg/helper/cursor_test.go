@@ -0,0 +1,85 @@
+package helper
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	id := uuid.MustParse("3f0c7c1e-6a8e-4a7e-9b1e-2d1f0c9a8b7d")
+	tests := []struct {
+		name      string
+		createdAt time.Time
+	}{
+		{"utc", time.Date(2024, 5, 17, 9, 30, 0, 0, time.UTC)},
+		{"nanoseconds", time.Date(2024, 5, 17, 9, 30, 0, 123456789, time.UTC)},
+		{"other zone", time.Date(2024, 5, 17, 13, 30, 0, 0, time.FixedZone("GET", 4*60*60))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := EncodeCursor(tt.createdAt, id)
+			createdAt, gotID, err := DecodeCursor(cursor)
+			if err != nil {
+				t.Fatalf("DecodeCursor(%q) error = %v", cursor, err)
+			}
+			if !createdAt.Equal(tt.createdAt) {
+				t.Errorf("DecodeCursor() created at = %s, want %s", createdAt, tt.createdAt)
+			}
+			if gotID != id {
+				t.Errorf("DecodeCursor() ID = %s, want %s", gotID, id)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejectsInvalid(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"empty", ""},
+		{"not base64", "!!!"},
+		{"no separator", encode("2024-05-17T09:30:00Z")},
+		{"bad time", encode("yesterday|3f0c7c1e-6a8e-4a7e-9b1e-2d1f0c9a8b7d")},
+		{"bad id", encode("2024-05-17T09:30:00Z|42")},
+		{"swapped parts", encode("3f0c7c1e-6a8e-4a7e-9b1e-2d1f0c9a8b7d|2024-05-17T09:30:00Z")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := DecodeCursor(tt.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor(%q) error = %v, want %v", tt.cursor, err, ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"lift", "lift"},
+		{"100%", `100\%`},
+		{"first_name", `first\_name`},
+		{`C:\dir`, `C:\\dir`},
+		{`%_\`, `\%\_\\`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := EscapeLike(tt.in); got != tt.want {
+				t.Errorf("EscapeLike(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
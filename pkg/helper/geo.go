@@ -0,0 +1,37 @@
+package helper
+
+import "math"
+
+const (
+	earthRadiusKm = 6371.0
+	kmPerDegree   = math.Pi * earthRadiusKm / 180
+)
+
+// BoundingBox returns the latitude and longitude range that contains every
+// point within radiusKm of the center. It is a cheap, index friendly pre-filter
+// for an exact great-circle distance check. Near the poles or the antimeridian
+// the full longitude range is returned.
+func BoundingBox(lat, lng, radiusKm float64) (minLat, maxLat, minLng, maxLng float64) {
+	latDelta := radiusKm / kmPerDegree
+	minLat = math.Max(lat-latDelta, -90)
+	maxLat = math.Min(lat+latDelta, 90)
+
+	if minLat == -90 || maxLat == 90 {
+		return minLat, maxLat, -180, 180
+	}
+
+	// The circle is widest in longitude where a meridian touches it, not at
+	// the latitude of the center, so the longitude range follows from the
+	// angular radius. Close to a pole the circle may reach round it.
+	sinLngDelta := math.Sin(radiusKm/earthRadiusKm) / math.Cos(lat*math.Pi/180)
+	if sinLngDelta >= 1 {
+		return minLat, maxLat, -180, 180
+	}
+	lngDelta := math.Asin(sinLngDelta) * 180 / math.Pi
+	minLng = lng - lngDelta
+	maxLng = lng + lngDelta
+	if minLng < -180 || maxLng > 180 {
+		return minLat, maxLat, -180, 180
+	}
+	return minLat, maxLat, minLng, maxLng
+}
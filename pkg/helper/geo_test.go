@@ -0,0 +1,80 @@
+package helper
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBoundingBox(t *testing.T) {
+	const tolerance = 1e-6
+
+	tests := []struct {
+		name                               string
+		lat, lng, radiusKm                 float64
+		minLat, maxLat, minLng, maxLng     float64
+		wholeLongitude, checkLongitudeSpan bool
+	}{
+		{
+			name: "equator", lat: 0, lng: 0, radiusKm: kmPerDegree,
+			minLat: -1, maxLat: 1, minLng: -1, maxLng: 1,
+		},
+		{
+			name: "tbilisi", lat: 41.7151, lng: 44.8271, radiusKm: 10,
+			minLat: 41.7151 - 10/kmPerDegree, maxLat: 41.7151 + 10/kmPerDegree,
+			checkLongitudeSpan: true,
+		},
+		{
+			// The widest points of the circle lie north of the center, at
+			// exactly 500 km from it.
+			name: "high latitude", lat: 70, lng: 25, radiusKm: 500,
+			minLat: 70 - 500/kmPerDegree, maxLat: 70 + 500/kmPerDegree,
+			minLng: 11.748463719, maxLng: 38.251536281,
+		},
+		{
+			name: "zero radius", lat: 41.7151, lng: 44.8271, radiusKm: 0,
+			minLat: 41.7151, maxLat: 41.7151, minLng: 44.8271, maxLng: 44.8271,
+		},
+		{
+			name: "reaches north pole", lat: 89.9, lng: 10, radiusKm: 50,
+			minLat: 89.9 - 50/kmPerDegree, maxLat: 90, wholeLongitude: true,
+		},
+		{
+			name: "reaches south pole", lat: -89.9, lng: 10, radiusKm: 50,
+			minLat: -90, maxLat: -89.9 + 50/kmPerDegree, wholeLongitude: true,
+		},
+		{
+			name: "crosses antimeridian", lat: 0, lng: 179.9, radiusKm: 50,
+			minLat: -50 / kmPerDegree, maxLat: 50 / kmPerDegree, wholeLongitude: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minLat, maxLat, minLng, maxLng := BoundingBox(tt.lat, tt.lng, tt.radiusKm)
+
+			if math.Abs(minLat-tt.minLat) > tolerance || math.Abs(maxLat-tt.maxLat) > tolerance {
+				t.Errorf("latitude range = [%f, %f], want [%f, %f]", minLat, maxLat, tt.minLat, tt.maxLat)
+			}
+
+			switch {
+			case tt.wholeLongitude:
+				if minLng != -180 || maxLng != 180 {
+					t.Errorf("longitude range = [%f, %f], want [-180, 180]", minLng, maxLng)
+				}
+			case tt.checkLongitudeSpan:
+				// Degrees of longitude shrink with the cosine of the latitude,
+				// so the box is wider in degrees than it is tall.
+				if minLng >= tt.lng || maxLng <= tt.lng {
+					t.Errorf("longitude range [%f, %f] does not contain %f", minLng, maxLng, tt.lng)
+				}
+				if maxLng-minLng <= maxLat-minLat {
+					t.Errorf("longitude span %f is not wider than latitude span %f", maxLng-minLng, maxLat-minLat)
+				}
+			default:
+				if math.Abs(minLng-tt.minLng) > tolerance || math.Abs(maxLng-tt.maxLng) > tolerance {
+					t.Errorf("longitude range = [%f, %f], want [%f, %f]", minLng, maxLng, tt.minLng, tt.maxLng)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package helper
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidMobile = errors.New("invalid mobile number")
+
+var e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// NormalizeMobile returns the mobile number in E.164 format, e.g. +995555123456.
+// Spaces, dashes, dots and parentheses are ignored, a leading 00 is read as
+// the international prefix, and numbers without one get defaultCallingCode.
+func NormalizeMobile(mobile, defaultCallingCode string) (string, error) {
+	cleaned := strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "").Replace(strings.TrimSpace(mobile))
+
+	switch {
+	case strings.HasPrefix(cleaned, "+"):
+	case strings.HasPrefix(cleaned, "00"):
+		cleaned = "+" + strings.TrimPrefix(cleaned, "00")
+	default:
+		cleaned = "+" + strings.TrimPrefix(defaultCallingCode, "+") + strings.TrimPrefix(cleaned, "0")
+	}
+
+	if !e164Pattern.MatchString(cleaned) {
+		return "", ErrInvalidMobile
+	}
+	return cleaned, nil
+}
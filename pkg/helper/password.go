@@ -20,14 +20,16 @@ const (
 	// Combined set of all character types for general password generation.
 	allChars = lowercase + uppercase + digits + special
 
-	// Default length for generated passwords.
-	passwordLength = 6 // Minimum length for passwords generated by the function.
+	// Minimum length for generated passwords.
+	passwordLength = 16 // Length of passwords generated by the function unless a longer one is requested.
 )
 
 // GeneratePassword generates a random password that includes lowercase, uppercase, digits, and special characters.
+// The password is minLength characters long, but never shorter than passwordLength.
 // Returns the generated password or an error if any random operation fails.
-func GeneratePassword() (string, error) {
-	password := make([]byte, passwordLength)
+func GeneratePassword(minLength int) (string, error) {
+	length := max(minLength, passwordLength)
+	password := make([]byte, length)
 
 	charSets := []string{lowercase, uppercase, digits, special}
 	for i := 0; i < len(charSets); i++ {
@@ -39,7 +41,7 @@ func GeneratePassword() (string, error) {
 		password[i] = char
 	}
 
-	for i := len(charSets); i < passwordLength; i++ {
+	for i := len(charSets); i < length; i++ {
 		char, err := randomCharFromSet(allChars)
 		if err != nil {
 			log.Printf("[ERROR] error generating random character from allChars, error: %v", err)
@@ -0,0 +1,80 @@
+package helper
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrWeakPassword is wrapped by every error returned from PasswordPolicy.Validate.
+var ErrWeakPassword = errors.New("password does not meet the password policy")
+
+//go:embed breachedPasswords.txt
+var breachedPasswordList string
+
+// breachedPasswords holds the lowercased entries of breachedPasswords.txt.
+var breachedPasswords = parseBreachedPasswords(breachedPasswordList)
+
+// PasswordPolicy describes the requirements for user chosen passwords.
+type PasswordPolicy struct {
+	MinLength      int // Minimum number of characters.
+	MinCharClasses int // Minimum number of character classes (lowercase, uppercase, digits, special) used.
+}
+
+// Validate checks the password against the policy and the bundled list of breached passwords.
+// Returns an error wrapping ErrWeakPassword that describes the first unmet requirement.
+func (p PasswordPolicy) Validate(password string) error {
+	if len([]rune(password)) < p.MinLength {
+		return fmt.Errorf("%w: must be at least %d characters long", ErrWeakPassword, p.MinLength)
+	}
+
+	if classes := countCharClasses(password); classes < p.MinCharClasses {
+		return fmt.Errorf("%w: must contain at least %d of lowercase letters, uppercase letters, digits and special characters", ErrWeakPassword, p.MinCharClasses)
+	}
+
+	if _, found := breachedPasswords[strings.ToLower(password)]; found {
+		return fmt.Errorf("%w: appears in a list of breached passwords", ErrWeakPassword)
+	}
+
+	return nil
+}
+
+// countCharClasses returns how many character classes the password uses.
+func countCharClasses(password string) int {
+	var lower, upper, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	count := 0
+	for _, used := range []bool{lower, upper, digit, other} {
+		if used {
+			count++
+		}
+	}
+	return count
+}
+
+// parseBreachedPasswords builds a lookup set from a newline separated list, skipping blank lines and # comments.
+func parseBreachedPasswords(list string) map[string]struct{} {
+	passwords := make(map[string]struct{})
+	for _, line := range strings.Split(list, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords[strings.ToLower(line)] = struct{}{}
+	}
+	return passwords
+}
@@ -5,12 +5,14 @@ import (
 	"math/rand"
 	"strings"
 	"time"
+
+	"github.com/vgrigalashvili/veemon/pkg/money"
 )
 
 const (
-	GEL = "GEL"
-	USD = "USD"
-	EUR = "EUR"
+	GEL = string(money.GEL)
+	USD = string(money.USD)
+	EUR = string(money.EUR)
 )
 const alphabet = "abcdefghijklmnopqrstuvwxyz"
 
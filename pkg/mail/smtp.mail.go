@@ -4,8 +4,12 @@ import (
 	"context"
 	"fmt"
 	"net/smtp"
+	"strings"
 )
 
+// headerBreaks removes line breaks that would let a value start a new header.
+var headerBreaks = strings.NewReplacer("\r", " ", "\n", " ")
+
 type SMTPMailer struct {
 	host     string
 	port     string
@@ -30,7 +34,7 @@ func (s *SMTPMailer) SendEmail(ctx context.Context, to []string, subject, body s
 	auth := smtp.PlainAuth("", s.username, s.password, s.host)
 	addr := fmt.Sprintf("%s:%s", s.host, s.port)
 
-	msg := []byte(fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s", s.from, to, subject, body))
+	msg := []byte(fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s", s.from, strings.Join(to, ", "), headerBreaks.Replace(subject), body))
 
 	select {
 	case <-ctx.Done():
@@ -0,0 +1,122 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrNegativeAmount      = errors.New("amount cannot be negative")
+	ErrCurrencyMismatch    = errors.New("currencies do not match")
+)
+
+// Currency is an ISO 4217 currency code.
+type Currency string
+
+const (
+	GEL Currency = "GEL"
+	USD Currency = "USD"
+	EUR Currency = "EUR"
+)
+
+// minorUnits holds the supported currencies and the number of minor units
+// (tetri, cents) in one major unit as a power of ten.
+var minorUnits = map[Currency]int{
+	GEL: 2,
+	USD: 2,
+	EUR: 2,
+}
+
+// ParseCurrency returns the supported currency with the given code, ignoring case.
+func ParseCurrency(code string) (Currency, error) {
+	currency := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if !currency.Valid() {
+		return "", ErrUnsupportedCurrency
+	}
+	return currency, nil
+}
+
+// Valid reports whether the currency is supported.
+func (c Currency) Valid() bool {
+	_, ok := minorUnits[c]
+	return ok
+}
+
+func (c *Currency) UnmarshalJSON(data []byte) error {
+	var code string
+	if err := json.Unmarshal(data, &code); err != nil {
+		return err
+	}
+	currency, err := ParseCurrency(code)
+	if err != nil {
+		return fmt.Errorf("%w: %q", err, code)
+	}
+	*c = currency
+	return nil
+}
+
+// Money is an amount in the minor units of a currency, e.g. 1050 GEL is 10.50 lari.
+// Amounts are integers so no precision is lost to floating point.
+type Money struct {
+	Amount   int64    `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+// New returns a validated amount of money.
+func New(amount int64, currency Currency) (Money, error) {
+	m := Money{Amount: amount, Currency: currency}
+	if err := m.Validate(); err != nil {
+		return Money{}, err
+	}
+	return m, nil
+}
+
+// Validate checks that the currency is supported and the amount is not negative.
+func (m Money) Validate() error {
+	if !m.Currency.Valid() {
+		return ErrUnsupportedCurrency
+	}
+	if m.Amount < 0 {
+		return ErrNegativeAmount
+	}
+	return nil
+}
+
+// Compare returns -1, 0 or +1 depending on whether m is less than, equal to or
+// greater than other. Both must be in the same currency.
+func (m Money) Compare(other Money) (int, error) {
+	if m.Currency != other.Currency {
+		return 0, ErrCurrencyMismatch
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// String formats the amount in major units, e.g. "10.50 GEL".
+func (m Money) String() string {
+	units := minorUnits[m.Currency]
+	if units == 0 {
+		return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+	}
+
+	divisor := int64(1)
+	for i := 0; i < units; i++ {
+		divisor *= 10
+	}
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/divisor, units, amount%divisor, m.Currency)
+}
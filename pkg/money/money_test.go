@@ -0,0 +1,151 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseCurrency(t *testing.T) {
+	tests := []struct {
+		code    string
+		want    Currency
+		wantErr error
+	}{
+		{"GEL", GEL, nil},
+		{"usd", USD, nil},
+		{" Eur ", EUR, nil},
+		{"", "", ErrUnsupportedCurrency},
+		{"GBP", "", ErrUnsupportedCurrency},
+		{"GE", "", ErrUnsupportedCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, err := ParseCurrency(tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseCurrency(%q) error = %v, want %v", tt.code, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCurrency(%q) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrencyUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Currency
+		wantErr bool
+	}{
+		{"supported", `"GEL"`, GEL, false},
+		{"lowercase", `"usd"`, USD, false},
+		{"unsupported", `"JPY"`, "", true},
+		{"not a string", `978`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Currency
+			err := json.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v, want error %v", tt.data, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal(%s) = %q, want %q", tt.data, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("unsupported wraps ErrUnsupportedCurrency", func(t *testing.T) {
+		var got Currency
+		if err := json.Unmarshal([]byte(`"JPY"`), &got); !errors.Is(err, ErrUnsupportedCurrency) {
+			t.Errorf("Unmarshal error = %v, want %v", err, ErrUnsupportedCurrency)
+		}
+	})
+}
+
+func TestMoneyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		money   Money
+		wantErr error
+	}{
+		{"positive", Money{Amount: 1050, Currency: GEL}, nil},
+		{"zero", Money{Amount: 0, Currency: USD}, nil},
+		{"negative", Money{Amount: -1, Currency: EUR}, ErrNegativeAmount},
+		{"unsupported currency", Money{Amount: 100, Currency: "GBP"}, ErrUnsupportedCurrency},
+		{"missing currency", Money{Amount: 100}, ErrUnsupportedCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.money.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	got, err := New(500, GEL)
+	if err != nil {
+		t.Fatalf("New(500, GEL) error = %v", err)
+	}
+	if want := (Money{Amount: 500, Currency: GEL}); got != want {
+		t.Errorf("New(500, GEL) = %+v, want %+v", got, want)
+	}
+
+	if _, err := New(-500, GEL); !errors.Is(err, ErrNegativeAmount) {
+		t.Errorf("New(-500, GEL) error = %v, want %v", err, ErrNegativeAmount)
+	}
+}
+
+func TestMoneyCompare(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Money
+		want    int
+		wantErr error
+	}{
+		{"less", Money{100, GEL}, Money{200, GEL}, -1, nil},
+		{"equal", Money{200, GEL}, Money{200, GEL}, 0, nil},
+		{"greater", Money{300, GEL}, Money{200, GEL}, 1, nil},
+		{"currency mismatch", Money{100, GEL}, Money{100, USD}, 0, ErrCurrencyMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.a.Compare(tt.b)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Compare() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Compare() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{Money{1050, GEL}, "10.50 GEL"},
+		{Money{5, USD}, "0.05 USD"},
+		{Money{0, EUR}, "0.00 EUR"},
+		{Money{-1050, GEL}, "-10.50 GEL"},
+		{Money{42, "XXX"}, "42 XXX"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.money.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,45 +1,239 @@
 package mqtt
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"log"
+	"os"
+	"sync/atomic"
 	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 )
 
-var client mqtt.Client
+const (
+	connectTimeout     = 30 * time.Second
+	disconnectQuiesce  = 250 // Milliseconds to finish in-flight work on disconnect.
+	initialRetryPeriod = time.Second
+)
+
+var ErrNotConnected = errors.New("not connected to the mqtt broker")
+
+// States of the connection to the broker.
+const (
+	StateConnecting   = "connecting"   // Not connected yet.
+	StateConnected    = "connected"    // Connected and subscribed to every route.
+	StateReconnecting = "reconnecting" // The connection was lost and is being restored.
+	StateDisconnected = "disconnected" // Stopped on shutdown.
+)
+
+// Config configures the connection to the MQTT broker.
+type Config struct {
+	BrokerURL string // Such as tcp://localhost:1883, or ssl://broker:8883 for TLS.
+	ClientID  string // Must be unique per instance, the broker keeps the session under it.
+	Username  string
+	Password  string
+	CAFile    string // PEM file of the CA of the broker certificate, the system roots when empty.
+
+	KeepAlive            time.Duration
+	QoS                  byte          // QoS of the subscriptions.
+	MaxReconnectInterval time.Duration // Longest wait between two connection attempts.
+}
+
+// Client is a connection to the MQTT broker that routes the messages of its
+// subscriptions through a Router.
+type Client interface {
+	// Run connects to the broker, retrying with backoff until it succeeds,
+	// and keeps the connection up until ctx is done. It then disconnects
+	// cleanly and returns.
+	Run(ctx context.Context) error
+	// State returns the state of the connection, one of the State constants.
+	State() string
+	// Publish publishes payload on topic at the QoS of the client, not
+	// retained, and waits until the broker took it or ctx is done. It fails
+	// with ErrNotConnected unless the client is connected.
+	Publish(ctx context.Context, topic string, payload []byte) error
+}
+
+type pahoClient struct {
+	config Config
+	router *Router
+	client mqtt.Client
+	state  atomic.Value
+	ctx    atomic.Pointer[context.Context] // The context of Run, the parent of every message context.
+}
+
+// NewClient creates a client for the broker in config. It does not connect
+// until Run is called.
+func NewClient(config Config, router *Router) (Client, error) {
+	if config.BrokerURL == "" || config.ClientID == "" {
+		return nil, errors.New("mqtt broker URL and client ID are required")
+	}
+	if config.QoS > 2 {
+		return nil, fmt.Errorf("invalid mqtt QoS %d", config.QoS)
+	}
+	if config.MaxReconnectInterval < initialRetryPeriod {
+		return nil, fmt.Errorf("mqtt max reconnect interval must be at least %s", initialRetryPeriod)
+	}
+
+	c := &pahoClient{
+		config: config,
+		router: router,
+	}
+	c.state.Store(StateConnecting)
+	background := context.Background()
+	c.ctx.Store(&background)
 
-// Connect connects to the MQTT broker using the given brokerURL and clientID.
-func Connect(brokerURL, clientID string) mqtt.Client {
 	opts := mqtt.NewClientOptions()
-	opts.AddBroker(brokerURL)
-	opts.SetClientID(clientID)
-	opts.SetCleanSession(true)
-	opts.SetConnectTimeout(30 * time.Second)
+	opts.AddBroker(config.BrokerURL)
+	opts.SetClientID(config.ClientID)
+	opts.SetUsername(config.Username)
+	opts.SetPassword(config.Password)
+	opts.SetKeepAlive(config.KeepAlive)
+	opts.SetConnectTimeout(connectTimeout)
+
+	// A persistent session keeps the subscriptions and the QoS 1 and 2
+	// messages published while the client was away on the broker.
+	opts.SetCleanSession(false)
+	opts.SetAutoReconnect(true)
+	opts.SetMaxReconnectInterval(config.MaxReconnectInterval)
+	// Handlers run in their own goroutines so a slow one, waiting on the
+	// database, does not hold up every other lift or block keepalives. They
+	// may therefore see the messages of a lift out of order.
+	opts.SetOrderMatters(false)
 
-	opts.OnConnect = func(c mqtt.Client) {
-		log.Println("[MQTT] Connected to broker")
+	if config.CAFile != "" {
+		tlsConfig, err := loadTLSConfig(config.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		opts.SetTLSConfig(tlsConfig)
 	}
-	opts.OnConnectionLost = func(c mqtt.Client, err error) {
+
+	// Messages the broker delivers from the session before the routes are
+	// subscribed again arrive here.
+	opts.SetDefaultPublishHandler(func(client mqtt.Client, msg mqtt.Message) {
+		router.MessageHandler(c.runContext())(client, msg)
+	})
+	opts.SetOnConnectHandler(c.onConnect)
+	opts.SetConnectionLostHandler(func(client mqtt.Client, err error) {
+		c.state.Store(StateReconnecting)
 		log.Printf("[MQTT] Connection lost: %v", err)
+	})
+	opts.SetReconnectingHandler(func(client mqtt.Client, opts *mqtt.ClientOptions) {
+		log.Printf("[MQTT] Reconnecting to %s", config.BrokerURL)
+	})
+
+	c.client = mqtt.NewClient(opts)
+	return c, nil
+}
+
+func (c *pahoClient) Run(ctx context.Context) error {
+	c.ctx.Store(&ctx)
+
+	retryPeriod := initialRetryPeriod
+	for {
+		token := c.client.Connect()
+		select {
+		case <-token.Done():
+		case <-ctx.Done():
+			// Disconnect aborts the attempt in the background instead of
+			// letting shutdown wait for the connect timeout.
+			c.state.Store(StateDisconnected)
+			c.client.Disconnect(disconnectQuiesce)
+			return nil
+		}
+		if token.Error() == nil {
+			break
+		}
+		log.Printf("[MQTT] Error connecting to %s, retrying in %s: %v", c.config.BrokerURL, retryPeriod, token.Error())
+
+		select {
+		case <-ctx.Done():
+			c.state.Store(StateDisconnected)
+			return nil
+		case <-time.After(retryPeriod):
+		}
+		retryPeriod = min(retryPeriod*2, c.config.MaxReconnectInterval)
+	}
+
+	<-ctx.Done()
+	log.Println("[INFO] graceful shutdown of MQTT client...")
+	c.state.Store(StateDisconnected)
+	c.client.Disconnect(disconnectQuiesce)
+	log.Println("[INFO] MQTT client disconnected.")
+	return nil
+}
+
+func (c *pahoClient) State() string {
+	return c.state.Load().(string)
+}
+
+func (c *pahoClient) Publish(ctx context.Context, topic string, payload []byte) error {
+	if c.State() != StateConnected {
+		return ErrNotConnected
 	}
 
-	client = mqtt.NewClient(opts)
-	if token := client.Connect(); token.Wait() && token.Error() != nil {
-		log.Fatalf("Error connecting to MQTT broker: %v", token.Error())
+	token := c.client.Publish(topic, c.config.QoS, false, payload)
+	select {
+	case <-token.Done():
+		if err := token.Error(); err != nil {
+			return fmt.Errorf("failed to publish to %s: %w", topic, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	return client
 }
 
-// SubscribeHeartbeat subscribes to the heartbeat topic.
-func SubscribeHeartbeat(topic string) {
-	if token := client.Subscribe(topic, 1, heartbeatHandler); token.Wait() && token.Error() != nil {
-		log.Fatalf("Error subscribing to heartbeat topic: %v", token.Error())
+// onConnect subscribes the routes on every connect. The broker keeps the
+// subscriptions of a persistent session, but the client only routes the
+// messages of subscriptions it made itself. Failed subscriptions are retried
+// with backoff while the connection stays up.
+func (c *pahoClient) onConnect(client mqtt.Client) {
+	log.Println("[MQTT] Connected to broker")
+	ctx := c.runContext()
+
+	retryPeriod := initialRetryPeriod
+	for {
+		err := c.router.Subscribe(ctx, client, c.config.QoS)
+		if err == nil {
+			c.state.Store(StateConnected)
+			return
+		}
+		log.Printf("[MQTT] Error subscribing, retrying in %s: %v", retryPeriod, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryPeriod):
+		}
+		if !client.IsConnectionOpen() {
+			return
+		}
+		retryPeriod = min(retryPeriod*2, c.config.MaxReconnectInterval)
 	}
-	log.Printf("Subscribed to heartbeat topic: %s", topic)
 }
 
-func heartbeatHandler(client mqtt.Client, msg mqtt.Message) {
-	// Handle the heartbeat message (for example, log it).
-	log.Printf("Heartbeat received on topic %s: %s", msg.Topic(), msg.Payload())
+func (c *pahoClient) runContext() context.Context {
+	return *c.ctx.Load()
+}
+
+// loadTLSConfig returns a TLS configuration trusting the CAs in caFile.
+func loadTLSConfig(caFile string) (*tls.Config, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mqtt CA file: %w", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(pem) {
+		return nil, errors.New("mqtt CA file holds no PEM certificates")
+	}
+	return &tls.Config{
+		RootCAs:    roots,
+		MinVersion: tls.VersionTLS12,
+	}, nil
 }
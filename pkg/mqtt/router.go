@@ -0,0 +1,238 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+var (
+	ErrInvalidPayload = errors.New("invalid payload")
+	ErrHandlerPanic   = errors.New("message handler panicked")
+)
+
+// Context is the message a handler processes, together with the values the
+// wildcards of the matched pattern took in its topic.
+type Context struct {
+	context.Context
+
+	Topic     string
+	Pattern   string   // The pattern of the route that matched the topic.
+	Wildcards []string // One value per wildcard of the pattern; # takes the rest of the topic.
+	DeviceID  string   // The value of the first + wildcard, such as the lift ID in Lift/+/events/door.
+	Payload   []byte
+}
+
+// Handler processes the messages routed to it. Returning an error rejects the
+// message; it is logged and not redelivered.
+type Handler interface {
+	ProcessMessage(ctx *Context) error
+}
+
+// HandlerFunc adapts a function to a Handler.
+type HandlerFunc func(ctx *Context) error
+
+func (fn HandlerFunc) ProcessMessage(ctx *Context) error {
+	return fn(ctx)
+}
+
+// Validator is implemented by payloads that check their fields once decoded.
+type Validator interface {
+	Validate() error
+}
+
+// JSON returns a handler that decodes the JSON payload of a message into a T,
+// the schema of the topic, and passes it to handle. Payloads that do not
+// decode, or whose T fails validation, are rejected with ErrInvalidPayload.
+func JSON[T any](handle func(ctx *Context, payload T) error) Handler {
+	return HandlerFunc(func(ctx *Context) error {
+		var payload T
+		if err := json.Unmarshal(ctx.Payload, &payload); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+		}
+		if validator, ok := any(payload).(Validator); ok {
+			if err := validator.Validate(); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+			}
+		}
+		return handle(ctx, payload)
+	})
+}
+
+// Router routes messages to handlers by topic pattern, in the spirit of
+// asynq.ServeMux. Patterns are MQTT topic filters; every pattern is
+// subscribed to on its own, so a message is handled by the route whose
+// subscription it arrived on.
+type Router struct {
+	mu     sync.RWMutex
+	routes []route
+}
+
+type route struct {
+	pattern string
+	levels  []string
+	handler Handler
+}
+
+func NewRouter() *Router {
+	return &Router{}
+}
+
+// Handle registers the handler for a topic pattern such as
+// Lift/+/events/door. It panics if the pattern is not a valid topic filter or
+// already has a handler.
+func (r *Router) Handle(pattern string, handler Handler) {
+	if handler == nil {
+		panic("mqtt: nil handler for " + pattern)
+	}
+	levels, err := patternLevels(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("mqtt: invalid pattern %q: %v", pattern, err))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rt := range r.routes {
+		if rt.pattern == pattern {
+			panic("mqtt: multiple registrations for " + pattern)
+		}
+	}
+	r.routes = append(r.routes, route{pattern: pattern, levels: levels, handler: handler})
+}
+
+// HandleFunc registers the handler function for a topic pattern.
+func (r *Router) HandleFunc(pattern string, handler func(ctx *Context) error) {
+	r.Handle(pattern, HandlerFunc(handler))
+}
+
+// Subscribe subscribes the client to every registered pattern at qos.
+// Messages are processed with ctx as their parent context.
+func (r *Router) Subscribe(ctx context.Context, client mqtt.Client, qos byte) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, rt := range r.routes {
+		onMessage := func(client mqtt.Client, msg mqtt.Message) {
+			r.dispatch(ctx, rt, msg)
+		}
+		if token := client.Subscribe(rt.pattern, qos, onMessage); token.Wait() && token.Error() != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", rt.pattern, token.Error())
+		}
+		log.Printf("Subscribed to topic: %s", rt.pattern)
+	}
+	return nil
+}
+
+// MessageHandler returns a handler routing each message to the first
+// registered route whose pattern matches its topic, for messages that arrive
+// outside of a subscription made by Subscribe.
+func (r *Router) MessageHandler(ctx context.Context) mqtt.MessageHandler {
+	return func(client mqtt.Client, msg mqtt.Message) {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+
+		for _, rt := range r.routes {
+			if _, ok := matchTopic(rt.levels, msg.Topic()); ok {
+				r.dispatch(ctx, rt, msg)
+				return
+			}
+		}
+		log.Printf("[MQTT] No route for message on topic %s", msg.Topic())
+	}
+}
+
+func (r *Router) dispatch(ctx context.Context, rt route, msg mqtt.Message) {
+	wildcards, ok := matchTopic(rt.levels, msg.Topic())
+	if !ok {
+		log.Printf("[MQTT] Message on topic %s does not match %s", msg.Topic(), rt.pattern)
+		return
+	}
+
+	messageCtx := &Context{
+		Context:   ctx,
+		Topic:     msg.Topic(),
+		Pattern:   rt.pattern,
+		Wildcards: wildcards,
+		DeviceID:  firstSingleLevelWildcard(rt.levels, wildcards),
+		Payload:   msg.Payload(),
+	}
+	if err := process(rt.handler, messageCtx); err != nil {
+		log.Printf("[MQTT] Message on topic %s rejected: %v", msg.Topic(), err)
+	}
+}
+
+// process runs a handler, turning a panic into ErrHandlerPanic so one bad
+// handler cannot take down the client.
+func process(handler Handler, ctx *Context) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("[MQTT] Handler for %s panicked: %v\n%s", ctx.Pattern, p, debug.Stack())
+			err = fmt.Errorf("%w: %v", ErrHandlerPanic, p)
+		}
+	}()
+	return handler.ProcessMessage(ctx)
+}
+
+// patternLevels splits a topic filter into its levels. A + wildcard must take
+// up a whole level and a # wildcard the whole last level.
+func patternLevels(pattern string) ([]string, error) {
+	if pattern == "" {
+		return nil, errors.New("empty pattern")
+	}
+	levels := strings.Split(pattern, "/")
+	for i, level := range levels {
+		if strings.Contains(level, "#") && (level != "#" || i != len(levels)-1) {
+			return nil, errors.New("# must be the last level on its own")
+		}
+		if strings.Contains(level, "+") && level != "+" {
+			return nil, errors.New("+ must be a level on its own")
+		}
+	}
+	return levels, nil
+}
+
+// matchTopic matches a topic against the levels of a pattern and returns the
+// values of its wildcards. A + wildcard does not match an empty level, since
+// handlers take its value as an ID.
+func matchTopic(levels []string, topic string) ([]string, bool) {
+	topicLevels := strings.Split(topic, "/")
+	var wildcards []string
+	for i, level := range levels {
+		switch {
+		case level == "#":
+			return append(wildcards, strings.Join(topicLevels[i:], "/")), true
+		case i >= len(topicLevels):
+			return nil, false
+		case level == "+":
+			if topicLevels[i] == "" {
+				return nil, false
+			}
+			wildcards = append(wildcards, topicLevels[i])
+		case level != topicLevels[i]:
+			return nil, false
+		}
+	}
+	if len(topicLevels) != len(levels) {
+		return nil, false
+	}
+	return wildcards, true
+}
+
+// firstSingleLevelWildcard returns the value of the first + wildcard of a
+// pattern, or "" when it has none. A # wildcard can only come last, so the
+// first + is always the first wildcard.
+func firstSingleLevelWildcard(levels, wildcards []string) string {
+	for _, level := range levels {
+		if level == "+" {
+			return wildcards[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+package mqtt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchTopic(t *testing.T) {
+	tests := []struct {
+		pattern       string
+		topic         string
+		wantMatch     bool
+		wantWildcards []string
+	}{
+		{"Lift/+/events/heartbeat", "Lift/L-17/events/heartbeat", true, []string{"L-17"}},
+		{"Lift/+/events/heartbeat", "Lift/L-17/events/fault", false, nil},
+		{"Lift/+/events/heartbeat", "Lift/L-17/events", false, nil},
+		{"Lift/+/events/heartbeat", "Lift/L-17/events/heartbeat/extra", false, nil},
+		{"Lift/+/events/heartbeat", "Lift//events/heartbeat", false, nil},
+		{"Lift/+/+/reply", "Lift/L-17//reply", false, nil},
+		{"+", "", false, nil},
+		{"Lift/+/+/reply", "Lift/L-17/commands/reply", true, []string{"L-17", "commands"}},
+		{"Lift/+/#", "Lift/L-17/events/fault", true, []string{"L-17", "events/fault"}},
+		{"Lift/+/#", "Lift/L-17", true, []string{"L-17", ""}},
+		{"Lift/+/#", "Lift", false, nil},
+		{"#", "Lift/L-17/events", true, []string{"Lift/L-17/events"}},
+		{"Lift/status", "Lift/status", true, nil},
+		{"Lift/status", "lift/status", false, nil},
+		{"Lift/status", "Lift/status/", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.topic, func(t *testing.T) {
+			levels, err := patternLevels(tt.pattern)
+			if err != nil {
+				t.Fatalf("patternLevels(%q) error = %v", tt.pattern, err)
+			}
+			wildcards, ok := matchTopic(levels, tt.topic)
+			if ok != tt.wantMatch {
+				t.Fatalf("matchTopic(%q, %q) matched = %v, want %v", tt.pattern, tt.topic, ok, tt.wantMatch)
+			}
+			if !reflect.DeepEqual(wildcards, tt.wantWildcards) {
+				t.Errorf("matchTopic(%q, %q) wildcards = %q, want %q", tt.pattern, tt.topic, wildcards, tt.wantWildcards)
+			}
+		})
+	}
+}
+
+func TestPatternLevels(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantErr bool
+	}{
+		{"Lift/+/events/heartbeat", false},
+		{"Lift/#", false},
+		{"#", false},
+		{"+", false},
+		{"", true},
+		{"Lift/#/events", true},
+		{"Lift/events#", true},
+		{"Lift/L+/events", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			_, err := patternLevels(tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("patternLevels(%q) error = %v, want error %v", tt.pattern, err, tt.wantErr)
+			}
+		})
+	}
+}
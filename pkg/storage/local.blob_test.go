@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalBlobRejectsEscapingKeys(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "blobs")
+	blob, err := NewLocalBlob(root)
+	if err != nil {
+		t.Fatalf("NewLocalBlob() error = %v", err)
+	}
+
+	// A file next to the root that an escaping key would reach.
+	outside := filepath.Join(filepath.Dir(root), "secret")
+	if err := os.WriteFile(outside, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{
+		"",
+		"../secret",
+		"tasks/../../secret",
+		"tasks/..",
+		"/etc/passwd",
+		`tasks\..\..\secret`,
+		"tasks//file",
+		"tasks/./file",
+		"tasks/",
+		".",
+	}
+
+	for _, key := range keys {
+		t.Run(key, func(t *testing.T) {
+			ctx := context.Background()
+			if err := blob.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("Put(%q) error = %v, want %v", key, err, ErrInvalidKey)
+			}
+			if _, err := blob.Get(ctx, key); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("Get(%q) error = %v, want %v", key, err, ErrInvalidKey)
+			}
+			if err := blob.Delete(ctx, key); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("Delete(%q) error = %v, want %v", key, err, ErrInvalidKey)
+			}
+		})
+	}
+
+	if content, err := os.ReadFile(outside); err != nil || string(content) != "secret" {
+		t.Errorf("file outside the root changed: %q, %v", content, err)
+	}
+}
+
+func TestLocalBlobRoundTrip(t *testing.T) {
+	blob, err := NewLocalBlob(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalBlob() error = %v", err)
+	}
+	ctx := context.Background()
+	key := "tasks/7/attachments/report.pdf"
+
+	if err := blob.Put(ctx, key, strings.NewReader("content"), 7, "application/pdf"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	r, err := blob.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	content, err := io.ReadAll(r)
+	r.Close()
+	if err != nil || string(content) != "content" {
+		t.Errorf("Get() = %q, %v, want %q", content, err, "content")
+	}
+
+	if err := blob.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := blob.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want %v", err, ErrNotFound)
+	}
+	if err := blob.Delete(ctx, key); err != nil {
+		t.Errorf("Delete() of a missing object error = %v, want nil", err)
+	}
+}
+
+func TestLocalBlobPutRejectsShortObject(t *testing.T) {
+	blob, err := NewLocalBlob(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalBlob() error = %v", err)
+	}
+	ctx := context.Background()
+
+	if err := blob.Put(ctx, "short", strings.NewReader("abc"), 10, "text/plain"); err == nil {
+		t.Fatal("Put() of fewer bytes than size succeeded")
+	}
+	if _, err := blob.Get(ctx, "short"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() of a failed Put() error = %v, want %v", err, ErrNotFound)
+	}
+}
@@ -0,0 +1,19 @@
+package token
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Denylist keeps track of tokens that were revoked before they expired.
+type Denylist interface {
+	// RevokeToken revokes a single token until it expires.
+	RevokeToken(ctx context.Context, payload *Payload) error
+
+	// RevokeUser revokes every token issued to the user up to now.
+	RevokeUser(ctx context.Context, userID uuid.UUID) error
+
+	// IsRevoked checks if the token was revoked, either on its own or together with all tokens of its user.
+	IsRevoked(ctx context.Context, payload *Payload) (bool, error)
+}
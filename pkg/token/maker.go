@@ -13,4 +13,10 @@ type Maker interface {
 
 	// VerifyToken checks if the token is valid or not
 	VerifyToken(token string) (*Payload, error)
+
+	// CreateRefreshToken creates a new refresh token for a specific user and duration
+	CreateRefreshToken(userID uuid.UUID, duration time.Duration) (*Payload, error)
+
+	// VerifyRefreshToken checks if the refresh token is valid or not
+	VerifyRefreshToken(token string) (*Payload, error)
 }
@@ -41,7 +41,51 @@ func (maker *PasetoMaker) CreateToken(userID uuid.UUID, email, role string, dura
 		log.Printf("[ERROR] Failed to create payload: %v", err)
 		return payload, err
 	}
-	// Encrypt the payload into a PASETO token.
+	return maker.encrypt(payload)
+}
+
+// CreateRefreshToken generates a new refresh token for a given user with a specific duration.
+// Refresh tokens carry no role and are rejected by VerifyToken.
+func (maker *PasetoMaker) CreateRefreshToken(userID uuid.UUID, duration time.Duration) (*Payload, error) {
+	payload, err := NewPayload(userID, "", "", duration)
+	if err != nil {
+		log.Printf("[ERROR] Failed to create payload: %v", err)
+		return payload, err
+	}
+	payload.Type = TypeRefresh
+	return maker.encrypt(payload)
+}
+
+// VerifyToken decrypts and validates a given access token string.
+// Returns the payload if valid, or an error if the token is invalid or expired.
+func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
+	payload, err := maker.decrypt(token)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Type == TypeRefresh {
+		log.Printf("[ERROR] Refresh token used as access token: ID=%s", payload.TokenID)
+		return nil, ErrInvalidToken
+	}
+	return payload, nil
+}
+
+// VerifyRefreshToken decrypts and validates a given refresh token string.
+// Returns the payload if valid, or an error if the token is invalid, expired or not a refresh token.
+func (maker *PasetoMaker) VerifyRefreshToken(token string) (*Payload, error) {
+	payload, err := maker.decrypt(token)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Type != TypeRefresh {
+		log.Printf("[ERROR] Access token used as refresh token: ID=%s", payload.TokenID)
+		return nil, ErrInvalidToken
+	}
+	return payload, nil
+}
+
+// encrypt encrypts the payload into a PASETO token and stores it on the payload.
+func (maker *PasetoMaker) encrypt(payload *Payload) (*Payload, error) {
 	token, err := maker.paseto.Encrypt(maker.symmetricKey, payload, nil)
 	if err != nil {
 		log.Printf("[ERROR] Failed to encrypt token: %v", err)
@@ -53,9 +97,8 @@ func (maker *PasetoMaker) CreateToken(userID uuid.UUID, email, role string, dura
 	return payload, err
 }
 
-// VerifyToken decrypts and validates a given token string.
-// Returns the payload if valid, or an error if the token is invalid or expired.
-func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
+// decrypt decrypts a token string and validates the resulting payload.
+func (maker *PasetoMaker) decrypt(token string) (*Payload, error) {
 	payload := &Payload{}
 
 	// Decrypt the token and populate the payload.
@@ -16,10 +16,17 @@ var (
 	ErrExpiredToken = errors.New("token has expired") // Returned when a token has exceeded its expiration time.
 )
 
+// Token types stored in the payload so that refresh tokens cannot be used as access tokens.
+const (
+	TypeAccess  = "access"  // Short-lived token sent with every API request.
+	TypeRefresh = "refresh" // Long-lived token exchanged for new access tokens.
+)
+
 // Payload contains the payload data of the token, including metadata such as expiration and issuance times.
 type Payload struct {
 	UserID  uuid.UUID `json:"user_id"`      // Unique identifier
 	TokenID uuid.UUID `json:"token_id"`     // Unique identifier for the token.
+	Type    string    `json:"type"`         // Type of the token, TypeAccess or TypeRefresh.
 	Token   string    `json:"access_token"` // Access token associated with the token.
 	// Email     string    `json:"email"`      // Email associated with the token.
 	Role      string    `json:"role"`       // Role associated with the token (e.g., admin, user).
@@ -41,6 +48,7 @@ func NewPayload(userID uuid.UUID, email, role string, duration time.Duration) (*
 	payload := &Payload{
 		UserID:  userID,
 		TokenID: tokenID,
+		Type:    TypeAccess,
 		Token:   "",
 		// Email:     email,
 		Role:      role,
@@ -0,0 +1,84 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const (
+	revokedTokenKeyPrefix = "veemon:revoked_token:" // Followed by the token ID.
+	revokedUserKeyPrefix  = "veemon:revoked_user:"  // Followed by the user ID, holds the revocation time.
+)
+
+// RedisDenylist is a Denylist stored in Redis. Entries expire together with
+// the tokens they revoke, so the denylist does not grow without bound.
+type RedisDenylist struct {
+	client   *redis.Client
+	tokenTTL time.Duration // Longest lifetime of an access token, used to expire per-user entries.
+}
+
+// NewRedisDenylist creates a new RedisDenylist. tokenTTL must be at least as
+// long as the lifetime of the access tokens being checked.
+func NewRedisDenylist(client *redis.Client, tokenTTL time.Duration) Denylist {
+	return &RedisDenylist{
+		client:   client,
+		tokenTTL: tokenTTL,
+	}
+}
+
+// RevokeToken stores the token ID until the token expires.
+func (dl *RedisDenylist) RevokeToken(ctx context.Context, payload *Payload) error {
+	ttl := time.Until(payload.ExpiredAt)
+	if ttl <= 0 {
+		return nil
+	}
+	if err := dl.client.Set(ctx, revokedTokenKeyPrefix+payload.TokenID.String(), 1, ttl).Err(); err != nil {
+		log.Printf("[ERROR] Failed to revoke token: ID=%s, error: %v", payload.TokenID, err)
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
+
+// RevokeUser stores the current time for the user; tokens issued at or
+// before it are considered revoked.
+func (dl *RedisDenylist) RevokeUser(ctx context.Context, userID uuid.UUID) error {
+	revokedAt := strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err := dl.client.Set(ctx, revokedUserKeyPrefix+userID.String(), revokedAt, dl.tokenTTL).Err(); err != nil {
+		log.Printf("[ERROR] Failed to revoke tokens of user: ID=%s, error: %v", userID, err)
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+	return nil
+}
+
+// IsRevoked checks both the token ID and the revocation time of its user.
+func (dl *RedisDenylist) IsRevoked(ctx context.Context, payload *Payload) (bool, error) {
+	values, err := dl.client.MGet(ctx,
+		revokedTokenKeyPrefix+payload.TokenID.String(),
+		revokedUserKeyPrefix+payload.UserID.String(),
+	).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check denylist: %w", err)
+	}
+
+	if values[0] != nil {
+		return true, nil
+	}
+
+	if revokedAt, ok := values[1].(string); ok {
+		nanos, err := strconv.ParseInt(revokedAt, 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid user revocation time %q: %w", revokedAt, err)
+		}
+		if !payload.IssuedAt.After(time.Unix(0, nanos)) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
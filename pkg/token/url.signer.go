@@ -0,0 +1,58 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aead/chacha20poly1305"
+)
+
+// urlSigningKeyLabel separates the URL signing key from the token key it is derived from.
+const urlSigningKeyLabel = "veemon:url-signing"
+
+// URLSigner signs short-lived links, such as download links of private files,
+// so they can be followed without an access token.
+type URLSigner struct {
+	key []byte // Derived from the token symmetric key, never the key itself.
+}
+
+// NewURLSigner creates a URLSigner whose key is derived from the token
+// symmetric key, so no separate secret has to be configured.
+func NewURLSigner(symmetricKey string) (*URLSigner, error) {
+	if len(symmetricKey) != chacha20poly1305.KeySize {
+		return nil, fmt.Errorf("invalid key size: must be exactly %d characters", chacha20poly1305.KeySize)
+	}
+
+	mac := hmac.New(sha256.New, []byte(symmetricKey))
+	mac.Write([]byte(urlSigningKeyLabel))
+	return &URLSigner{key: mac.Sum(nil)}, nil
+}
+
+// Sign returns the hex encoded signature of a link to resource that expires at expiresAt.
+func (s *URLSigner) Sign(resource string, expiresAt time.Time) string {
+	return hex.EncodeToString(s.sign(resource, expiresAt.Unix()))
+}
+
+// Verify checks the signature of a link to resource that expires at the given
+// Unix time. It returns ErrInvalidToken for a wrong signature and
+// ErrExpiredToken once the link has expired.
+func (s *URLSigner) Verify(resource string, expires int64, signature string) error {
+	given, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(given, s.sign(resource, expires)) {
+		return ErrInvalidToken
+	}
+	if time.Now().Unix() > expires {
+		return ErrExpiredToken
+	}
+	return nil
+}
+
+func (s *URLSigner) sign(resource string, expires int64) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(resource + "\n" + strconv.FormatInt(expires, 10)))
+	return mac.Sum(nil)
+}
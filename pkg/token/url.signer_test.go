@@ -0,0 +1,97 @@
+package token
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSymmetricKey = "12345678901234567890123456789012"
+
+func TestNewURLSigner(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{"valid key", testSymmetricKey, false},
+		{"short key", testSymmetricKey[:31], true},
+		{"long key", testSymmetricKey + "3", true},
+		{"empty key", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewURLSigner(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewURLSigner() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestURLSignerVerify(t *testing.T) {
+	signer, err := NewURLSigner(testSymmetricKey)
+	if err != nil {
+		t.Fatalf("NewURLSigner() error = %v", err)
+	}
+	other, err := NewURLSigner(strings.Repeat("k", 32))
+	if err != nil {
+		t.Fatalf("NewURLSigner() error = %v", err)
+	}
+
+	resource := "attachments/3f0c7c1e-6a8e-4a7e-9b1e-2d1f0c9a8b7d"
+	expiresAt := time.Now().Add(5 * time.Minute)
+	expires := expiresAt.Unix()
+	signature := signer.Sign(resource, expiresAt)
+
+	expiredAt := time.Now().Add(-time.Minute)
+	expiredSignature := signer.Sign(resource, expiredAt)
+
+	tests := []struct {
+		name      string
+		resource  string
+		expires   int64
+		signature string
+		wantErr   error
+	}{
+		{"valid link", resource, expires, signature, nil},
+		{"other resource", resource + "x", expires, signature, ErrInvalidToken},
+		{"extended expiry", resource, expires + 3600, signature, ErrInvalidToken},
+		{"signed with another key", resource, expires, other.Sign(resource, expiresAt), ErrInvalidToken},
+		{"tampered signature", resource, expires, flipLastHexDigit(signature), ErrInvalidToken},
+		{"truncated signature", resource, expires, signature[:len(signature)-2], ErrInvalidToken},
+		{"not hex", resource, expires, "zz" + signature[2:], ErrInvalidToken},
+		{"empty signature", resource, expires, "", ErrInvalidToken},
+		{"expired link", resource, expiredAt.Unix(), expiredSignature, ErrExpiredToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := signer.Verify(tt.resource, tt.expires, tt.signature); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestURLSignerKeyIsDerived(t *testing.T) {
+	signer, err := NewURLSigner(testSymmetricKey)
+	if err != nil {
+		t.Fatalf("NewURLSigner() error = %v", err)
+	}
+	if string(signer.key) == testSymmetricKey {
+		t.Error("URL signing key is the token symmetric key")
+	}
+}
+
+// flipLastHexDigit returns the signature with its last hex digit changed.
+func flipLastHexDigit(signature string) string {
+	last := signature[len(signature)-1]
+	replacement := byte('0')
+	if last == '0' {
+		replacement = '1'
+	}
+	return signature[:len(signature)-1] + string(replacement)
+}
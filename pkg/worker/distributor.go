@@ -12,6 +12,16 @@ type TaskDistributor interface {
 		payload *PayloadSendVerifyEmail,
 		opts ...asynq.Option,
 	) error
+	DistributeTaskSendResetPassword(
+		ctx context.Context,
+		payload *PayloadSendResetPassword,
+		opts ...asynq.Option,
+	) error
+	DistributeTaskSendIncidentNotification(
+		ctx context.Context,
+		payload *PayloadSendIncidentNotification,
+		opts ...asynq.Option,
+	) error
 }
 
 type RedisTaskDistributor struct {
@@ -19,15 +19,19 @@ type TaskProcessor interface {
 	Start() error
 	Shutdown()
 	ProcessTaskSendVerifyEmail(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendResetPassword(ctx context.Context, task *asynq.Task) error
+	ProcessTaskCreateMetricPartitions(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendIncidentNotification(ctx context.Context, task *asynq.Task) error
 }
 
 type RedisTaskProcessor struct {
-	server *asynq.Server
-	db     *db.Queries
-	mailer mail.EmailSender
+	server    *asynq.Server
+	db        *db.Store
+	mailer    mail.EmailSender
+	publicURL string // Base URL of the API used in links sent by email.
 }
 
-func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, db *db.Queries, mailer mail.EmailSender) TaskProcessor {
+func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, db *db.Store, mailer mail.EmailSender, publicURL string) TaskProcessor {
 	logger := NewLogger()
 	redis.SetLogger(logger)
 
@@ -48,9 +52,10 @@ func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, db *db.Queries, mailer
 	)
 
 	redisTaskProcessor := &RedisTaskProcessor{
-		server: server,
-		db:     db,
-		mailer: mailer,
+		server:    server,
+		db:        db,
+		mailer:    mailer,
+		publicURL: publicURL,
 	}
 
 	return redisTaskProcessor
@@ -59,6 +64,9 @@ func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, db *db.Queries, mailer
 func (rtp *RedisTaskProcessor) Start() error {
 	mux := asynq.NewServeMux()
 	mux.HandleFunc(TaskSendVerifyEmail, rtp.ProcessTaskSendVerifyEmail)
+	mux.HandleFunc(TaskSendResetPassword, rtp.ProcessTaskSendResetPassword)
+	mux.HandleFunc(TaskCreateMetricPartitions, rtp.ProcessTaskCreateMetricPartitions)
+	mux.HandleFunc(TaskSendIncidentNotification, rtp.ProcessTaskSendIncidentNotification)
 
 	return rtp.server.Start(mux)
 }
@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// TaskScheduler enqueues the periodic maintenance tasks.
+type TaskScheduler interface {
+	Start() error
+	Shutdown()
+}
+
+type RedisTaskScheduler struct {
+	scheduler *asynq.Scheduler
+}
+
+func NewRedisTaskScheduler(redisOpt asynq.RedisClientOpt) (TaskScheduler, error) {
+	scheduler := asynq.NewScheduler(redisOpt, &asynq.SchedulerOpts{
+		Logger:   NewLogger(),
+		Location: time.UTC,
+	})
+
+	// Every instance runs a scheduler; Unique keeps the daily run from being
+	// enqueued once per instance.
+	_, err := scheduler.Register(
+		"@daily",
+		asynq.NewTask(TaskCreateMetricPartitions, nil),
+		asynq.Queue(QueueDefault),
+		asynq.Unique(time.Hour),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RedisTaskScheduler{scheduler: scheduler}, nil
+}
+
+func (rts *RedisTaskScheduler) Start() error {
+	return rts.scheduler.Start()
+}
+
+func (rts *RedisTaskScheduler) Shutdown() {
+	rts.scheduler.Shutdown()
+}
@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskCreateMetricPartitions = "task:create_metric_partitions"
+
+// metricPartitionsAhead is how many months after the current one get a metric
+// partition, so a missed run of the task does not reject samples.
+const metricPartitionsAhead = 2
+
+// ProcessTaskCreateMetricPartitions creates the monthly device metric
+// partitions for the previous month, the current month and the months ahead.
+// The previous month keeps samples dated shortly before a month starts
+// storable. Partitions that already exist are left alone.
+func (processor *RedisTaskProcessor) ProcessTaskCreateMetricPartitions(ctx context.Context, task *asynq.Task) error {
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	for i := -1; i <= metricPartitionsAhead; i++ {
+		month := monthStart.AddDate(0, i, 0)
+		err := processor.db.CreateDeviceMetricsPartition(ctx, pgtype.Date{Time: month, Valid: true})
+		if err != nil {
+			return fmt.Errorf("failed to create metric partition for %s: %w", month.Format("2006-01"), err)
+		}
+	}
+
+	// Log the successful processing of the task.
+	log.Info().
+		Str("type", task.Type()).
+		Str("from", monthStart.AddDate(0, -1, 0).Format("2006-01")).
+		Int("months", metricPartitionsAhead+2).
+		Msg("processed task")
+	return nil
+}
@@ -0,0 +1,160 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
+)
+
+const TaskSendIncidentNotification = "task:send_incident_notification"
+
+type (
+	// Define the payload structure for incident notifications. Level is the
+	// escalation level the notification is sent for; the contacts of the lift
+	// at that level are notified, since lower levels were notified before.
+	// Raised marks the notification of an incident that became critical, which
+	// goes to the contacts of all levels up to Level.
+	PayloadSendIncidentNotification struct {
+		IncidentID uuid.UUID `json:"incident_id"`
+		Level      int       `json:"level"`
+		Raised     bool      `json:"raised"`
+	}
+)
+
+// DistributeTaskSendIncidentNotification enqueues a task to notify the contacts of a lift of an incident.
+func (distributor *RedisTaskDistributor) DistributeTaskSendIncidentNotification(
+	ctx context.Context,
+	payload *PayloadSendIncidentNotification,
+	opts ...asynq.Option,
+) error {
+	// Marshal the payload to JSON.
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	// Create a new Asynq task.
+	task := asynq.NewTask(TaskSendIncidentNotification, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	// Log the successful enqueue of the task.
+	log.Info().
+		Str("type", task.Type()).
+		Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).
+		Int("max_retry", info.MaxRetry).
+		Msg("enqueued task")
+	return nil
+}
+
+// ProcessTaskSendIncidentNotification processes a task to email the contacts
+// of a lift about one of its incidents. Incidents resolved, or escalations
+// acknowledged, by the time the task runs are not notified. Acknowledged
+// incidents becoming critical are.
+func (processor *RedisTaskProcessor) ProcessTaskSendIncidentNotification(ctx context.Context, task *asynq.Task) error {
+	// Unmarshal the payload from the task.
+	var payload PayloadSendIncidentNotification
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		// Skip retrying if the payload is invalid.
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	incident, err := processor.db.GetIncident(ctx, payload.IncidentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("incident not found: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get incident: %w", err)
+	}
+	if incident.Status == "resolved" || (payload.Level > 0 && !payload.Raised && incident.Status != "open") {
+		log.Info().
+			Str("type", task.Type()).
+			Str("incident_id", incident.ID.String()).
+			Str("status", incident.Status).
+			Msg("skipped notification of handled incident")
+		return nil
+	}
+
+	device, err := processor.db.GetDevice(ctx, incident.DeviceID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("device not found: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get device: %w", err)
+	}
+
+	minLevel := payload.Level
+	if payload.Raised {
+		minLevel = 0
+	}
+	recipients, err := processor.db.ListIncidentRecipients(ctx, db.ListIncidentRecipientsParams{
+		DeviceID: incident.DeviceID,
+		MinLevel: int32(minLevel),
+		MaxLevel: int32(payload.Level),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list incident recipients: %w", err)
+	}
+	if len(recipients) == 0 {
+		log.Warn().
+			Str("type", task.Type()).
+			Str("incident_id", incident.ID.String()).
+			Str("device_id", incident.DeviceID).
+			Int("level", payload.Level).
+			Msg("no contacts to notify of incident")
+		return nil
+	}
+
+	// Prepare email content.
+	subject := fmt.Sprintf("[%s] Lift %s: %s incident %s", incident.Severity, device.ID, incident.Kind, incident.Code)
+	switch {
+	case payload.Raised:
+		subject = fmt.Sprintf("Now critical: %s", subject)
+	case payload.Level > 0:
+		subject = fmt.Sprintf("Escalated (level %d): %s", payload.Level, subject)
+	}
+	content := fmt.Sprintf(`Hello,<br/>
+	Lift <b>%s</b> in %s, %s has an open incident.<br/>
+	Kind: %s<br/>
+	Code: %s<br/>
+	Severity: %s<br/>
+	Summary: %s<br/>
+	Occurrences: %d, last at %s<br/>
+	Please acknowledge the incident in Veemon once someone is taking care of it.<br/>`,
+		html.EscapeString(device.ID),
+		html.EscapeString(device.Building),
+		html.EscapeString(device.Address),
+		incident.Kind,
+		html.EscapeString(incident.Code),
+		incident.Severity,
+		html.EscapeString(incident.Summary),
+		incident.Occurrences,
+		incident.LastSeenAt.UTC().Format("2006-01-02 15:04:05 MST"),
+	)
+
+	// Send the incident notification email.
+	err = processor.mailer.SendEmail(ctx, recipients, subject, content)
+	if err != nil {
+		return fmt.Errorf("failed to send incident notification email: %w", err)
+	}
+
+	// Log the successful processing of the task.
+	log.Info().
+		Str("type", task.Type()).
+		Str("incident_id", incident.ID.String()).
+		Int("level", payload.Level).
+		Int("recipients", len(recipients)).
+		Msg("processed task")
+	return nil
+}
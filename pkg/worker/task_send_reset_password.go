@@ -0,0 +1,107 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
+	"github.com/vgrigalashvili/veemon/pkg/helper"
+)
+
+const TaskSendResetPassword = "task:send_reset_password"
+
+type (
+	// Define the payload structure for sending password reset emails.
+	// Welcome is set for accounts created without a password, whose owners
+	// use the reset code to choose their first password.
+	PayloadSendResetPassword struct {
+		UserID  uuid.UUID `json:"user_id"`
+		Email   string    `json:"email"`
+		Welcome bool      `json:"welcome"`
+	}
+)
+
+// DistributeTaskSendResetPassword enqueues a task to send a password reset email.
+func (distributor *RedisTaskDistributor) DistributeTaskSendResetPassword(
+	ctx context.Context,
+	payload *PayloadSendResetPassword,
+	opts ...asynq.Option,
+) error {
+	// Marshal the payload to JSON.
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	// Create a new Asynq task.
+	task := asynq.NewTask(TaskSendResetPassword, jsonPayload, opts...)
+	info, err := distributor.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	// Log the successful enqueue of the task.
+	log.Info().
+		Str("type", task.Type()).
+		Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).
+		Int("max_retry", info.MaxRetry).
+		Msg("enqueued task")
+	return nil
+}
+
+// ProcessTaskSendResetPassword processes a task to send a password reset email.
+func (processor *RedisTaskProcessor) ProcessTaskSendResetPassword(ctx context.Context, task *asynq.Task) error {
+	// Unmarshal the payload from the task.
+	var payload PayloadSendResetPassword
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		// Skip retrying if the payload is invalid.
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	// Only the hash of the reset token is stored; the token itself is only in the email.
+	resetToken, err := helper.GenerateToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate reset token: %w", err)
+	}
+	_, err = processor.db.CreatePasswordReset(ctx, db.CreatePasswordResetParams{
+		UserID:    payload.UserID,
+		TokenHash: helper.HashToken(resetToken),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create password reset: %w", err)
+	}
+
+	// Prepare email content.
+	subject := "Reset your Veemon password"
+	content := fmt.Sprintf(`Hello,<br/>
+	We received a request to reset your password.<br/>
+	Use the following code to choose a new password, it can be used once and expires in one hour:<br/>
+	<b>%s</b><br/>
+	If you did not request a password reset, you can ignore this email.<br/>`, resetToken)
+	if payload.Welcome {
+		subject = "Welcome to Veemon"
+		content = fmt.Sprintf(`Hello,<br/>
+	An account has been created for you.<br/>
+	Use the following code to choose your password, it can be used once and expires in one hour:<br/>
+	<b>%s</b><br/>`, resetToken)
+	}
+	to := payload.Email
+
+	// Send the password reset email.
+	err = processor.mailer.SendEmail(ctx, []string{to}, subject, content)
+	if err != nil {
+		return fmt.Errorf("failed to send reset password email: %w", err)
+	}
+
+	// Log the successful processing of the task.
+	log.Info().
+		Str("type", task.Type()).
+		Str("email", payload.Email).
+		Msg("processed task")
+	return nil
+}
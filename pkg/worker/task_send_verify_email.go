@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 	"github.com/rs/zerolog/log"
+	db "github.com/vgrigalashvili/veemon/internal/repository/sqlc"
 	"github.com/vgrigalashvili/veemon/pkg/helper"
 )
 
@@ -15,13 +17,15 @@ const TaskSendVerifyEmail = "task:send_verify_email"
 type (
 	// Define parameters for creating a verify email.
 	CreateVerifyEmailParams struct {
-		Email      string `json:"email"`
-		SecretCode string `json:"secret_code"`
+		UserID     uuid.UUID `json:"user_id"`
+		Email      string    `json:"email"`
+		SecretCode string    `json:"secret_code"`
 	}
 
 	// Define the payload structure for sending verification emails.
 	PayloadSendVerifyEmail struct {
-		Email string `json:"email"`
+		UserID uuid.UUID `json:"user_id"`
+		Email  string    `json:"email"`
 	}
 )
 
@@ -64,9 +68,14 @@ func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Cont
 	}
 
 	// Create a verification email entry in the database.
+	secretCode, err := helper.GenerateToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate secret code: %w", err)
+	}
 	verifyEmail, err := processor.createVerifyEmail(ctx, CreateVerifyEmailParams{
+		UserID:     payload.UserID,
 		Email:      payload.Email,
-		SecretCode: helper.RandomString(32), // Generate a random secret code.
+		SecretCode: secretCode,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create verify email: %w", err)
@@ -74,9 +83,8 @@ func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Cont
 
 	// Prepare email content.
 	subject := "Welcome to Veemon"
-	// TODO: Use an environment variable for the frontend URL.
-	verifyUrl := fmt.Sprintf("http://localhost:8080/v1/verify_email?email_id=%d&secret_code=%s",
-		verifyEmail.ID, verifyEmail.SecretCode)
+	verifyUrl := fmt.Sprintf("%s/api/auth/verify-email?email_id=%d&secret_code=%s",
+		processor.publicURL, verifyEmail.ID, secretCode)
 	content := fmt.Sprintf(`Hello,<br/>
 	Thank you for registering with us!<br/>
 	Please <a href="%s">click here</a> to verify your email address.<br/>`, verifyUrl)
@@ -97,18 +105,16 @@ func (processor *RedisTaskProcessor) ProcessTaskSendVerifyEmail(ctx context.Cont
 	return nil
 }
 
-// createVerifyEmail is a placeholder for the actual implementation of creating a verification email entry.
-func (processor *RedisTaskProcessor) createVerifyEmail(ctx context.Context, params CreateVerifyEmailParams) (*VerifyEmail, error) {
-	// Implementation should interact with the database to create the email verification record.
-	// Replace the below code with your database logic.
-	return &VerifyEmail{
-		ID:         1,                 // Example ID
-		SecretCode: params.SecretCode, // Generated secret code
-	}, nil
-}
-
-// VerifyEmail represents the verification email record (simplified example).
-type VerifyEmail struct {
-	ID         int    `json:"id"`
-	SecretCode string `json:"secret_code"`
+// createVerifyEmail stores a verification record for the email. Only a hash
+// of the secret code is stored; the code itself is sent to the user.
+func (processor *RedisTaskProcessor) createVerifyEmail(ctx context.Context, params CreateVerifyEmailParams) (*db.VerifyEmail, error) {
+	verifyEmail, err := processor.db.CreateVerifyEmail(ctx, db.CreateVerifyEmailParams{
+		UserID:         params.UserID,
+		Email:          params.Email,
+		SecretCodeHash: helper.HashToken(params.SecretCode),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &verifyEmail, nil
 }